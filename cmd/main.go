@@ -5,15 +5,20 @@ import (
 	"TODO_API/internal/app/handler"
 	"TODO_API/internal/app/middleware"
 	"TODO_API/internal/repository"
+	"TODO_API/internal/search"
 	"TODO_API/internal/service"
+	"TODO_API/internal/worker"
 	"TODO_API/pkg/database"
 	"TODO_API/pkg/jwt"
 	"TODO_API/pkg/logger"
+	"TODO_API/pkg/storage"
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -25,7 +30,11 @@ import (
 )
 
 // 配置路由
-func setupRouter(r *gin.Engine, h *handler.Healther, a *handler.AuthHandler, u *handler.UserHandeler, t *handler.TodoHandler) {
+func setupRouter(r *gin.Engine, h *handler.Healther, a *handler.AuthHandler, u *handler.UserHandeler, t *handler.TodoHandler, tg *handler.TagHandler,
+	rm *handler.ReminderHandler, p *handler.ProjectHandler, sh *handler.ShareHandler,
+	cm *handler.CommentHandler, at *handler.AttachmentHandler, cal *handler.CalendarHandler,
+	ap *handler.AppPasswordHandler, dav *handler.CalDAVHandler, davAuth middleware.BasicAuthenticator,
+	st *handler.StatusHandler) {
 	// 添加Swagger文档路由（仅在开发环境）
 	if config.GlobalConfig.App.Environment == "development" {
 		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -47,13 +56,78 @@ func setupRouter(r *gin.Engine, h *handler.Healther, a *handler.AuthHandler, u *
 				"POST   /api/auth/login - 用户登录",
 				"POST   /api/auth/refresh - 刷新令牌",
 				"GET    /api/users/me - 获取当前用户(需认证)",
+				"GET    /api/users/me/app-passwords - 获取应用专用密码列表(需认证)",
+				"POST   /api/users/me/app-passwords - 创建应用专用密码(需认证)",
+				"DELETE /api/users/me/app-passwords/:id - 吊销应用专用密码(需认证)",
 				"GET    /api/todos - 获取待办事项列表(需认证)",
+				"GET    /api/todos/search - 全文搜索待办事项(需认证)",
+				"GET    /api/todos/stats - 获取统计分析(需认证)",
+				"GET    /api/todos/export?format=json|csv|md - 导出待办事项(需认证)",
+				"POST   /api/todos/import?dry_run=true - 导入待办事项(需认证)",
+				"POST   /api/todos/import/:source - 从 todoist|trello|mstodo 迁移(需认证)",
+				"GET    /api/todos/todotxt - 导出 todo.txt(需认证)",
+				"POST   /api/todos/todotxt - 上传 todo.txt 同步(需认证)",
 				"POST   /api/todos - 创建待办事项(需认证)",
 				"GET    /api/todos/:id - 获取待办事项详情(需认证)",
 				"PUT    /api/todos/:id - 更新待办事项(需认证)",
+				"PATCH  /api/todos/:id - 局部更新待办事项，支持合并补丁与 JSON Patch(需认证)",
 				"DELETE /api/todos/:id - 删除待办事项(需认证)",
 				"PUT    /api/todos/:id/status - 更新状态(需认证)",
+				"PUT    /api/todos/:id/move - 在看板中移动(需认证)",
+				"GET    /api/board?project= - 获取看板(需认证)",
 				"PUT    /api/todos/batch/status - 批量更新状态(需认证)",
+				"POST   /api/todos/batch - 批量修改或删除(需认证)",
+				"GET    /api/todos/trash - 获取回收站列表(需认证)",
+				"POST   /api/todos/:id/restore - 恢复待办事项(需认证)",
+				"DELETE /api/todos/:id/purge - 彻底删除待办事项(需认证)",
+				"POST   /api/todos/:id/tags - 添加标签(需认证)",
+				"DELETE /api/todos/:id/tags/:tag_id - 移除标签(需认证)",
+				"GET    /api/todos/:id/subtasks - 获取子任务列表(需认证)",
+				"POST   /api/todos/:id/subtasks - 创建子任务(需认证)",
+				"PUT    /api/todos/:id/subtasks/order - 调整子任务顺序(需认证)",
+				"GET    /api/todos/:id/occurrences - 预览重复任务(需认证)",
+				"DELETE /api/todos/:id/recurrence - 取消重复(需认证)",
+				"GET    /api/todos/:id/history - 获取变更记录(需认证)",
+				"GET    /api/todos/:id/dependencies - 获取依赖关系(需认证)",
+				"POST   /api/todos/:id/dependencies - 添加前置任务(需认证)",
+				"DELETE /api/todos/:id/dependencies/:blocker_id - 移除前置任务(需认证)",
+				"GET    /api/todos/:id/reminders - 获取提醒列表(需认证)",
+				"POST   /api/todos/:id/reminders - 创建提醒(需认证)",
+				"DELETE /api/todos/:id/reminders/:reminder_id - 删除提醒(需认证)",
+				"GET    /api/todos/:id/comments - 获取评论列表(需认证)",
+				"POST   /api/todos/:id/comments - 发表评论(需认证)",
+				"PUT    /api/todos/:id/comments/:comment_id - 编辑评论(需认证)",
+				"DELETE /api/todos/:id/comments/:comment_id - 删除评论(需认证)",
+				"GET    /api/todos/:id/attachments - 获取附件列表(需认证)",
+				"POST   /api/todos/:id/attachments - 上传附件(需认证)",
+				"GET    /api/todos/:id/attachments/:attachment_id - 下载附件(需认证)",
+				"DELETE /api/todos/:id/attachments/:attachment_id - 删除附件(需认证)",
+				"GET    /api/tags - 获取标签列表(需认证)",
+				"POST   /api/tags - 创建标签(需认证)",
+				"PUT    /api/tags/:id - 更新标签(需认证)",
+				"DELETE /api/tags/:id - 删除标签(需认证)",
+				"GET    /api/statuses - 获取状态工作流(需认证)",
+				"POST   /api/statuses - 创建自定义状态(需认证)",
+				"PUT    /api/statuses/:id - 更新自定义状态(需认证)",
+				"DELETE /api/statuses/:id - 删除自定义状态(需认证)",
+				"GET    /api/projects - 获取项目列表(需认证)",
+				"POST   /api/projects - 创建项目(需认证)",
+				"GET    /api/projects/:id - 获取项目详情(需认证)",
+				"PUT    /api/projects/:id - 更新项目(需认证)",
+				"DELETE /api/projects/:id?mode=inbox|delete - 删除项目(需认证)",
+				"GET    /api/projects/:id/stats - 项目统计(需认证)",
+				"GET    /api/shares?resource_type=&resource_id= - 获取资源的共享列表(需认证)",
+				"POST   /api/shares - 创建共享(需认证)",
+				"GET    /api/shares/with-me - 获取共享给我的资源(需认证)",
+				"PUT    /api/shares/:id - 修改共享角色(需认证)",
+				"DELETE /api/shares/:id - 取消共享(需认证)",
+				"GET    /api/calendar/token - 获取日历订阅状态(需认证)",
+				"POST   /api/calendar/token - 生成日历订阅地址(需认证)",
+				"DELETE /api/calendar/token - 停用日历订阅(需认证)",
+				"GET    /api/calendar/export - 导出日历(需认证)",
+				"POST   /api/calendar/import - 导入日历(需认证)",
+				"GET    /api/calendar/feed/:token - 日历订阅(令牌认证)",
+				"*      /caldav/ - CalDAV 同步(应用专用密码认证)",
 			},
 		})
 	})
@@ -69,6 +143,9 @@ func setupRouter(r *gin.Engine, h *handler.Healther, a *handler.AuthHandler, u *
 			auth.POST("/refresh", a.RefreshToken)
 		}
 
+		//日历订阅使用地址中的令牌认证，供无法携带JWT的日历客户端使用
+		api.GET("/calendar/feed/:token", cal.Feed)
+
 		protected := api.Group("")
 		protected.Use(middleware.AuthMiddleWare())
 		{
@@ -78,6 +155,9 @@ func setupRouter(r *gin.Engine, h *handler.Healther, a *handler.AuthHandler, u *
 				user.GET("/me", u.GetProfile)
 				user.PUT("/me", u.UpdateProfile)
 				user.PUT("/me/password", u.ChangePassword)
+				user.GET("/me/app-passwords", ap.GetAppPasswords)
+				user.POST("/me/app-passwords", ap.CreateAppPassword)
+				user.DELETE("/me/app-passwords/:id", ap.DeleteAppPassword)
 			}
 
 			// 待办事项路由
@@ -88,16 +168,141 @@ func setupRouter(r *gin.Engine, h *handler.Healther, a *handler.AuthHandler, u *
 				todos.POST("", t.CreateTodo)       // 创建待办事项
 				todos.GET("/:id", t.GetTodoByID)   // 获取单个待办事项
 				todos.PUT("/:id", t.UpdateTodo)    // 更新待办事项
+				todos.PATCH("/:id", t.PatchTodo)   // 局部更新待办事项
 				todos.DELETE("/:id", t.DeleteTodo) // 删除待办事项
 
+				// 搜索与统计
+				todos.GET("/search", t.SearchTodos) // 全文搜索待办事项
+				todos.GET("/stats", t.GetStats)     // 获取统计分析
+
+				// 导入导出
+				todos.GET("/export", t.ExportTodos)           // 导出待办事项
+				todos.POST("/import", t.ImportTodos)          // 导入待办事项
+				todos.POST("/import/:source", t.MigrateTodos) // 从其他待办工具迁移
+				todos.GET("/todotxt", t.ExportTodoTxt)        // 导出 todo.txt
+				todos.POST("/todotxt", t.SyncTodoTxt)         // 上传 todo.txt 同步
+
 				// 状态操作
 				todos.PUT("/:id/status", t.UpdateTodoStatus)    // 更新状态
+				todos.PUT("/:id/move", t.MoveTodo)              // 在看板中移动
 				todos.PUT("/batch/status", t.BatchUpdateStatus) // 批量更新状态
+				todos.POST("/batch", t.BatchTodos)              // 批量修改或删除
+
+				// 回收站操作
+				todos.GET("/trash", t.GetTrash)           // 获取回收站列表
+				todos.POST("/:id/restore", t.RestoreTodo) // 恢复待办事项
+				todos.DELETE("/:id/purge", t.PurgeTodo)   // 彻底删除待办事项
+
+				// 标签操作
+				todos.POST("/:id/tags", t.AttachTags)          // 添加标签
+				todos.DELETE("/:id/tags/:tag_id", t.DetachTag) // 移除标签
+
+				// 子任务操作
+				todos.GET("/:id/subtasks", t.GetSubtasks)           // 获取子任务列表
+				todos.POST("/:id/subtasks", t.CreateSubtask)        // 创建子任务
+				todos.PUT("/:id/subtasks/order", t.ReorderSubtasks) // 调整子任务顺序
+
+				// 重复任务操作
+				todos.GET("/:id/occurrences", t.GetOccurrences)   // 预览重复任务
+				todos.DELETE("/:id/recurrence", t.StopRecurrence) // 取消重复
+
+				// 变更记录
+				todos.GET("/:id/history", t.GetHistory) // 获取变更记录
+
+				// 依赖关系
+				todos.GET("/:id/dependencies", t.GetDependencies)                 // 获取依赖关系
+				todos.POST("/:id/dependencies", t.AddDependency)                  // 添加前置任务
+				todos.DELETE("/:id/dependencies/:blocker_id", t.RemoveDependency) // 移除前置任务
+
+				// 提醒操作
+				todos.GET("/:id/reminders", rm.GetReminders)                   // 获取提醒列表
+				todos.POST("/:id/reminders", rm.CreateReminder)                // 创建提醒
+				todos.DELETE("/:id/reminders/:reminder_id", rm.DeleteReminder) // 删除提醒
+
+				// 评论操作
+				todos.GET("/:id/comments", cm.GetComments)                  // 获取评论列表
+				todos.POST("/:id/comments", cm.CreateComment)               // 发表评论
+				todos.PUT("/:id/comments/:comment_id", cm.UpdateComment)    // 编辑评论
+				todos.DELETE("/:id/comments/:comment_id", cm.DeleteComment) // 删除评论
+
+				// 附件操作
+				todos.GET("/:id/attachments", at.GetAttachments)                     // 获取附件列表
+				todos.POST("/:id/attachments", at.UploadAttachment)                  // 上传附件
+				todos.GET("/:id/attachments/:attachment_id", at.DownloadAttachment)  // 下载附件
+				todos.DELETE("/:id/attachments/:attachment_id", at.DeleteAttachment) // 删除附件
+			}
+
+			// 看板路由
+			protected.GET("/board", t.GetBoard)
+
+			// 标签路由
+			tags := protected.Group("/tags")
+			{
+				tags.GET("", tg.GetTags)          // 获取标签列表
+				tags.POST("", tg.CreateTag)       // 创建标签
+				tags.GET("/:id", tg.GetTagByID)   // 获取单个标签
+				tags.PUT("/:id", tg.UpdateTag)    // 更新标签
+				tags.DELETE("/:id", tg.DeleteTag) // 删除标签
+			}
+
+			// 自定义状态路由
+			statuses := protected.Group("/statuses")
+			{
+				statuses.GET("", st.GetWorkflow)         // 获取状态工作流
+				statuses.POST("", st.CreateStatus)       // 创建自定义状态
+				statuses.PUT("/:id", st.UpdateStatus)    // 更新自定义状态
+				statuses.DELETE("/:id", st.DeleteStatus) // 删除自定义状态
+			}
+
+			// 项目路由
+			projects := protected.Group("/projects")
+			{
+				projects.GET("", p.GetProjects)                    // 获取项目列表
+				projects.POST("", p.CreateProject)                 // 创建项目
+				projects.GET("/:id", p.GetProjectByID)             // 获取项目详情
+				projects.PUT("/:id", p.UpdateProject)              // 更新项目
+				projects.DELETE("/:id", p.DeleteProject)           // 删除项目
+				projects.GET("/:id/stats", p.GetProjectStatistics) // 项目统计
+			}
+
+			// 共享路由
+			shares := protected.Group("/shares")
+			{
+				shares.GET("", sh.GetShares)               // 获取资源的共享列表
+				shares.POST("", sh.CreateShare)            // 创建共享
+				shares.GET("/with-me", sh.GetSharedWithMe) // 获取共享给我的资源
+				shares.PUT("/:id", sh.UpdateShare)         // 修改共享角色
+				shares.DELETE("/:id", sh.DeleteShare)      // 取消共享
+			}
+
+			// 日历路由
+			calendar := protected.Group("/calendar")
+			{
+				calendar.GET("/token", cal.GetToken)       // 获取订阅状态
+				calendar.POST("/token", cal.RotateToken)   // 生成订阅地址
+				calendar.DELETE("/token", cal.RevokeToken) // 停用订阅
+				calendar.GET("/export", cal.Export)        // 导出日历
+				calendar.POST("/import", cal.Import)       // 导入日历
 			}
 		}
 
 	}
 
+	//CalDAV路由，使用应用专用密码进行 HTTP Basic 认证；OPTIONS 供客户端探测，无需认证
+	r.OPTIONS("/caldav/*path", dav.Options)
+	r.GET("/.well-known/caldav", dav.WellKnown)
+	r.Handle("PROPFIND", "/.well-known/caldav", dav.WellKnown)
+	caldav := r.Group("/caldav")
+	caldav.Use(middleware.BasicAuthMiddleWare(config.GlobalConfig.App.Name+" CalDAV", davAuth))
+	{
+		caldav.Handle("PROPFIND", "/*path", dav.Propfind)
+		caldav.Handle("REPORT", "/*path", dav.Report)
+		caldav.GET("/*path", dav.Get)
+		caldav.HEAD("/*path", dav.Get)
+		caldav.PUT("/*path", dav.Put)
+		caldav.DELETE("/*path", dav.Delete)
+	}
+
 	// 调试路由：显示所有已注册的路由
 	r.GET("/debug/routes", func(c *gin.Context) {
 		var routes []map[string]string
@@ -118,9 +323,14 @@ func setupRouter(r *gin.Engine, h *handler.Healther, a *handler.AuthHandler, u *
 func setupBasicMiddleWare(g *gin.Engine) {
 	//添加请求日志中间件
 	g.Use(func(c *gin.Context) {
+		path := c.Request.URL.Path
+		//日历订阅地址中包含令牌，不写入日志
+		if strings.HasPrefix(path, "/api/calendar/feed/") {
+			path = "/api/calendar/feed/***"
+		}
 		logger.Info("Http请求",
 			zap.String("method", c.Request.Method),
-			zap.String("path", c.Request.URL.Path),
+			zap.String("path", path),
 			zap.String("ip", c.ClientIP()),
 		)
 		c.Next()
@@ -129,8 +339,44 @@ func setupBasicMiddleWare(g *gin.Engine) {
 	g.Use(gin.Recovery())
 }
 
-// 启动服务器
-func startSever(g *gin.Engine) {
+// 根据配置创建附件存储后端
+func setupStorage(cfg config.AttachmentConfig) (storage.Storage, error) {
+	switch cfg.Storage {
+	case "s3":
+		return storage.NewS3Storage(storage.S3Options{
+			Endpoint:  cfg.S3.Endpoint,
+			Region:    cfg.S3.Region,
+			Bucket:    cfg.S3.Bucket,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			UseSSL:    cfg.S3.UseSSL,
+			PathStyle: cfg.S3.PathStyle,
+		}), nil
+	case "", "local":
+		path := cfg.LocalPath
+		if path == "" {
+			path = "uploads"
+		}
+		return storage.NewLocalStorage(path)
+	default:
+		return nil, fmt.Errorf("不支持的存储后端: %s", cfg.Storage)
+	}
+}
+
+// 根据配置创建搜索后端
+func setupSearch(cfg config.SearchConfig) (search.Searcher, error) {
+	switch cfg.Engine {
+	case "", "mysql":
+		return search.NewMySQLSearcher(database.GetDB()), nil
+	case "memory":
+		return search.NewMemoryIndex(), nil
+	default:
+		return nil, fmt.Errorf("不支持的搜索后端: %s", cfg.Engine)
+	}
+}
+
+// 启动服务器，关闭时一并停止后台任务
+func startSever(g *gin.Engine, workers ...worker.Worker) {
 	port := config.GlobalConfig.Server.Port
 	if port == "" {
 		port = "8080"
@@ -168,6 +414,11 @@ func startSever(g *gin.Engine) {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error("服务器关闭失败", zap.Error(err))
 	}
+	for _, w := range workers {
+		if err := w.Stop(ctx); err != nil {
+			logger.Error("后台任务停止失败", zap.Error(err))
+		}
+	}
 	logger.Info("服务器关闭成功")
 
 }
@@ -228,18 +479,95 @@ func main() {
 	//初始化依赖注入
 	userRepo := repository.NewUserRepository(database.GetDB())
 	todoRepo := repository.NewTodoRepository(database.GetDB())
+	tagRepo := repository.NewTagRepository(database.GetDB())
+	reminderRepo := repository.NewReminderRepository(database.GetDB())
+	projectRepo := repository.NewProjectRepository(database.GetDB())
+	shareRepo := repository.NewShareRepository(database.GetDB())
+	commentRepo := repository.NewCommentRepository(database.GetDB())
+	dependencyRepo := repository.NewDependencyRepository(database.GetDB())
+	statusRepo := repository.NewStatusRepository(database.GetDB())
+	transactor := repository.NewTransactor(database.GetDB())
+	attachmentRepo := repository.NewAttachmentRepository(database.GetDB())
+	historyRepo := repository.NewHistoryRepository(database.GetDB())
+	appPasswordRepo := repository.NewAppPasswordRepository(database.GetDB())
+
+	//初始化附件存储
+	attachmentConfig := config.GlobalConfig.Attachment
+	fileStorage, err := setupStorage(attachmentConfig)
+	if err != nil {
+		logger.Error("附件存储初始化失败", zap.Error(err))
+		log.Fatalf("附件存储初始化失败: %v", err)
+	}
+
+	//初始化搜索后端
+	searchConfig := config.GlobalConfig.Search
+	searcher, err := setupSearch(searchConfig)
+	if err != nil {
+		logger.Error("搜索后端初始化失败", zap.Error(err))
+		log.Fatalf("搜索后端初始化失败: %v", err)
+	}
+
+	accessControl := service.NewAccessControl(shareRepo)
 
 	authService := service.NewAuthService(userRepo)
 	userService := service.NewUserService(userRepo)
-	todoService := service.NewTodoService(todoRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, todoRepo, accessControl, fileStorage,
+		attachmentConfig.MaxSize, attachmentConfig.AllowedTypes)
+	todoService := service.NewTodoService(todoRepo, tagRepo, reminderRepo, projectRepo, commentRepo,
+		historyRepo, dependencyRepo, statusRepo, transactor, accessControl, attachmentService, searcher)
+	//进程内索引启动时全量构建
+	if searchConfig.Engine == "memory" {
+		count, err := todoService.RebuildSearchIndex(context.Background())
+		if err != nil {
+			logger.Error("构建搜索索引失败", zap.Error(err))
+			log.Fatalf("构建搜索索引失败: %v", err)
+		}
+		logger.Info("搜索索引构建完成", zap.Int("todos", count))
+	}
+	tagService := service.NewTagService(tagRepo)
+	statusService := service.NewStatusService(statusRepo)
+	reminderService := service.NewReminderService(reminderRepo, todoRepo, accessControl)
+	projectService := service.NewProjectService(projectRepo, todoRepo, todoService, accessControl)
+	shareService := service.NewShareService(shareRepo, todoRepo, projectRepo, userRepo, accessControl)
+	commentService := service.NewCommentService(commentRepo, todoRepo, accessControl)
+	calendarService := service.NewCalendarService(userRepo, todoService)
+	appPasswordService := service.NewAppPasswordService(appPasswordRepo, userRepo)
 
 	authHandler := handler.NewAuthHandler(authService)
 	userHandler := handler.NewUserHandeler(userService)
 	todoHandler := handler.NewTodoHandler(todoService)
+	tagHandler := handler.NewTagHandler(tagService)
+	statusHandler := handler.NewStatusHandler(statusService)
+	reminderHandler := handler.NewReminderHandler(reminderService)
+	projectHandler := handler.NewProjectHandler(projectService)
+	shareHandler := handler.NewShareHandler(shareService)
+	commentHandler := handler.NewCommentHandler(commentService)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+	calendarHandler := handler.NewCalendarHandler(calendarService)
+	appPasswordHandler := handler.NewAppPasswordHandler(appPasswordService)
+	caldavHandler := handler.NewCalDAVHandler(todoService)
 	healthHandler := handler.NewHealther()
 	//设置路由
-	setupRouter(r, healthHandler, authHandler, userHandler, todoHandler)
+	setupRouter(r, healthHandler, authHandler, userHandler, todoHandler, tagHandler, reminderHandler, projectHandler, shareHandler,
+		commentHandler, attachmentHandler, calendarHandler, appPasswordHandler, caldavHandler, appPasswordService,
+		statusHandler)
+
+	//启动后台任务
+	var workers []worker.Worker
+	if reminderConfig := config.GlobalConfig.Reminder; reminderConfig.Enabled {
+		reminderWorker := worker.NewReminderWorker(reminderRepo, worker.NewLogNotifier(),
+			time.Duration(reminderConfig.Interval)*time.Second, reminderConfig.BatchSize)
+		reminderWorker.Start()
+		workers = append(workers, reminderWorker)
+	}
+	if trashConfig := config.GlobalConfig.Trash; trashConfig.Enabled {
+		trashWorker := worker.NewTrashWorker(todoService,
+			time.Duration(trashConfig.RetentionDays)*24*time.Hour,
+			time.Duration(trashConfig.Interval)*time.Second, trashConfig.BatchSize)
+		trashWorker.Start()
+		workers = append(workers, trashWorker)
+	}
 
 	//启动服务器
-	startSever(r)
+	startSever(r, workers...)
 }
@@ -45,12 +45,56 @@ type JWTConfig struct {
 	Issuer        string `mapstructure:"issuer"`
 }
 
+// 提醒任务配置
+type ReminderConfig struct {
+	Enabled   bool `mapstructure:"enabled"`
+	Interval  int  `mapstructure:"interval"`   //扫描间隔(秒)
+	BatchSize int  `mapstructure:"batch_size"` //每次扫描处理的最大提醒数
+}
+
+// 回收站清理任务配置
+type TrashConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	RetentionDays int  `mapstructure:"retention_days"` //删除后保留天数，超过后彻底删除
+	Interval      int  `mapstructure:"interval"`       //扫描间隔(秒)
+	BatchSize     int  `mapstructure:"batch_size"`     //每次扫描最多清理的待办事项数
+}
+
+// 搜索配置
+type SearchConfig struct {
+	Engine string `mapstructure:"engine"` //搜索后端: mysql(FULLTEXT 索引), memory(进程内倒排索引，启动时全量构建)
+}
+
+// 附件配置
+type AttachmentConfig struct {
+	MaxSize      int64    `mapstructure:"max_size"`      //单个文件最大字节数
+	AllowedTypes []string `mapstructure:"allowed_types"` //允许的MIME类型，支持 image/* 通配
+	Storage      string   `mapstructure:"storage"`       //存储后端: local, s3
+	LocalPath    string   `mapstructure:"local_path"`    //本地存储目录
+	S3           S3Config `mapstructure:"s3"`
+}
+
+// S3兼容存储配置，MinIO 需要开启 path_style
+type S3Config struct {
+	Endpoint  string `mapstructure:"endpoint"`
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	UseSSL    bool   `mapstructure:"use_ssl"`
+	PathStyle bool   `mapstructure:"path_style"`
+}
+
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	App      AppConfig      `mapstructure:"app"`
-	Log      LogConfig      `mapstructure:"log"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	App        AppConfig        `mapstructure:"app"`
+	Log        LogConfig        `mapstructure:"log"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	Reminder   ReminderConfig   `mapstructure:"reminder"`
+	Attachment AttachmentConfig `mapstructure:"attachment"`
+	Trash      TrashConfig      `mapstructure:"trash"`
+	Search     SearchConfig     `mapstructure:"search"`
 }
 
 var GlobalConfig Config
@@ -65,12 +109,12 @@ func InitConfig(configPath string) {
 
 	//读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
-		log.Fatal("读取配置文件失败：%v", err)
+		log.Fatalf("读取配置文件失败：%v", err)
 	}
 
 	//将配置绑定到结构体
 	if err := viper.Unmarshal(&GlobalConfig); err != nil {
-		log.Fatal("绑定配置结构体失败：%v", err)
+		log.Fatalf("绑定配置结构体失败：%v", err)
 	}
 
 	log.Println("配置文件加载成功")
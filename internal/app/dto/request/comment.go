@@ -0,0 +1,17 @@
+package request
+
+// CreateCommentRequest 创建评论请求
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=5000"`
+}
+
+// UpdateCommentRequest 编辑评论请求
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=5000"`
+}
+
+// CommentQueryRequest 评论列表查询请求
+type CommentQueryRequest struct {
+	Page     uint `form:"page,default=1" binding:"required,min=1"`
+	PageSize uint `form:"page_size,default=20" binding:"required,min=1,max=100"`
+}
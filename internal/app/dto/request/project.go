@@ -0,0 +1,20 @@
+package request
+
+// CreateProjectRequest 创建项目请求
+type CreateProjectRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description,omitempty" binding:"max=500"`
+	Color       string `json:"color,omitempty" binding:"omitempty,hexcolor"`
+}
+
+// UpdateProjectRequest 更新项目请求
+type UpdateProjectRequest struct {
+	Name        string `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	Description string `json:"description,omitempty" binding:"max=500"`
+	Color       string `json:"color,omitempty" binding:"omitempty,hexcolor"`
+}
+
+// DeleteProjectRequest 删除项目请求，inbox-待办事项移入收件箱，delete-一并删除
+type DeleteProjectRequest struct {
+	Mode string `form:"mode,default=inbox" binding:"oneof=inbox delete"`
+}
@@ -0,0 +1,9 @@
+package request
+
+import "time"
+
+// CreateReminderRequest 创建提醒请求，remind_at 与 offset_minutes 二选一
+type CreateReminderRequest struct {
+	RemindAt      *time.Time `json:"remind_at,omitempty" binding:"required_without=OffsetMinutes,excluded_with=OffsetMinutes"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty" binding:"required_without=RemindAt,omitempty,min=0,max=525600"`
+}
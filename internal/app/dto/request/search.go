@@ -0,0 +1,9 @@
+package request
+
+// SearchTodoRequest 全文搜索请求
+// 多个词之间为"且"关系，"双引号"包裹的内容按短语匹配，以 * 结尾的词按前缀匹配
+type SearchTodoRequest struct {
+	Query    string `form:"q" binding:"required,max=200"`
+	Page     uint   `form:"page,default=1" binding:"required,min=1"`
+	PageSize uint   `form:"page_size,default=10" binding:"required,min=1,max=100"`
+}
@@ -0,0 +1,20 @@
+package request
+
+// CreateShareRequest 创建共享请求
+type CreateShareRequest struct {
+	ResourceType string `json:"resource_type" binding:"required,oneof=todo project"`
+	ResourceID   uint   `json:"resource_id" binding:"required"`
+	Username     string `json:"username" binding:"required"`
+	Role         string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+// UpdateShareRequest 更新共享角色请求
+type UpdateShareRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor owner"`
+}
+
+// ShareQueryRequest 查询资源共享列表请求
+type ShareQueryRequest struct {
+	ResourceType string `form:"resource_type" binding:"required,oneof=todo project"`
+	ResourceID   uint   `form:"resource_id" binding:"required"`
+}
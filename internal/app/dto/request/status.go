@@ -0,0 +1,16 @@
+package request
+
+// CreateStatusRequest 创建自定义状态请求
+type CreateStatusRequest struct {
+	Name     string `json:"name" binding:"required,min=1,max=50"`
+	Category *uint8 `json:"category" binding:"required,oneof=0 1 2"` // 所属分类: 0-待办,1-进行中,2-已完成
+	Color    string `json:"color,omitempty" binding:"omitempty,hexcolor"`
+	Position int    `json:"position,omitempty" binding:"min=0"` // 同一分类内的排序位置
+}
+
+// UpdateStatusRequest 更新自定义状态请求，所属分类创建后不能修改
+type UpdateStatusRequest struct {
+	Name     string `json:"name,omitempty" binding:"omitempty,min=1,max=50"`
+	Color    string `json:"color,omitempty" binding:"omitempty,hexcolor"`
+	Position *int   `json:"position,omitempty" binding:"omitempty,min=0"`
+}
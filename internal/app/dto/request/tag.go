@@ -0,0 +1,18 @@
+package request
+
+// CreateTagRequest 创建标签请求
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,min=1,max=50"`
+	Color string `json:"color,omitempty" binding:"omitempty,hexcolor"`
+}
+
+// UpdateTagRequest 更新标签请求
+type UpdateTagRequest struct {
+	Name  string `json:"name,omitempty" binding:"omitempty,min=1,max=50"`
+	Color string `json:"color,omitempty" binding:"omitempty,hexcolor"`
+}
+
+// TodoTagsRequest 待办事项添加标签请求
+type TodoTagsRequest struct {
+	TagIDs []uint `json:"tag_ids" binding:"required,min=1"`
+}
@@ -1,41 +1,154 @@
 package request
 
-import "time"
+import (
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
 
 // CreateTodoRequest 创建待办事项请求
 type CreateTodoRequest struct {
-	Title       string     `json:"title" binding:"required,min=1,max=200"`
-	Description string     `json:"description,omitempty"`
-	Status      uint8      `json:"status,omitempty" binding:"oneof=0 1 2"`
-	Priority    uint8      `json:"priority,omitempty" binding:"oneof=1 2 3 4"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
+	Title       string             `json:"title" binding:"required,min=1,max=200"`
+	Description string             `json:"description,omitempty"`
+	Status      uint8              `json:"status,omitempty" binding:"oneof=0 1 2"`
+	Priority    uint8              `json:"priority,omitempty" binding:"oneof=1 2 3 4"`
+	DueDate     *time.Time         `json:"due_date,omitempty"`
+	Recurrence  *RecurrenceRequest `json:"recurrence,omitempty"`
+	ProjectID   *uint              `json:"project_id,omitempty"` // 为空或0表示收件箱
 }
 
 // UpdateTodoRequest 更新待办事项请求
 type UpdateTodoRequest struct {
-	Title       string     `json:"title" binding:"required,min=1,max=200"`
-	Description string     `json:"description,omitempty"`
-	Status      *uint8     `json:"status,omitempty" binding:"oneof=0 1 2"`
-	Priority    *uint8     `json:"priority,omitempty" binding:"oneof=1 2 3 4"`
-	DueDate     *time.Time `json:"due_date,omitempty"`
+	Title       string             `json:"title" binding:"required,min=1,max=200"`
+	Description string             `json:"description,omitempty"`
+	Status      *uint8             `json:"status,omitempty" binding:"oneof=0 1 2"`
+	StatusID    *uint              `json:"status_id,omitempty"` // 自定义状态，0 表示清除；与 status 同时指定时必须属于该分类
+	Priority    *uint8             `json:"priority,omitempty" binding:"oneof=1 2 3 4"`
+	DueDate     *time.Time         `json:"due_date,omitempty"`
+	Recurrence  *RecurrenceRequest `json:"recurrence,omitempty"`
+	ProjectID   *uint              `json:"project_id,omitempty"` // 0 表示移入收件箱
+}
+
+// TodoPatchDocument 局部更新时被修补的待办事项文档：补丁应用到由当前数据生成的文档上，
+// 修补结果中为 null 或被删除的可选字段表示清除该字段
+type TodoPatchDocument struct {
+	Title       string             `json:"title" binding:"required,min=1,max=200"`
+	Description *string            `json:"description"`
+	Status      *uint8             `json:"status" binding:"required,oneof=0 1 2"`
+	StatusID    *uint              `json:"status_id"` // 自定义状态，必须属于 status 分类
+	Priority    *uint8             `json:"priority" binding:"required,oneof=1 2 3 4"`
+	DueDate     *time.Time         `json:"due_date"`
+	Recurrence  *RecurrenceRequest `json:"recurrence"` // 设置时必须有截止时间
+	ProjectID   *uint              `json:"project_id"` // 为空表示收件箱
+}
+
+// Validate 按 binding 标签校验修补后的文档，规则与请求参数绑定时一致
+func (d *TodoPatchDocument) Validate() error {
+	return binding.Validator.ValidateStruct(d)
+}
+
+// RecurrenceRequest 重复规则，以截止时间作为第一次发生时间
+type RecurrenceRequest struct {
+	Freq      string     `json:"freq" binding:"required,oneof=daily weekly monthly"`
+	Interval  int        `json:"interval,omitempty" binding:"omitempty,min=1,max=365"`
+	ByWeekday []string   `json:"by_weekday,omitempty" binding:"omitempty,dive,oneof=MO TU WE TH FR SA SU"`
+	Until     *time.Time `json:"until,omitempty"`
+	Count     int        `json:"count,omitempty" binding:"omitempty,min=1"`
 }
 
 // TodoQueryRequest 待办事项查询请求
 type TodoQueryRequest struct {
-	Page     uint   `form:"page,default=1" binding:"required,min=1"`
-	PageSize uint   `form:"page,default=10" binding:"required,min=1,max=100"`
-	Status   *uint8 `form:"status,omitempty" binding:"oneof=0 1 2"`
-	Priority *uint8 `form:"priority,omitempty" binding:"oneof=1 2 3 4"`
-	KeyWord  string `form:"keyword"`
+	Page      uint   `form:"page,default=1" binding:"required,min=1"`
+	PageSize  uint   `form:"page_size,default=10" binding:"required,min=1,max=100"`
+	Status    *uint8 `form:"status,omitempty" binding:"oneof=0 1 2"`
+	Priority  *uint8 `form:"priority,omitempty" binding:"oneof=1 2 3 4"`
+	TagID     *uint  `form:"tag"`
+	ProjectID *uint  `form:"project"` // 0 表示收件箱
+	KeyWord   string `form:"keyword"`
+	// Filter 筛选表达式，如 status in (0,1) and priority >= 3 and due < 2026-11-01 and overdue
+	Filter string `form:"filter" binding:"max=1000"`
+	// Sort 排序字段，逗号分隔，字段前加 - 表示降序，如 -priority,due_date
+	Sort string `form:"sort"`
+	// Cursor 上一次响应中 cursors 的 next/prev，设置后忽略 page
+	Cursor string `form:"cursor"`
+	// WithStats 是否同时返回统计信息，统计范围为用户或项目的全部待办事项，不受其余筛选条件影响
+	WithStats bool `form:"with_stats"`
+}
+
+// TodoStatsRequest 统计查询请求，日期格式为 2006-01-02，默认最近30天
+type TodoStatsRequest struct {
+	From      *time.Time `form:"from" time_format:"2006-01-02"`
+	To        *time.Time `form:"to" time_format:"2006-01-02"`
+	ProjectID *uint      `form:"project"` // 0 表示收件箱
 }
 
 // UpdateTodoStatusRequest 更新状态请求
 type UpdateTodoStatusRequest struct {
-	Status *uint8 `json:"status" binding:"oneof=0 1 2"`
+	Status   *uint8 `json:"status" binding:"required_without=StatusID,omitempty,oneof=0 1 2"`
+	StatusID *uint  `json:"status_id,omitempty"` // 自定义状态，0 表示清除；只指定自定义状态时流转到其所属分类
+}
+
+// MoveTodoRequest 看板中移动待办事项，BeforeID/AfterID 为移动后紧邻其前/后的待办事项，
+// 只指定一个时放在其后/前，都不指定时放在列首
+type MoveTodoRequest struct {
+	Status   *uint8 `json:"status,omitempty" binding:"omitempty,oneof=0 1 2"` // 目标列，默认为当前状态
+	BeforeID *uint  `json:"before_id,omitempty"`
+	AfterID  *uint  `json:"after_id,omitempty"`
+}
+
+// BoardQueryRequest 看板查询请求
+type BoardQueryRequest struct {
+	ProjectID *uint `form:"project"`                                  // 0 表示收件箱，为空表示全部
+	Limit     int   `form:"limit,default=50" binding:"min=1,max=200"` // 每列最多返回的数量
 }
 
 // BatchUpdateRequest 批量操作请求
 type BatchUpdateTodoRequest struct {
-	TodoIDs []uint `json:"todo_ids" binding:"required,min=1"`
-	Status  *uint8 `json:"status,omitempty" binding:"oneof=0 1 2"`
+	TodoIDs []uint `json:"todo_ids" binding:"required,min=1,max=200"`
+	Status  *uint8 `json:"status" binding:"required,oneof=0 1 2"`
+}
+
+// BatchTodoRequest 批量修改或删除待办事项，action 为 update 时只修改指定的字段
+type BatchTodoRequest struct {
+	TodoIDs      []uint     `json:"todo_ids" binding:"required,min=1,max=200"`
+	Action       string     `json:"action" binding:"required,oneof=update delete"`
+	Status       *uint8     `json:"status,omitempty" binding:"omitempty,oneof=0 1 2"`
+	StatusID     *uint      `json:"status_id,omitempty"` // 自定义状态，0 表示清除
+	Priority     *uint8     `json:"priority,omitempty" binding:"omitempty,oneof=1 2 3 4"`
+	DueDate      *time.Time `json:"due_date,omitempty"`
+	ProjectID    *uint      `json:"project_id,omitempty"` // 0 表示移入收件箱
+	AddTagIDs    []uint     `json:"add_tag_ids,omitempty"`
+	RemoveTagIDs []uint     `json:"remove_tag_ids,omitempty"`
+	AllOrNothing bool       `json:"all_or_nothing"` // 任一待办事项失败时全部回滚
+}
+
+// ReorderSubtasksRequest 子任务排序请求
+type ReorderSubtasksRequest struct {
+	SubtaskIDs []uint `json:"subtask_ids" binding:"required,min=1"`
+}
+
+// AddDependencyRequest 添加前置任务请求
+type AddDependencyRequest struct {
+	BlockerID uint `json:"blocker_id" binding:"required"`
+}
+
+// OccurrencePreviewRequest 重复实例预览请求
+type OccurrencePreviewRequest struct {
+	Limit int `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+// ExportTodosRequest 导出请求
+type ExportTodosRequest struct {
+	Format string `form:"format,default=json" binding:"oneof=json csv md"`
+}
+
+// ImportTodosRequest 导入请求，Format 为空时按文件扩展名判断
+type ImportTodosRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+	DryRun bool   `form:"dry_run"` // 只校验不写入
+}
+
+// MigrateTodosRequest 从其他工具迁移的请求
+type MigrateTodosRequest struct {
+	DryRun bool `form:"dry_run"` // 只校验不写入
 }
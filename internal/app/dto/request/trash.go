@@ -0,0 +1,7 @@
+package request
+
+// TrashQueryRequest 回收站列表查询请求
+type TrashQueryRequest struct {
+	Page     uint `form:"page,default=1" binding:"required,min=1"`
+	PageSize uint `form:"page_size,default=10" binding:"required,min=1,max=100"`
+}
@@ -12,3 +12,8 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password,omitempty" binding:"required,min=6,max=20"`
 	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=NewPassword"`
 }
+
+// CreateAppPasswordRequest 创建应用专用密码请求
+type CreateAppPasswordRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
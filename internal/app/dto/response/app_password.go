@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+// AppPasswordResponse 应用专用密码，Password 只在创建时返回
+type AppPasswordResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Password   string     `json:"password,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+// AttachmentResponse 附件响应
+type AttachmentResponse struct {
+	ID          uint      `json:"id"`
+	TodoID      uint      `json:"todo_id"`
+	UserID      uint      `json:"user_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -16,6 +16,7 @@ type UserResponse struct {
 	Username string  `json:"username"`
 	Email    string  `json:"email"`
 	Avatar   *string `json:"avatar,omitempty"`
+	Version  uint    `json:"version"` // 版本号，每次更新加1
 }
 
 // UserProfileResponse 用户详情响应
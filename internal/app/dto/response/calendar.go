@@ -0,0 +1,36 @@
+package response
+
+// CalendarTokenResponse 日历订阅状态，令牌及订阅地址只在生成时返回
+type CalendarTokenResponse struct {
+	Enabled bool   `json:"enabled"`
+	Token   string `json:"token,omitempty"`
+	FeedURL string `json:"feed_url,omitempty"`
+}
+
+// 日历及 todo.txt 导入结果
+const (
+	ImportResultCreated   = "created"
+	ImportResultUpdated   = "updated"
+	ImportResultUnchanged = "unchanged"
+	ImportResultSkipped   = "skipped"
+	ImportResultFailed    = "failed"
+)
+
+// CalendarImportItem 单个 VTODO 的导入结果，Index 为在文件中的序号(从1开始)
+type CalendarImportItem struct {
+	Index   int    `json:"index"`
+	UID     string `json:"uid,omitempty"`
+	Title   string `json:"title,omitempty"`
+	Result  string `json:"result"`
+	TodoID  *uint  `json:"todo_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// CalendarImportResponse 日历导入响应
+type CalendarImportResponse struct {
+	Total   int                  `json:"total"`
+	Created int                  `json:"created"`
+	Skipped int                  `json:"skipped"`
+	Failed  int                  `json:"failed"`
+	Items   []CalendarImportItem `json:"items"`
+}
@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+// CommentResponse 评论响应
+type CommentResponse struct {
+	ID        uint       `json:"id"`
+	TodoID    uint       `json:"todo_id"`
+	UserID    uint       `json:"user_id"`
+	Username  string     `json:"username"`
+	Body      string     `json:"body"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CommentListResponse 评论列表响应
+type CommentListResponse struct {
+	Comments   []CommentResponse `json:"comments"`
+	Pagination Pagination        `json:"pagination"`
+}
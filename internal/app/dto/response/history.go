@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+// TodoHistoryResponse 待办事项变更记录响应
+type TodoHistoryResponse struct {
+	ID        uint      `json:"id"`
+	TodoID    uint      `json:"todo_id"`
+	UserID    uint      `json:"user_id"`
+	Username  string    `json:"username"`
+	Action    string    `json:"action"`          // created, updated, deleted
+	Field     string    `json:"field,omitempty"` // title, description, status, priority, due_date, project_id, recurrence, tags
+	OldValue  *string   `json:"old_value,omitempty"`
+	NewValue  *string   `json:"new_value,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+// ProjectResponse 项目响应
+type ProjectResponse struct {
+	ID          uint        `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Color       string      `json:"color"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+	Statistics  *Statistics `json:"statistics,omitempty"`
+}
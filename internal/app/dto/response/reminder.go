@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+// ReminderResponse 提醒响应
+type ReminderResponse struct {
+	ID            uint       `json:"id"`
+	TodoID        uint       `json:"todo_id"`
+	RemindAt      time.Time  `json:"remind_at"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	FailedAt      *time.Time `json:"failed_at,omitempty"` // 多次发送失败后不再重试
+	CreatedAt     time.Time  `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package response
+
+// SearchHighlights 高亮片段，命中词以 <em></em> 包裹，其余内容已做 HTML 转义
+type SearchHighlights struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// SearchResultResponse 单条搜索结果
+type SearchResultResponse struct {
+	Todo       TodoResponse     `json:"todo"`
+	Score      float64          `json:"score"`
+	Highlights SearchHighlights `json:"highlights"`
+}
+
+// SearchTodoResponse 全文搜索响应
+type SearchTodoResponse struct {
+	Query      string                 `json:"query"`
+	Results    []SearchResultResponse `json:"results"`
+	Pagination Pagination             `json:"pagination"`
+}
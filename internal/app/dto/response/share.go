@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+// ShareResponse 共享响应
+type ShareResponse struct {
+	ID            uint      `json:"id"`
+	ResourceType  string    `json:"resource_type"`
+	ResourceID    uint      `json:"resource_id"`
+	ResourceName  string    `json:"resource_name,omitempty"` // 待办事项标题或项目名称
+	OwnerID       uint      `json:"owner_id"`
+	OwnerUsername string    `json:"owner_username,omitempty"`
+	UserID        uint      `json:"user_id"`
+	Username      string    `json:"username,omitempty"`
+	Role          string    `json:"role"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package response
+
+import "time"
+
+// StatusResponse 自定义状态响应
+type StatusResponse struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	Category     uint8     `json:"category"`
+	CategoryText string    `json:"category_text"`
+	Color        string    `json:"color"`
+	Position     int       `json:"position"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StatusCategoryResponse 状态分类及其下的自定义状态
+type StatusCategoryResponse struct {
+	Status      uint8            `json:"status"`
+	StatusText  string           `json:"status_text"`
+	Transitions []uint8          `json:"transitions"` // 允许流转到的其他分类
+	Statuses    []StatusResponse `json:"statuses"`
+}
+
+// WorkflowResponse 状态工作流响应
+type WorkflowResponse struct {
+	Categories []StatusCategoryResponse `json:"categories"`
+}
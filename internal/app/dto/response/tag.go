@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+// TagResponse 标签响应
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -4,19 +4,49 @@ import "time"
 
 // TodoResponse 待办事项响应
 type TodoResponse struct {
-	ID           uint       `json:"id"`
-	UserID       uint       `json:"user_id"`
-	Title        string     `json:"title"`
-	Description  string     `json:"description,omitempty"`
-	Status       uint8      `json:"status"`
-	StatusText   string     `json:"status_text"`
-	Priority     uint8      `json:"priority"`
-	PriorityText string     `json:"priority_text"`
-	DueDate      *time.Time `json:"due_date,omitempty"`
-	CompletedAt  *time.Time `json:"completed,omitempty"`
-	CreatedAt    *time.Time `json:"created_at,omitempty"`
-	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
-	IsOverdue    bool       `json:"is_overdue"`
+	ID           uint                `json:"id"`
+	UserID       uint                `json:"user_id"`
+	ProjectID    *uint               `json:"project_id,omitempty"`
+	ParentID     *uint               `json:"parent_id,omitempty"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description,omitempty"`
+	Status       uint8               `json:"status"`
+	StatusID     *uint               `json:"status_id,omitempty"` // 自定义状态ID
+	StatusText   string              `json:"status_text"`         // 有自定义状态时为其名称
+	Priority     uint8               `json:"priority"`
+	PriorityText string              `json:"priority_text"`
+	DueDate      *time.Time          `json:"due_date,omitempty"`
+	CompletedAt  *time.Time          `json:"completed,omitempty"`
+	CreatedAt    *time.Time          `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time          `json:"updated_at,omitempty"`
+	DeletedAt    *time.Time          `json:"deleted_at,omitempty"` // 仅回收站中的待办事项有值
+	Version      uint                `json:"version"`              // 版本号，每次更新加1
+	IsOverdue    bool                `json:"is_overdue"`
+	Recurrence   *RecurrenceResponse `json:"recurrence,omitempty"`
+	Tags         []TagResponse       `json:"tags"`
+	CommentCount uint                `json:"comment_count"`
+	IsBlocked    bool                `json:"is_blocked"` // 存在未完成的前置任务
+
+	Subtasks          []TodoResponse `json:"subtasks,omitempty"`
+	SubtaskCount      uint           `json:"subtask_count"`
+	CompletedSubtasks uint           `json:"completed_subtasks"`
+	CompletionPercent uint           `json:"completion_percent"` // 完成百分比，0-100
+}
+
+// RecurrenceResponse 重复规则响应
+type RecurrenceResponse struct {
+	Rule      string     `json:"rule"`
+	Freq      string     `json:"freq"`
+	Interval  int        `json:"interval"`
+	ByWeekday []string   `json:"by_weekday,omitempty"`
+	Until     *time.Time `json:"until,omitempty"`
+	Count     int        `json:"count,omitempty"` // 剩余重复次数(含当前一次)
+}
+
+// OccurrencesResponse 重复实例预览响应
+type OccurrencesResponse struct {
+	Rule        string      `json:"rule"`
+	Occurrences []time.Time `json:"occurrences"`
 }
 
 // Pagination 分页信息
@@ -27,6 +57,12 @@ type Pagination struct {
 	TotalPages uint `json:"total_pages"`
 }
 
+// Cursors 游标分页信息，在对应方向上没有更多数据时为空
+type Cursors struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
 // Statistics 统计信息
 type Statistics struct {
 	TotalCount      uint `json:"total_count"`
@@ -39,10 +75,90 @@ type Statistics struct {
 type TodoListResponse struct {
 	Todos      []TodoResponse `json:"todos"`
 	Pagination Pagination     `json:"pagination"`
-	Statistics Statistics     `json:"statistics,omitempty"`
+	Cursors    Cursors        `json:"cursors"`
+	Statistics *Statistics    `json:"statistics,omitempty"` // 请求 with_stats=true 时返回
+}
+
+// TrashListResponse 回收站列表响应
+type TrashListResponse struct {
+	Todos      []TodoResponse `json:"todos"`
+	Pagination Pagination     `json:"pagination"`
+}
+
+// PriorityCounts 按优先级计数
+type PriorityCounts struct {
+	Low    uint `json:"low"`
+	Medium uint `json:"medium"`
+	High   uint `json:"high"`
+	Urgent uint `json:"urgent"`
+}
+
+// DailyStats 单日创建与完成数量
+type DailyStats struct {
+	Date      string `json:"date"`
+	Created   uint   `json:"created"`
+	Completed uint   `json:"completed"`
 }
 
-// TodoStatsResponse 待办事项统计响应
+// TodoStatsResponse 待办事项统计响应，窗口相关的统计均基于 from 至 to (含)
 type TodoStatsResponse struct {
-	Statistics Statistics `json:"statistics"`
+	Statistics   Statistics     `json:"statistics"`
+	ByPriority   PriorityCounts `json:"by_priority"`
+	OverdueCount uint           `json:"overdue_count"`
+
+	From               string       `json:"from"`
+	To                 string       `json:"to"`
+	CreatedInWindow    uint         `json:"created_in_window"`
+	CompletedInWindow  uint         `json:"completed_in_window"`
+	CompletionRate     float64      `json:"completion_rate"`      // 窗口内创建的任务中已完成的比例，0~1
+	AvgCompletionHours float64      `json:"avg_completion_hours"` // 窗口内完成的任务从创建到完成的平均小时数
+	Daily              []DailyStats `json:"daily"`
+}
+
+// BoardColumn 看板中的一列，Total 为该列的总数
+type BoardColumn struct {
+	Status     uint8          `json:"status"`
+	StatusText string         `json:"status_text"`
+	Total      uint           `json:"total"`
+	Todos      []TodoResponse `json:"todos"`
+}
+
+// BoardResponse 看板响应，每个状态一列
+type BoardResponse struct {
+	ProjectID *uint         `json:"project_id,omitempty"`
+	Columns   []BoardColumn `json:"columns"`
+}
+
+// TodoDependenciesResponse 待办事项的依赖关系
+type TodoDependenciesResponse struct {
+	TodoID    uint           `json:"todo_id"`
+	IsBlocked bool           `json:"is_blocked"`
+	BlockedBy []TodoResponse `json:"blocked_by"` // 前置任务，完成前阻塞该任务
+	Blocks    []TodoResponse `json:"blocks"`     // 被该任务阻塞的任务
+}
+
+// 批量操作中每个待办事项的结果
+const (
+	BatchResultUpdated    = "updated"
+	BatchResultDeleted    = "deleted"
+	BatchResultNotFound   = "not_found"
+	BatchResultForbidden  = "forbidden"
+	BatchResultFailed     = "failed"
+	BatchResultRolledBack = "rolled_back" // 本身可以执行，但全部回滚模式下因其他待办事项失败而撤销
+)
+
+// BatchTodoItem 单个待办事项的批量操作结果
+type BatchTodoItem struct {
+	ID      uint   `json:"id"`
+	Result  string `json:"result"`
+	Message string `json:"message,omitempty"`
+}
+
+// BatchTodoResponse 批量操作响应
+type BatchTodoResponse struct {
+	Total      int             `json:"total"`
+	Succeeded  int             `json:"succeeded"`
+	Failed     int             `json:"failed"`
+	RolledBack bool            `json:"rolled_back"` // 全部回滚模式下有待办事项失败，所有修改均已撤销
+	Items      []BatchTodoItem `json:"items"`
 }
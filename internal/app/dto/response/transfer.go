@@ -0,0 +1,50 @@
+package response
+
+// TodoImportError 单行的校验错误，Row 为数据所在行(CSV 含表头，从1开始)或序号(JSON，从1开始)
+type TodoImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// TodoImportResponse 导入结果，存在任一错误时不写入任何数据
+type TodoImportResponse struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Valid   int               `json:"valid"`
+	Created int               `json:"created"`
+	Errors  []TodoImportError `json:"errors"`
+}
+
+// MigrationIssue 迁移中无法映射或被调整的内容
+type MigrationIssue struct {
+	Item    string `json:"item"` // 源数据中的标题或名称
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// TodoMigrationResponse 迁移结果及报告，Errors 中的行号为转换后记录的序号
+type TodoMigrationResponse struct {
+	Source string `json:"source"`
+	TodoImportResponse
+	Unmapped []MigrationIssue `json:"unmapped"`
+}
+
+// TodoTxtSyncItem todo.txt 中单行的同步结果，Line 为行号(从1开始)
+type TodoTxtSyncItem struct {
+	Line    int    `json:"line"`
+	Title   string `json:"title,omitempty"`
+	Result  string `json:"result"`
+	TodoID  *uint  `json:"todo_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// TodoTxtSyncResponse todo.txt 同步结果，每行单独处理，部分失败不影响其余行
+type TodoTxtSyncResponse struct {
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Unchanged int               `json:"unchanged"`
+	Failed    int               `json:"failed"`
+	Items     []TodoTxtSyncItem `json:"items"`
+}
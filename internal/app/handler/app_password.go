@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppPasswordHandler struct {
+	appPasswordService service.AppPasswordService
+}
+
+func NewAppPasswordHandler(appPasswordService service.AppPasswordService) *AppPasswordHandler {
+	return &AppPasswordHandler{appPasswordService: appPasswordService}
+}
+
+// CreateAppPassword 创建应用专用密码
+// @Summary 创建应用专用密码
+// @Description 为 CalDAV 等客户端创建应用专用密码，使用用户名及该密码进行 HTTP Basic 认证；密码只返回这一次
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body request.CreateAppPasswordRequest true "创建应用专用密码请求"
+// @Success 200 {object} response.Response{data=response.AppPasswordResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /users/me/app-passwords [post]
+func (h *AppPasswordHandler) CreateAppPassword(c *gin.Context) {
+	var req request.CreateAppPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	password, err := h.appPasswordService.CreateAppPassword(c.Request.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "应用专用密码数量已达上限" {
+			response.BadRequest(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, "创建应用专用密码失败: "+err.Error())
+		return
+	}
+	response.Success(c, password)
+}
+
+// GetAppPasswords 获取应用专用密码列表
+// @Summary 获取应用专用密码列表
+// @Description 获取当前用户的应用专用密码，不包含密码本身
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]response.AppPasswordResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /users/me/app-passwords [get]
+func (h *AppPasswordHandler) GetAppPasswords(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	passwords, err := h.appPasswordService.GetAppPasswords(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "获取应用专用密码失败: "+err.Error())
+		return
+	}
+	response.Success(c, passwords)
+}
+
+// DeleteAppPassword 吊销应用专用密码
+// @Summary 吊销应用专用密码
+// @Description 删除应用专用密码，使用该密码的客户端将无法继续访问
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "应用专用密码ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /users/me/app-passwords/{id} [delete]
+func (h *AppPasswordHandler) DeleteAppPassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	if err := h.appPasswordService.DeleteAppPassword(c.Request.Context(), uint(id), userID); err != nil {
+		if err.Error() == "应用专用密码不存在" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, "吊销应用专用密码失败: "+err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	attachmentService service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+// UploadAttachment 上传附件
+// @Summary 上传附件
+// @Description 以 multipart/form-data 上传附件，文件字段名为 file，大小与类型受配置限制
+// @Tags 附件
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param file formData file true "附件文件"
+// @Success 200 {object} response.Response{data=response.AttachmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), uint(todoID), userID, file)
+	if err != nil {
+		handleAttachmentError(c, err, "上传失败")
+		return
+	}
+	response.Success(c, attachment)
+}
+
+// GetAttachments 获取附件列表
+// @Summary 获取附件列表
+// @Description 获取待办事项的全部附件
+// @Tags 附件
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=[]response.AttachmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/attachments [get]
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	attachments, err := h.attachmentService.GetAttachments(c.Request.Context(), uint(todoID), userID)
+	if err != nil {
+		handleAttachmentError(c, err, "获取附件失败")
+		return
+	}
+	response.Success(c, attachments)
+}
+
+// DownloadAttachment 下载附件
+// @Summary 下载附件
+// @Description 下载附件文件内容
+// @Tags 附件
+// @Produce octet-stream
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的附件ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	attachment, content, err := h.attachmentService.OpenAttachment(c.Request.Context(), uint(todoID), uint(attachmentID), userID)
+	if err != nil {
+		handleAttachmentError(c, err, "下载失败")
+		return
+	}
+	defer content.Close()
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    disposition,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteAttachment 删除附件
+// @Summary 删除附件
+// @Description 删除附件记录及存储的文件
+// @Tags 附件
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的附件ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	err = h.attachmentService.DeleteAttachment(c.Request.Context(), uint(todoID), uint(attachmentID), userID)
+	if err != nil {
+		handleAttachmentError(c, err, "删除附件失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// handleAttachmentError 将附件服务错误映射为HTTP响应
+func handleAttachmentError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "待办事项不存在", "附件不存在", "附件文件不存在":
+		response.NotFound(c, err.Error())
+	case "无权限访问此待办事项", "无权限修改此待办事项":
+		response.Forbidden(c, err.Error())
+	case "文件不能为空", "文件大小超出限制", "不支持的文件类型":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
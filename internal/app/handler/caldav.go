@@ -0,0 +1,461 @@
+package handler
+
+import (
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/etag"
+	"TODO_API/pkg/webdav"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// davPrefix CalDAV 服务的路径前缀
+	davPrefix = "/caldav"
+	// davCollection 待办事项日历集合的名称
+	davCollection = "todos"
+	// maxCalendarObjectSize 单个日历对象的最大字节数
+	maxCalendarObjectSize = 1 << 20
+	// davAllow OPTIONS 返回的支持方法
+	davAllow = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+	// calendarObjectContentType 日历对象的 Content-Type
+	calendarObjectContentType = "text/calendar; charset=utf-8; component=VTODO"
+)
+
+// davKind CalDAV 资源类型
+type davKind int
+
+const (
+	davRoot      davKind = iota // /caldav/
+	davPrincipal                // /caldav/principals/{username}/
+	davHome                     // /caldav/calendars/{username}/
+	davCalendar                 // /caldav/calendars/{username}/todos/
+	davObject                   // /caldav/calendars/{username}/todos/{name}
+)
+
+// davResource 请求路径对应的资源
+type davResource struct {
+	kind davKind
+	name string // 日历对象的资源名
+}
+
+// 属性名
+var (
+	propResourceType      = xml.Name{Space: webdav.NSDAV, Local: "resourcetype"}
+	propDisplayName       = xml.Name{Space: webdav.NSDAV, Local: "displayname"}
+	propCurrentPrincipal  = xml.Name{Space: webdav.NSDAV, Local: "current-user-principal"}
+	propPrincipalURL      = xml.Name{Space: webdav.NSDAV, Local: "principal-URL"}
+	propOwner             = xml.Name{Space: webdav.NSDAV, Local: "owner"}
+	propGetETag           = xml.Name{Space: webdav.NSDAV, Local: "getetag"}
+	propGetContentType    = xml.Name{Space: webdav.NSDAV, Local: "getcontenttype"}
+	propGetContentLength  = xml.Name{Space: webdav.NSDAV, Local: "getcontentlength"}
+	propSupportedReports  = xml.Name{Space: webdav.NSDAV, Local: "supported-report-set"}
+	propPrivilegeSet      = xml.Name{Space: webdav.NSDAV, Local: "current-user-privilege-set"}
+	propCalendarHomeSet   = xml.Name{Space: webdav.NSCalDAV, Local: "calendar-home-set"}
+	propSupportedComps    = xml.Name{Space: webdav.NSCalDAV, Local: "supported-calendar-component-set"}
+	propCalendarData      = xml.Name{Space: webdav.NSCalDAV, Local: "calendar-data"}
+	propGetCTag           = xml.Name{Space: webdav.NSCalendarSrv, Local: "getctag"}
+	reportCalendarQuery   = xml.Name{Space: webdav.NSCalDAV, Local: "calendar-query"}
+	reportCalendarGet     = xml.Name{Space: webdav.NSCalDAV, Local: "calendar-multiget"}
+	conditionSupportedRpt = xml.Name{Space: webdav.NSDAV, Local: "supported-report"}
+)
+
+// CalDAVHandler CalDAV 服务，将用户的待办事项作为一个只包含 VTODO 的日历集合
+type CalDAVHandler struct {
+	todoService service.TodoService
+}
+
+func NewCalDAVHandler(todoService service.TodoService) *CalDAVHandler {
+	return &CalDAVHandler{todoService: todoService}
+}
+
+func principalHref(username string) string {
+	return davPrefix + "/principals/" + url.PathEscape(username) + "/"
+}
+
+func homeHref(username string) string {
+	return davPrefix + "/calendars/" + url.PathEscape(username) + "/"
+}
+
+func calendarHref(username string) string {
+	return homeHref(username) + davCollection + "/"
+}
+
+func objectHref(username, name string) string {
+	return calendarHref(username) + url.PathEscape(name)
+}
+
+// resolve 解析请求路径，只能访问当前用户自己的资源
+func (h *CalDAVHandler) resolve(c *gin.Context) (davResource, bool) {
+	username := middleware.GetUserNameFromContext(c)
+	parts := strings.Split(strings.Trim(c.Param("path"), "/"), "/")
+
+	var res davResource
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		return davResource{kind: davRoot}, true
+	case len(parts) == 2 && parts[0] == "principals":
+		res.kind = davPrincipal
+	case len(parts) == 2 && parts[0] == "calendars":
+		res.kind = davHome
+	case len(parts) == 3 && parts[0] == "calendars" && parts[2] == davCollection:
+		res.kind = davCalendar
+	case len(parts) == 4 && parts[0] == "calendars" && parts[2] == davCollection && parts[3] != "":
+		res.kind, res.name = davObject, parts[3]
+	default:
+		c.String(http.StatusNotFound, "资源不存在")
+		return res, false
+	}
+	if parts[1] != username {
+		c.String(http.StatusForbidden, "无权限访问其他用户的日历")
+		return res, false
+	}
+	return res, true
+}
+
+// calendarCTag 集合的 CTag，任一对象新增、删除或修改时变化
+func calendarCTag(objects []service.CalendarObject) string {
+	hash := sha256.New()
+	for _, object := range objects {
+		hash.Write([]byte(object.Name + object.ETag + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:16])
+}
+
+// resourceProps 资源的全部属性，calendar-data 只在显式请求时返回
+func (h *CalDAVHandler) resourceProps(username string, res davResource, ctag string,
+	object *service.CalendarObject) []webdav.Property {
+	principal := webdav.Property{Name: propCurrentPrincipal, Inner: webdav.HrefElement(principalHref(username))}
+	switch res.kind {
+	case davRoot:
+		return []webdav.Property{
+			{Name: propResourceType, Inner: webdav.Element(webdav.NSDAV, "collection")},
+			principal,
+		}
+	case davPrincipal:
+		return []webdav.Property{
+			{Name: propResourceType, Inner: webdav.Element(webdav.NSDAV, "collection") + webdav.Element(webdav.NSDAV, "principal")},
+			{Name: propDisplayName, Text: username},
+			principal,
+			{Name: propPrincipalURL, Inner: webdav.HrefElement(principalHref(username))},
+			{Name: propCalendarHomeSet, Inner: webdav.HrefElement(homeHref(username))},
+		}
+	case davHome:
+		return []webdav.Property{
+			{Name: propResourceType, Inner: webdav.Element(webdav.NSDAV, "collection")},
+			{Name: propDisplayName, Text: username},
+			principal,
+			{Name: propOwner, Inner: webdav.HrefElement(principalHref(username))},
+		}
+	case davCalendar:
+		privileges := ""
+		for _, privilege := range []string{"read", "write", "write-content", "write-properties", "bind", "unbind"} {
+			privileges += "<d:privilege>" + webdav.Element(webdav.NSDAV, privilege) + "</d:privilege>"
+		}
+		reports := ""
+		for _, report := range []string{"calendar-multiget", "calendar-query"} {
+			reports += "<d:supported-report><d:report>" + webdav.Element(webdav.NSCalDAV, report) + "</d:report></d:supported-report>"
+		}
+		return []webdav.Property{
+			{Name: propResourceType, Inner: webdav.Element(webdav.NSDAV, "collection") + webdav.Element(webdav.NSCalDAV, "calendar")},
+			{Name: propDisplayName, Text: "待办事项"},
+			principal,
+			{Name: propOwner, Inner: webdav.HrefElement(principalHref(username))},
+			{Name: propSupportedComps, Inner: `<c:comp name="VTODO"/>`},
+			{Name: propSupportedReports, Inner: reports},
+			{Name: propPrivilegeSet, Inner: privileges},
+			{Name: propGetCTag, Text: ctag},
+			{Name: propGetETag, Text: `"` + ctag + `"`},
+		}
+	default:
+		return []webdav.Property{
+			{Name: propResourceType},
+			{Name: propGetETag, Text: object.ETag},
+			{Name: propGetContentType, Text: calendarObjectContentType},
+			{Name: propGetContentLength, Text: strconv.Itoa(len(object.Data))},
+			{Name: propCalendarData, Text: string(object.Data)},
+		}
+	}
+}
+
+// selectProps 按请求筛选属性，返回找到的属性及不支持的属性名
+func selectProps(props []webdav.Property, req *webdav.Request) ([]webdav.Property, []xml.Name) {
+	if req.AllProp || req.PropName {
+		var found []webdav.Property
+		for _, prop := range props {
+			if prop.Name == propCalendarData {
+				continue
+			}
+			if req.PropName {
+				prop = webdav.Property{Name: prop.Name}
+			}
+			found = append(found, prop)
+		}
+		return found, nil
+	}
+
+	var found []webdav.Property
+	var notFound []xml.Name
+	for _, name := range req.Props {
+		ok := false
+		for _, prop := range props {
+			if prop.Name == name {
+				found = append(found, prop)
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			notFound = append(notFound, name)
+		}
+	}
+	return found, notFound
+}
+
+// davResponse 生成单个资源的 multistatus 响应
+func (h *CalDAVHandler) davResponse(username, href string, res davResource, ctag string,
+	object *service.CalendarObject, req *webdav.Request) webdav.Response {
+	found, notFound := selectProps(h.resourceProps(username, res, ctag, object), req)
+	return webdav.Response{Href: href, Found: found, NotFound: notFound}
+}
+
+// Options 返回支持的方法，无需认证，供客户端探测服务能力
+func (h *CalDAVHandler) Options(c *gin.Context) {
+	c.Header("DAV", "1, 3, calendar-access")
+	c.Header("Allow", davAllow)
+	c.Status(http.StatusOK)
+}
+
+// WellKnown 服务发现，重定向到 CalDAV 根路径
+func (h *CalDAVHandler) WellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, davPrefix+"/")
+}
+
+// Propfind 查询资源属性，Depth 为 1 时包含直接子资源
+func (h *CalDAVHandler) Propfind(c *gin.Context) {
+	res, ok := h.resolve(c)
+	if !ok {
+		return
+	}
+	req, err := webdav.ParseRequest(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	username := middleware.GetUserNameFromContext(c)
+	depth := c.GetHeader("Depth")
+	children := depth == "1" || strings.EqualFold(depth, "infinity")
+
+	var responses []webdav.Response
+	switch res.kind {
+	case davRoot:
+		responses = append(responses, h.davResponse(username, davPrefix+"/", res, "", nil, req))
+	case davPrincipal:
+		responses = append(responses, h.davResponse(username, principalHref(username), res, "", nil, req))
+	case davHome, davCalendar:
+		objects, err := h.todoService.ListCalendarObjects(c.Request.Context(), middleware.GetUserIDFromContext(c))
+		if err != nil {
+			c.String(http.StatusInternalServerError, "获取日历失败: "+err.Error())
+			return
+		}
+		ctag := calendarCTag(objects)
+		calendar := davResource{kind: davCalendar}
+		if res.kind == davHome {
+			responses = append(responses, h.davResponse(username, homeHref(username), res, "", nil, req))
+			if children {
+				responses = append(responses, h.davResponse(username, calendarHref(username), calendar, ctag, nil, req))
+			}
+			break
+		}
+		responses = append(responses, h.davResponse(username, calendarHref(username), calendar, ctag, nil, req))
+		if children {
+			for i := range objects {
+				object := &objects[i]
+				responses = append(responses, h.davResponse(username, objectHref(username, object.Name),
+					davResource{kind: davObject, name: object.Name}, "", object, req))
+			}
+		}
+	case davObject:
+		object, err := h.todoService.GetCalendarObject(c.Request.Context(), middleware.GetUserIDFromContext(c), res.name)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		responses = append(responses, h.davResponse(username, objectHref(username, object.Name), res, "", object, req))
+	}
+	webdav.WriteMultiStatus(c.Writer, responses)
+}
+
+// Report 支持 calendar-multiget 与 calendar-query，calendar-query 只按组件类型筛选
+func (h *CalDAVHandler) Report(c *gin.Context) {
+	res, ok := h.resolve(c)
+	if !ok {
+		return
+	}
+	req, err := webdav.ParseRequest(c.Request.Body)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+	if res.kind != davCalendar || (req.Name != reportCalendarQuery && req.Name != reportCalendarGet) {
+		webdav.WriteError(c.Writer, http.StatusForbidden, conditionSupportedRpt, "不支持的报告类型")
+		return
+	}
+
+	objects, err := h.todoService.ListCalendarObjects(c.Request.Context(), middleware.GetUserIDFromContext(c))
+	if err != nil {
+		c.String(http.StatusInternalServerError, "获取日历失败: "+err.Error())
+		return
+	}
+	username := middleware.GetUserNameFromContext(c)
+
+	var responses []webdav.Response
+	if req.Name == reportCalendarGet {
+		byName := make(map[string]*service.CalendarObject, len(objects))
+		for i := range objects {
+			byName[objects[i].Name] = &objects[i]
+		}
+		for _, href := range req.Hrefs {
+			name := href
+			if u, err := url.Parse(href); err == nil {
+				name = u.Path
+			}
+			name = name[strings.LastIndex(name, "/")+1:]
+			if unescaped, err := url.PathUnescape(name); err == nil {
+				name = unescaped
+			}
+			object, ok := byName[name]
+			if !ok {
+				responses = append(responses, webdav.Response{Href: href, Status: http.StatusNotFound})
+				continue
+			}
+			responses = append(responses, h.davResponse(username, href, davResource{kind: davObject, name: name}, "", object, req))
+		}
+	} else if onlyTodos(req.CompFilters) {
+		for i := range objects {
+			object := &objects[i]
+			responses = append(responses, h.davResponse(username, objectHref(username, object.Name),
+				davResource{kind: davObject, name: object.Name}, "", object, req))
+		}
+	}
+	webdav.WriteMultiStatus(c.Writer, responses)
+}
+
+// onlyTodos calendar-query 是否只查询 VTODO，查询 VEVENT 等其他组件时结果为空
+func onlyTodos(compFilters []string) bool {
+	for _, name := range compFilters {
+		if name != "VCALENDAR" && name != "VTODO" {
+			return false
+		}
+	}
+	return true
+}
+
+// Get 获取日历对象，对集合 GET 时返回全部待办事项
+func (h *CalDAVHandler) Get(c *gin.Context) {
+	res, ok := h.resolve(c)
+	if !ok {
+		return
+	}
+	userID := middleware.GetUserIDFromContext(c)
+	switch res.kind {
+	case davCalendar:
+		data, err := h.todoService.ExportCalendar(c.Request.Context(), userID)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "获取日历失败: "+err.Error())
+			return
+		}
+		c.Data(http.StatusOK, calendarContentType, data)
+	case davObject:
+		object, err := h.todoService.GetCalendarObject(c.Request.Context(), userID, res.name)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		c.Header("ETag", object.ETag)
+		if etag.WeakMatch(c.GetHeader("If-None-Match"), object.ETag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
+		c.Data(http.StatusOK, calendarObjectContentType, object.Data)
+	default:
+		c.String(http.StatusMethodNotAllowed, "该资源不支持 GET")
+	}
+}
+
+// Put 创建或更新日历对象，保存的内容会被规范化，因此不返回 ETag，客户端需重新获取
+func (h *CalDAVHandler) Put(c *gin.Context) {
+	res, ok := h.resolve(c)
+	if !ok {
+		return
+	}
+	if res.kind != davObject {
+		c.String(http.StatusMethodNotAllowed, "只能对日历对象使用 PUT")
+		return
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxCalendarObjectSize)
+	created, err := h.todoService.PutCalendarObject(c.Request.Context(), middleware.GetUserIDFromContext(c),
+		res.name, body, c.GetHeader("If-Match"), c.GetHeader("If-None-Match"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if created {
+		c.Status(http.StatusCreated)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Delete 删除日历对象，对应的待办事项移入回收站
+func (h *CalDAVHandler) Delete(c *gin.Context) {
+	res, ok := h.resolve(c)
+	if !ok {
+		return
+	}
+	if res.kind != davObject {
+		c.String(http.StatusForbidden, "不能删除日历集合")
+		return
+	}
+	err := h.todoService.DeleteCalendarObject(c.Request.Context(), middleware.GetUserIDFromContext(c),
+		res.name, c.GetHeader("If-Match"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// handleError 将服务错误映射为 CalDAV 响应，前置条件错误按 RFC 4791 返回 DAV:error
+func (h *CalDAVHandler) handleError(c *gin.Context, err error) {
+	msg := err.Error()
+	switch {
+	case msg == "日历对象不存在":
+		c.String(http.StatusNotFound, msg)
+	case msg == "日历对象已被修改", msg == "数据已被修改":
+		c.String(http.StatusPreconditionFailed, msg)
+	case msg == "仅支持 VTODO":
+		webdav.WriteError(c.Writer, http.StatusForbidden,
+			xml.Name{Space: webdav.NSCalDAV, Local: "supported-calendar-component"}, msg)
+	case msg == "UID 已被其他日历对象使用":
+		webdav.WriteError(c.Writer, http.StatusConflict,
+			xml.Name{Space: webdav.NSCalDAV, Local: "no-uid-conflict"}, msg)
+	case strings.HasPrefix(msg, "日历数据无效"):
+		webdav.WriteError(c.Writer, http.StatusForbidden,
+			xml.Name{Space: webdav.NSCalDAV, Local: "valid-calendar-data"}, msg)
+	case msg == "存在未完成的子任务" || msg == "存在未完成的前置任务" || msg == "不允许的状态流转":
+		c.String(http.StatusConflict, msg)
+	case strings.Contains(msg, "request body too large"):
+		c.String(http.StatusRequestEntityTooLarge, "日历对象过大")
+	default:
+		c.String(http.StatusInternalServerError, "操作失败: "+msg)
+	}
+}
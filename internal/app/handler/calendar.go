@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// calendarContentType iCalendar 数据的 Content-Type
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+func NewCalendarHandler(calendarService service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// feedURL 根据当前请求生成订阅地址，经反向代理时使用 X-Forwarded-Proto
+func feedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + "/api/calendar/feed/" + token + ".ics"
+}
+
+// GetToken 获取日历订阅状态
+// @Summary 获取日历订阅状态
+// @Description 返回是否已开启日历订阅，订阅地址只在生成时返回
+// @Tags 日历
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=response.CalendarTokenResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/token [get]
+func (h *CalendarHandler) GetToken(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	token, err := h.calendarService.GetToken(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "获取订阅状态失败: "+err.Error())
+		return
+	}
+	response.Success(c, token)
+}
+
+// RotateToken 生成订阅地址
+// @Summary 生成订阅地址
+// @Description 生成新的日历订阅令牌及订阅地址，旧地址立即失效；令牌只返回这一次，请妥善保存
+// @Tags 日历
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=response.CalendarTokenResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/token [post]
+func (h *CalendarHandler) RotateToken(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	token, err := h.calendarService.RotateToken(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "生成订阅地址失败: "+err.Error())
+		return
+	}
+	token.FeedURL = feedURL(c, token.Token)
+	response.Success(c, token)
+}
+
+// RevokeToken 停用日历订阅
+// @Summary 停用日历订阅
+// @Description 删除订阅令牌，已订阅的日历客户端将无法继续获取数据
+// @Tags 日历
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/token [delete]
+func (h *CalendarHandler) RevokeToken(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	if err := h.calendarService.RevokeToken(c.Request.Context(), userID); err != nil {
+		response.InternalServerError(c, "停用订阅失败: "+err.Error())
+		return
+	}
+	response.Success(c, nil)
+}
+
+// Feed 日历订阅
+// @Summary 日历订阅
+// @Description 供日历客户端订阅的 iCalendar 数据，使用地址中的令牌认证，无需 JWT
+// @Tags 日历
+// @Produce text/calendar
+// @Param token path string true "订阅令牌，可带 .ics 后缀"
+// @Success 200 {string} string "iCalendar 数据"
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/feed/{token} [get]
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	data, err := h.calendarService.Feed(c.Request.Context(), token)
+	if err != nil {
+		if err.Error() == "订阅地址无效" {
+			response.NotFound(c, err.Error())
+			return
+		}
+		response.InternalServerError(c, "获取日历失败: "+err.Error())
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, calendarContentType, data)
+}
+
+// Export 导出日历
+// @Summary 导出日历
+// @Description 将当前用户的全部待办事项导出为 .ics 文件(VTODO)
+// @Tags 日历
+// @Produce text/calendar
+// @Security Bearer
+// @Success 200 {string} string "iCalendar 数据"
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/export [get]
+func (h *CalendarHandler) Export(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	data, err := h.calendarService.Export(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "导出失败: "+err.Error())
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="todos.ics"`)
+	c.Data(http.StatusOK, calendarContentType, data)
+}
+
+// Import 导入日历
+// @Summary 导入日历
+// @Description 从 .ics 文件导入 VTODO 到收件箱，已存在的 UID 及已取消的任务跳过，返回每个任务的导入结果
+// @Tags 日历
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true ".ics 文件"
+// @Success 200 {object} response.Response{data=response.CalendarImportResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /calendar/import [post]
+func (h *CalendarHandler) Import(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	result, err := h.calendarService.Import(c.Request.Context(), userID, file)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case msg == "文件不能为空", msg == "文件大小超出限制", msg == "日历文件中没有待办事项",
+			strings.HasPrefix(msg, "日历文件无效"), strings.HasPrefix(msg, "单次最多导入"):
+			response.BadRequest(c, msg)
+		default:
+			response.InternalServerError(c, "导入失败: "+msg)
+		}
+		return
+	}
+	response.Success(c, result)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService service.CommentService
+}
+
+func NewCommentHandler(commentService service.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// CreateComment 发表评论
+// @Summary 发表评论
+// @Description 在待办事项下发表评论，可查看该待办事项的用户均可评论
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param request body request.CreateCommentRequest true "创建评论请求"
+// @Success 200 {object} response.Response{data=response.CommentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	comment, err := h.commentService.CreateComment(c.Request.Context(), uint(todoID), userID, &req)
+	if err != nil {
+		handleCommentError(c, err, "发表评论失败")
+		return
+	}
+	response.Success(c, comment)
+}
+
+// GetComments 获取评论列表
+// @Summary 获取评论列表
+// @Description 分页获取待办事项的评论，按发表时间正序
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=response.CommentListResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/comments [get]
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var query request.CommentQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	comments, err := h.commentService.GetComments(c.Request.Context(), uint(todoID), userID, &query)
+	if err != nil {
+		handleCommentError(c, err, "获取评论失败")
+		return
+	}
+	response.Success(c, comments)
+}
+
+// UpdateComment 编辑评论
+// @Summary 编辑评论
+// @Description 编辑评论内容，只有评论作者或待办事项所有者可以编辑
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param comment_id path int true "评论ID"
+// @Param request body request.UpdateCommentRequest true "编辑评论请求"
+// @Success 200 {object} response.Response{data=response.CommentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的评论ID")
+		return
+	}
+
+	var req request.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	comment, err := h.commentService.UpdateComment(c.Request.Context(), uint(todoID), uint(commentID), userID, &req)
+	if err != nil {
+		handleCommentError(c, err, "编辑评论失败")
+		return
+	}
+	response.Success(c, comment)
+}
+
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 删除评论，只有评论作者或待办事项所有者可以删除
+// @Tags 评论
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param comment_id path int true "评论ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的评论ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	err = h.commentService.DeleteComment(c.Request.Context(), uint(todoID), uint(commentID), userID)
+	if err != nil {
+		handleCommentError(c, err, "删除评论失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// handleCommentError 将评论服务错误映射为HTTP响应
+func handleCommentError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "待办事项不存在", "评论不存在":
+		response.NotFound(c, err.Error())
+	case "无权限访问此待办事项", "无权限修改此评论":
+		response.Forbidden(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"TODO_API/internal/service"
+	"TODO_API/pkg/etag"
+	"TODO_API/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requireIfMatch 获取 If-Match 请求头，修改前必须先获取资源并带上其 ETag，缺少时返回 428
+func requireIfMatch(c *gin.Context) (string, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		response.PreconditionRequired(c, "缺少 If-Match 请求头，请先获取资源的 ETag")
+		return "", false
+	}
+	return ifMatch, true
+}
+
+// notModified 按响应内容设置 ETag 响应头，If-None-Match 弱比较匹配时返回 304，调用方不再写入响应体
+func notModified(c *gin.Context, v any) bool {
+	tag := service.ResponseETag(v)
+	c.Header("ETag", tag)
+	if etag.WeakMatch(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
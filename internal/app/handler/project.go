@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectHandler struct {
+	projectService service.ProjectService
+}
+
+func NewProjectHandler(projectService service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{projectService: projectService}
+}
+
+// CreateProject 创建项目
+// @Summary 创建项目
+// @Description 为当前用户创建新的项目(清单)
+// @Tags 项目
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body request.CreateProjectRequest true "创建项目请求"
+// @Success 200 {object} response.Response{data=response.ProjectResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var req request.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	project, err := h.projectService.CreateProject(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleProjectError(c, err, "创建失败")
+		return
+	}
+	response.Success(c, project)
+}
+
+// GetProjects 获取项目列表
+// @Summary 获取项目列表
+// @Description 获取当前用户的全部项目
+// @Tags 项目
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]response.ProjectResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /projects [get]
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	projects, err := h.projectService.GetProjects(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "获取列表失败"+err.Error())
+		return
+	}
+	response.Success(c, projects)
+}
+
+// GetProjectByID 获取项目详情
+// @Summary 获取项目详情
+// @Description 根据ID获取项目详情及统计信息
+// @Tags 项目
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {object} response.Response{data=response.ProjectResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /projects/{id} [get]
+func (h *ProjectHandler) GetProjectByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	project, err := h.projectService.GetProjectByID(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		handleProjectError(c, err, "获取失败")
+		return
+	}
+	response.Success(c, project)
+}
+
+// UpdateProject 更新项目
+// @Summary 更新项目
+// @Description 修改项目名称、描述或颜色
+// @Tags 项目
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param request body request.UpdateProjectRequest true "更新项目请求"
+// @Success 200 {object} response.Response{data=response.ProjectResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	project, err := h.projectService.UpdateProject(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		handleProjectError(c, err, "更新失败")
+		return
+	}
+	response.Success(c, project)
+}
+
+// DeleteProject 删除项目
+// @Summary 删除项目
+// @Description 删除项目，mode=inbox 时待办事项移入收件箱，mode=delete 时一并删除
+// @Tags 项目
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Param mode query string false "待办事项处理方式: inbox, delete" default(inbox)
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.DeleteProjectRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	err = h.projectService.DeleteProject(c.Request.Context(), uint(id), userID, req.Mode == "delete")
+	if err != nil {
+		handleProjectError(c, err, "删除失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetProjectStatistics 获取项目统计
+// @Summary 获取项目统计
+// @Description 统计项目内各状态的待办事项数量
+// @Tags 项目
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "项目ID"
+// @Success 200 {object} response.Response{data=response.Statistics}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /projects/{id}/stats [get]
+func (h *ProjectHandler) GetProjectStatistics(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	stats, err := h.projectService.GetProjectStatistics(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		handleProjectError(c, err, "获取统计失败")
+		return
+	}
+	response.Success(c, stats)
+}
+
+// handleProjectError 将项目服务错误映射为HTTP响应
+func handleProjectError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "项目不存在":
+		response.NotFound(c, err.Error())
+	case "无权限访问此项目":
+		response.Forbidden(c, err.Error())
+	case "项目名已存在":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReminderHandler struct {
+	reminderService service.ReminderService
+}
+
+func NewReminderHandler(reminderService service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{reminderService: reminderService}
+}
+
+// CreateReminder 创建提醒
+// @Summary 创建提醒
+// @Description 为待办事项创建提醒，可指定绝对时间(remind_at)或截止时间前的分钟数(offset_minutes)
+// @Tags 提醒
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param request body request.CreateReminderRequest true "创建提醒请求"
+// @Success 200 {object} response.Response{data=response.ReminderResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/reminders [post]
+func (h *ReminderHandler) CreateReminder(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.CreateReminderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	reminder, err := h.reminderService.CreateReminder(c.Request.Context(), uint(todoID), userID, &req)
+	if err != nil {
+		handleReminderError(c, err, "创建提醒失败")
+		return
+	}
+	response.Success(c, reminder)
+}
+
+// GetReminders 获取提醒列表
+// @Summary 获取提醒列表
+// @Description 获取待办事项的全部提醒
+// @Tags 提醒
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=[]response.ReminderResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/reminders [get]
+func (h *ReminderHandler) GetReminders(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	reminders, err := h.reminderService.GetReminders(c.Request.Context(), uint(todoID), userID)
+	if err != nil {
+		handleReminderError(c, err, "获取提醒失败")
+		return
+	}
+	response.Success(c, reminders)
+}
+
+// DeleteReminder 删除提醒
+// @Summary 删除提醒
+// @Description 删除待办事项上的指定提醒
+// @Tags 提醒
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param reminder_id path int true "提醒ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/reminders/{reminder_id} [delete]
+func (h *ReminderHandler) DeleteReminder(c *gin.Context) {
+	todoID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+	reminderID, err := strconv.ParseUint(c.Param("reminder_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的提醒ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	err = h.reminderService.DeleteReminder(c.Request.Context(), uint(todoID), uint(reminderID), userID)
+	if err != nil {
+		handleReminderError(c, err, "删除提醒失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// handleReminderError 将提醒服务错误映射为HTTP响应
+func handleReminderError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "待办事项不存在", "提醒不存在":
+		response.NotFound(c, err.Error())
+	case "无权限访问此待办事项":
+		response.Forbidden(c, err.Error())
+	case "待办事项未设置截止时间", "提醒时间已过":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
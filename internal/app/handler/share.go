@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShareHandler struct {
+	shareService service.ShareService
+}
+
+func NewShareHandler(shareService service.ShareService) *ShareHandler {
+	return &ShareHandler{shareService: shareService}
+}
+
+// CreateShare 创建共享
+// @Summary 创建共享
+// @Description 将待办事项或项目以 viewer、editor 或 owner 角色共享给其他用户
+// @Tags 共享
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body request.CreateShareRequest true "创建共享请求"
+// @Success 200 {object} response.Response{data=response.ShareResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /shares [post]
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	var req request.CreateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	share, err := h.shareService.CreateShare(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleShareError(c, err, "共享失败")
+		return
+	}
+	response.Success(c, share)
+}
+
+// GetShares 获取资源的共享列表
+// @Summary 获取资源的共享列表
+// @Description 获取待办事项或项目的共享用户，需要 owner 角色
+// @Tags 共享
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param resource_type query string true "资源类型: todo, project"
+// @Param resource_id query int true "资源ID"
+// @Success 200 {object} response.Response{data=[]response.ShareResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /shares [get]
+func (h *ShareHandler) GetShares(c *gin.Context) {
+	var query request.ShareQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	shares, err := h.shareService.GetShares(c.Request.Context(), userID, &query)
+	if err != nil {
+		handleShareError(c, err, "获取列表失败")
+		return
+	}
+	response.Success(c, shares)
+}
+
+// GetSharedWithMe 获取共享给我的资源
+// @Summary 获取共享给我的资源
+// @Description 列出其他用户共享给当前用户的待办事项与项目
+// @Tags 共享
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]response.ShareResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /shares/with-me [get]
+func (h *ShareHandler) GetSharedWithMe(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	shares, err := h.shareService.GetSharedWithMe(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "获取列表失败"+err.Error())
+		return
+	}
+	response.Success(c, shares)
+}
+
+// UpdateShare 修改共享角色
+// @Summary 修改共享角色
+// @Description 修改被共享用户的角色，需要 owner 角色
+// @Tags 共享
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "共享ID"
+// @Param request body request.UpdateShareRequest true "更新共享请求"
+// @Success 200 {object} response.Response{data=response.ShareResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /shares/{id} [put]
+func (h *ShareHandler) UpdateShare(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.UpdateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	share, err := h.shareService.UpdateShare(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		handleShareError(c, err, "更新失败")
+		return
+	}
+	response.Success(c, share)
+}
+
+// DeleteShare 取消共享
+// @Summary 取消共享
+// @Description 资源的 owner 取消共享，或被共享用户主动退出
+// @Tags 共享
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "共享ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /shares/{id} [delete]
+func (h *ShareHandler) DeleteShare(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	if err := h.shareService.DeleteShare(c.Request.Context(), uint(id), userID); err != nil {
+		handleShareError(c, err, "删除失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// handleShareError 将共享服务错误映射为HTTP响应
+func handleShareError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "共享不存在", "用户不存在", "待办事项不存在", "项目不存在":
+		response.NotFound(c, err.Error())
+	case "无权限管理此共享":
+		response.Forbidden(c, err.Error())
+	case "已共享给该用户", "不能共享给自己或资源所有者", "不能修改自己的共享角色", "不支持的共享资源类型":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
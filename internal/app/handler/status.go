@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatusHandler struct {
+	statusService service.StatusService
+}
+
+func NewStatusHandler(statusService service.StatusService) *StatusHandler {
+	return &StatusHandler{statusService: statusService}
+}
+
+// GetWorkflow 获取状态工作流
+// @Summary 获取状态工作流
+// @Description 列出待办、进行中、已完成三个分类，每个分类允许流转到的其他分类，以及当前用户在该分类下的自定义状态
+// @Tags 状态
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=response.WorkflowResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /statuses [get]
+func (h *StatusHandler) GetWorkflow(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	workflow, err := h.statusService.GetWorkflow(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "获取状态失败"+err.Error())
+		return
+	}
+	response.Success(c, workflow)
+}
+
+// CreateStatus 创建自定义状态
+// @Summary 创建自定义状态
+// @Description 在指定分类下创建自定义状态，如进行中分类下的“待审核”。待办事项使用自定义状态时按其分类参与状态流转、统计与看板
+// @Tags 状态
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body request.CreateStatusRequest true "创建自定义状态请求"
+// @Success 200 {object} response.Response{data=response.StatusResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /statuses [post]
+func (h *StatusHandler) CreateStatus(c *gin.Context) {
+	var req request.CreateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	status, err := h.statusService.CreateStatus(c.Request.Context(), userID, &req)
+	if err != nil {
+		handleStatusError(c, err, "创建失败")
+		return
+	}
+	response.Success(c, status)
+}
+
+// UpdateStatus 更新自定义状态
+// @Summary 更新自定义状态
+// @Description 修改自定义状态的名称、颜色或排序位置，所属分类不能修改
+// @Tags 状态
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "自定义状态ID"
+// @Param request body request.UpdateStatusRequest true "更新自定义状态请求"
+// @Success 200 {object} response.Response{data=response.StatusResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /statuses/{id} [put]
+func (h *StatusHandler) UpdateStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.UpdateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	status, err := h.statusService.UpdateStatus(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		handleStatusError(c, err, "更新失败")
+		return
+	}
+	response.Success(c, status)
+}
+
+// DeleteStatus 删除自定义状态
+// @Summary 删除自定义状态
+// @Description 删除自定义状态，使用该状态的待办事项保留所属分类
+// @Tags 状态
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "自定义状态ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /statuses/{id} [delete]
+func (h *StatusHandler) DeleteStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	if err := h.statusService.DeleteStatus(c.Request.Context(), uint(id), userID); err != nil {
+		handleStatusError(c, err, "删除失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// handleStatusError 将自定义状态服务错误映射为HTTP响应
+func handleStatusError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "状态不存在":
+		response.NotFound(c, err.Error())
+	case "状态名已存在", "自定义状态数量已达上限":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
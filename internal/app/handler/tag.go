@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/middleware"
+	"TODO_API/internal/service"
+	"TODO_API/pkg/response"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService service.TagService
+}
+
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{tagService: tagService}
+}
+
+// CreateTag 创建标签
+// @Summary 创建标签
+// @Description 为当前用户创建新标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body request.CreateTagRequest true "创建标签请求"
+// @Success 200 {object} response.Response{data=response.TagResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req request.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	tag, err := h.tagService.CreateTag(c.Request.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "标签名已存在" {
+			response.BadRequest(c, err.Error())
+		} else {
+			response.InternalServerError(c, "创建失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, tag)
+}
+
+// GetTags 获取标签列表
+// @Summary 获取标签列表
+// @Description 获取当前用户的全部标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} response.Response{data=[]response.TagResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	tags, err := h.tagService.GetTags(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "获取列表失败"+err.Error())
+		return
+	}
+	response.Success(c, tags)
+}
+
+// GetTagByID 获取标签详情
+// @Summary 获取标签详情
+// @Description 根据ID获取标签详情
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Response{data=response.TagResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetTagByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	tag, err := h.tagService.GetTagByID(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		handleTagError(c, err, "获取失败")
+		return
+	}
+	response.Success(c, tag)
+}
+
+// UpdateTag 更新标签
+// @Summary 更新标签
+// @Description 修改标签名称或颜色
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "标签ID"
+// @Param request body request.UpdateTagRequest true "更新标签请求"
+// @Success 200 {object} response.Response{data=response.TagResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	tag, err := h.tagService.UpdateTag(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		handleTagError(c, err, "更新失败")
+		return
+	}
+	response.Success(c, tag)
+}
+
+// DeleteTag 删除标签
+// @Summary 删除标签
+// @Description 删除标签，同时移除其与待办事项的关联
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "标签ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	if err := h.tagService.DeleteTag(c.Request.Context(), uint(id), userID); err != nil {
+		handleTagError(c, err, "删除失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// handleTagError 将标签服务错误映射为HTTP响应
+func handleTagError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "标签不存在":
+		response.NotFound(c, err.Error())
+	case "无权限访问此标签":
+		response.Forbidden(c, err.Error())
+	case "标签名已存在":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"TODO_API/internal/app/dto/request"
 	"TODO_API/internal/app/middleware"
 	"TODO_API/internal/service"
+	"TODO_API/pkg/logger"
 	"TODO_API/pkg/response"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
 type TodoHandler struct {
@@ -41,7 +46,13 @@ func (h *TodoHandler) CreateTodo(c *gin.Context) {
 
 	todo, err := h.todoService.Create(c.Request.Context(), userID, &req)
 	if err != nil {
-		response.InternalServerError(c, "创建失败"+err.Error())
+		if isRecurrenceError(err) {
+			response.BadRequest(c, err.Error())
+		} else if err.Error() == "项目不存在" {
+			response.NotFound(c, err.Error())
+		} else {
+			response.InternalServerError(c, "创建失败"+err.Error())
+		}
 		return
 	}
 
@@ -50,13 +61,15 @@ func (h *TodoHandler) CreateTodo(c *gin.Context) {
 
 // GetTodoByID 获取待办事项详情
 // @Summary 获取待办事项详情
-// @Description 根据ID获取特定的待办事项详情
+// @Description 根据ID获取特定的待办事项详情。响应头 ETag 由响应内容生成，请求头 If-None-Match 与之匹配时返回 304
 // @Tags 待办事项
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path int true "待办事项ID"
+// @Param If-None-Match header string false "上次获取时的 ETag"
 // @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Success 304 "未修改"
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
@@ -83,6 +96,9 @@ func (h *TodoHandler) GetTodoByID(c *gin.Context) {
 		}
 		return
 	}
+	if notModified(c, todo) {
+		return
+	}
 	response.Success(c, todo)
 }
 
@@ -97,9 +113,17 @@ func (h *TodoHandler) GetTodoByID(c *gin.Context) {
 // @Param page_size query int false "每页数量" default(10)
 // @Param status query int false "状态筛选: 0-待办, 1-进行中, 2-已完成"
 // @Param priority query int false "优先级筛选: 1-低, 2-中, 3-高, 4-紧急"
-// @Param keyword query string false "关键词搜索"
+// @Param tag query int false "标签ID筛选"
+// @Param project query int false "项目ID筛选，0 表示收件箱"
+// @Param keyword query string false "关键词，通过全文搜索匹配，语法与 /todos/search 的 q 相同"
+// @Param filter query string false "筛选表达式，如 status in (0,1) and priority >= 3 and due < 2026-11-01 and overdue。字段: status, priority, project, tag, due, created, updated, completed；条件: overdue, has_due, no_due；支持 and, or, not 与括号"
+// @Param sort query string false "排序字段，逗号分隔，字段前加 - 表示降序，可选 due_date, priority, status, created_at, updated_at, title；默认 due_date,-priority,-created_at"
+// @Param cursor query string false "游标，取自上一次响应的 cursors.next 或 cursors.prev，设置后忽略 page"
+// @Param with_stats query bool false "是否返回统计信息" default(false)
 // @Success 200 {object} response.Response{data=response.TodoListResponse}
+// @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /todos [get]
 func (h *TodoHandler) GetTodos(c *gin.Context) {
@@ -112,27 +136,303 @@ func (h *TodoHandler) GetTodos(c *gin.Context) {
 
 	todos, err := h.todoService.GetTodos(c.Request.Context(), userID, &query)
 	if err != nil {
-		response.InternalServerError(c, "获取列表失败"+err.Error())
+		handleQueryError(c, err, "获取列表失败")
 		return
 	}
 
 	response.Success(c, todos)
 }
 
+// SearchTodos 全文搜索待办事项
+// @Summary 全文搜索待办事项
+// @Description 在标题和描述中搜索，结果按相关度排序并附带高亮片段。多个词须同时命中，"双引号"包裹的内容按短语匹配，以 * 结尾的词按前缀匹配
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param q query string true "搜索关键词"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=response.SearchTodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/search [get]
+func (h *TodoHandler) SearchTodos(c *gin.Context) {
+	var query request.SearchTodoRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	result, err := h.todoService.SearchTodos(c.Request.Context(), userID, &query)
+	if err != nil {
+		handleQueryError(c, err, "搜索失败")
+		return
+	}
+	response.Success(c, result)
+}
+
+// GetStats 获取统计分析
+// @Summary 获取统计分析
+// @Description 按状态与优先级计数、过期数量，以及统计区间内的完成率、平均完成耗时和每日创建/完成数量，只统计顶层任务
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param from query string false "开始日期(含)，格式 2006-01-02，默认结束日期前29天"
+// @Param to query string false "结束日期(含)，格式 2006-01-02，默认今天"
+// @Param project query int false "项目ID，0 表示收件箱"
+// @Success 200 {object} response.Response{data=response.TodoStatsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/stats [get]
+func (h *TodoHandler) GetStats(c *gin.Context) {
+	var query request.TodoStatsRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	stats, err := h.todoService.GetStats(c.Request.Context(), userID, &query)
+	if err != nil {
+		switch err.Error() {
+		case "项目不存在":
+			response.NotFound(c, err.Error())
+		case "开始日期不能晚于结束日期", "统计区间不能超过366天":
+			response.BadRequest(c, err.Error())
+		default:
+			response.InternalServerError(c, "获取统计失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, stats)
+}
+
+// 导出格式对应的 Content-Type
+var exportContentTypes = map[string]string{
+	"json": "application/json; charset=utf-8",
+	"csv":  "text/csv; charset=utf-8",
+	"md":   "text/markdown; charset=utf-8",
+}
+
+// ExportTodos 导出待办事项
+// @Summary 导出待办事项
+// @Description 流式导出当前用户的全部待办事项(含子任务)，json/csv 可重新导入，md 为便于阅读的任务列表
+// @Tags 待办事项
+// @Produce json
+// @Produce text/csv
+// @Produce text/markdown
+// @Security Bearer
+// @Param format query string false "导出格式 json|csv|md，默认 json"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/export [get]
+func (h *TodoHandler) ExportTodos(c *gin.Context) {
+	var query request.ExportTodosRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	filename := "todos-" + time.Now().Format("20060102") + "." + query.Format
+	c.Header("Content-Type", exportContentTypes[query.Format])
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err := h.todoService.ExportTodos(c.Request.Context(), userID, query.Format, c.Writer); err != nil {
+		//已开始输出时无法再返回错误响应，只能记录日志
+		if c.Writer.Written() {
+			logger.Error("导出待办事项中断", zap.Uint("UserID", userID), zap.Error(err))
+			return
+		}
+		c.Writer.Header().Del("Content-Disposition")
+		response.InternalServerError(c, "导出失败: "+err.Error())
+	}
+}
+
+// ImportTodos 导入待办事项
+// @Summary 导入待办事项
+// @Description 从导出的 json/csv 文件导入待办事项，parent_id 可引用文件中的 id 或已有的顶层任务，未设置 project_id 时可用 project 指定项目名(不存在则创建)。任一行校验失败时不写入任何数据并返回每行的错误；dry_run 只校验不写入，校验通过时在一个事务中全部写入
+// @Tags 待办事项
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "json 或 csv 文件"
+// @Param format query string false "文件格式 json|csv，默认按扩展名判断"
+// @Param dry_run query bool false "只校验不写入"
+// @Success 200 {object} response.Response{data=response.TodoImportResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/import [post]
+func (h *TodoHandler) ImportTodos(c *gin.Context) {
+	var query request.ImportTodosRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	result, err := h.todoService.ImportTodos(c.Request.Context(), userID, &query, file)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case msg == "文件不能为空", msg == "文件大小超出限制", msg == "文件中没有待办事项",
+			msg == "无法识别文件格式，请指定 format",
+			strings.HasPrefix(msg, "文件格式无效"), strings.HasPrefix(msg, "单次最多导入"):
+			response.BadRequest(c, msg)
+		default:
+			response.InternalServerError(c, "导入失败: "+msg)
+		}
+		return
+	}
+	response.Success(c, result)
+}
+
+// MigrateTodos 从其他待办工具迁移
+// @Summary 从其他待办工具迁移
+// @Description 导入 Todoist(项目 CSV 或 Sync API JSON)、Trello(看板 JSON)、Microsoft To Do(Graph JSON)的导出文件，项目按名称匹配或创建，标签、优先级、截止时间及完成状态映射到待办事项；无法映射的内容列在 unmapped 中。校验及事务与导入相同
+// @Tags 待办事项
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param source path string true "来源 todoist|trello|mstodo"
+// @Param file formData file true "导出文件"
+// @Param dry_run query bool false "只校验不写入"
+// @Success 200 {object} response.Response{data=response.TodoMigrationResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/import/{source} [post]
+func (h *TodoHandler) MigrateTodos(c *gin.Context) {
+	var query request.MigrateTodosRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	result, err := h.todoService.MigrateTodos(c.Request.Context(), userID, c.Param("source"), query.DryRun, file)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case msg == "文件不能为空", msg == "文件大小超出限制", msg == "文件中没有待办事项", msg == "不支持的迁移来源",
+			strings.HasPrefix(msg, "文件格式无效"), strings.HasPrefix(msg, "单次最多导入"):
+			response.BadRequest(c, msg)
+		default:
+			response.InternalServerError(c, "迁移失败: "+msg)
+		}
+		return
+	}
+	response.Success(c, result)
+}
+
+// ExportTodoTxt 导出 todo.txt
+// @Summary 导出 todo.txt
+// @Description 以 todo.txt 格式导出当前用户的全部待办事项：优先级紧急/高/中对应 (A)/(B)/(C)，项目对应 +项目，标签对应 @上下文，截止日期为 due:，子任务带 parent:，每行带 id: 用于上传时对应已有的待办事项
+// @Tags 待办事项
+// @Produce plain
+// @Security Bearer
+// @Success 200 {file} file
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/todotxt [get]
+func (h *TodoHandler) ExportTodoTxt(c *gin.Context) {
+	userID := middleware.GetUserIDFromContext(c)
+	data, err := h.todoService.ExportTodoTxt(c.Request.Context(), userID)
+	if err != nil {
+		response.InternalServerError(c, "导出失败: "+err.Error())
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="todo.txt"`)
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", data)
+}
+
+// SyncTodoTxt 上传 todo.txt 同步
+// @Summary 上传 todo.txt 同步
+// @Description 上传 todo.txt 文件与已有的待办事项同步：带 id: 的行更新对应的待办事项(标题、优先级、完成状态、截止日期、项目、标签)，没有 id: 的行新建，文件中没有的待办事项保持不变；不存在的项目及标签自动创建。每行单独处理并返回结果
+// @Tags 待办事项
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param file formData file true "todo.txt 文件"
+// @Success 200 {object} response.Response{data=response.TodoTxtSyncResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/todotxt [post]
+func (h *TodoHandler) SyncTodoTxt(c *gin.Context) {
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	result, err := h.todoService.SyncTodoTxt(c.Request.Context(), userID, file)
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case msg == "文件不能为空", msg == "文件大小超出限制", msg == "文件中没有待办事项",
+			strings.HasPrefix(msg, "文件格式无效"), strings.HasPrefix(msg, "单次最多导入"):
+			response.BadRequest(c, msg)
+		default:
+			response.InternalServerError(c, "同步失败: "+msg)
+		}
+		return
+	}
+	response.Success(c, result)
+}
+
+// handleQueryError 将列表查询与搜索错误映射为HTTP响应，查询参数错误返回400，项目不存在或未共享返回404
+func handleQueryError(c *gin.Context, err error, prefix string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "筛选条件无效"), strings.HasPrefix(err.Error(), "排序参数无效"):
+		response.BadRequest(c, err.Error())
+	case err.Error() == "游标无效", err.Error() == "游标与排序方式不匹配":
+		response.BadRequest(c, err.Error())
+	case err.Error() == "搜索关键词不能为空", err.Error() == "搜索关键词过多", err.Error() == "短语缺少结束引号":
+		response.BadRequest(c, err.Error())
+	case err.Error() == "项目不存在":
+		response.NotFound(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
+
 // UpdateTodo 更新待办事项
 // @Summary 更新待办事项
-// @Description 更新指定的待办事项信息
+// @Description 更新指定的待办事项信息。须在 If-Match 中带上获取时的 ETag，待办事项已被修改时返回 412，响应头 ETag 为更新后内容的 ETag
 // @Tags 待办事项
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path int true "待办事项ID"
+// @Param If-Match header string true "获取时的 ETag，* 表示任意版本"
 // @Param request body request.UpdateTodoRequest true "更新待办事项请求"
 // @Success 200 {object} response.Response{data=response.TodoResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /todos/{id} [put]
 func (h *TodoHandler) UpdateTodo(c *gin.Context) {
@@ -148,35 +448,115 @@ func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 		response.BadRequest(c, "参数错误: "+err.Error())
 		return
 	}
+	ifMatch, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
 
 	userID := middleware.GetUserIDFromContext(c)
-	todo, err := h.todoService.UpdateTodo(c.Request.Context(), uint(id), userID, &req)
+	todo, err := h.todoService.UpdateTodo(c.Request.Context(), uint(id), userID, ifMatch, &req)
 	if err != nil {
-		if err.Error() == "待办事项不存在" {
+		if err.Error() == "待办事项不存在" || err.Error() == "项目不存在" || err.Error() == "状态不存在" {
 			response.NotFound(c, err.Error())
 		} else if err.Error() == "无权限修改此待办事项" {
 			response.Forbidden(c, err.Error())
+		} else if err.Error() == "数据已被修改" {
+			response.PreconditionFailed(c, err.Error())
+		} else if isStatusError(err) || isRecurrenceError(err) || err.Error() == "子任务的项目跟随父任务，不能单独修改" {
+			response.BadRequest(c, err.Error())
 		} else {
 			response.InternalServerError(c, "更新失败"+err.Error())
 		}
 		return
 	}
+	c.Header("ETag", service.ResponseETag(todo))
+	response.Success(c, todo)
+}
+
+// PatchTodo 局部更新待办事项
+// @Summary 局部更新待办事项
+// @Description 以 JSON 补丁局部更新待办事项，Content-Type 为 application/merge-patch+json(RFC 7396)或 application/json-patch+json(RFC 6902)。
+// @Description 补丁应用到文档 {title, description, status, status_id, priority, due_date, recurrence, project_id} 上，null 或被删除的可选字段表示清除，如 {"description": null} 清除描述。
+// @Description 修补结果校验通过后才会写入，状态变化与更新状态的规则相同。须在 If-Match 中带上获取时的 ETag，待办事项已被修改时返回 412
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param If-Match header string true "获取时的 ETag，* 表示任意版本"
+// @Param request body object true "合并补丁或 JSON Patch 操作数组"
+// @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 415 {object} response.Response
+// @Failure 422 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id} [patch]
+func (h *TodoHandler) PatchTodo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		response.BadRequest(c, "读取请求失败: "+err.Error())
+		return
+	}
+	ifMatch, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	todo, err := h.todoService.PatchTodo(c.Request.Context(), uint(id), userID, ifMatch, c.ContentType(), patch)
+	if err != nil {
+		switch msg := err.Error(); {
+		case msg == "待办事项不存在" || msg == "项目不存在" || msg == "状态不存在":
+			response.NotFound(c, msg)
+		case msg == "无权限修改此待办事项":
+			response.Forbidden(c, msg)
+		case msg == "数据已被修改":
+			response.PreconditionFailed(c, msg)
+		case msg == "不支持的补丁格式":
+			response.UnsupportedMediaType(c, msg)
+		case strings.HasPrefix(msg, "补丁无法应用"):
+			response.Conflict(c, msg)
+		case strings.HasPrefix(msg, "修补后的待办事项无效"):
+			response.UnprocessableEntity(c, msg)
+		case strings.HasPrefix(msg, "补丁格式错误") || isStatusError(err) || isRecurrenceError(err) || msg == "子任务的项目跟随父任务，不能单独修改":
+			response.BadRequest(c, msg)
+		default:
+			response.InternalServerError(c, "更新失败"+msg)
+		}
+		return
+	}
+	c.Header("ETag", service.ResponseETag(todo))
 	response.Success(c, todo)
 }
 
 // DeleteTodo 删除待办事项
 // @Summary 删除待办事项
-// @Description 删除指定的待办事项
+// @Description 删除指定的待办事项。须在 If-Match 中带上获取时的 ETag，待办事项已被修改时返回 412
 // @Tags 待办事项
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path int true "待办事项ID"
+// @Param If-Match header string true "获取时的 ETag，* 表示任意版本"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /todos/{id} [delete]
 func (h *TodoHandler) DeleteTodo(c *gin.Context) {
@@ -186,13 +566,19 @@ func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 		response.BadRequest(c, "无效的ID")
 		return
 	}
+	ifMatch, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
 	userID := middleware.GetUserIDFromContext(c)
-	err = h.todoService.DeleteTodo(c.Request.Context(), uint(id), userID)
+	err = h.todoService.DeleteTodo(c.Request.Context(), uint(id), userID, ifMatch)
 	if err != nil {
 		if err.Error() == "待办事项不存在" {
 			response.NotFound(c, err.Error())
 		} else if err.Error() == "无权限删除此待办事项" {
 			response.Forbidden(c, err.Error())
+		} else if err.Error() == "数据已被修改" {
+			response.PreconditionFailed(c, err.Error())
 		} else {
 			response.InternalServerError(c, "删除失败"+err.Error())
 		}
@@ -203,18 +589,22 @@ func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 
 // UpdateTodoStatus 更新待办事项状态
 // @Summary 更新待办事项状态
-// @Description 更新指定待办事项的状态
+// @Description 更新指定待办事项的状态，可指定分类(status)或自定义状态(status_id)。流转须符合状态机：已完成只能重新打开为待办；开始或完成前要求前置任务已完成，完成前要求子任务已完成。
+// @Description 须在 If-Match 中带上获取时的 ETag，待办事项已被修改时返回 412，响应头 ETag 为更新后内容的 ETag
 // @Tags 待办事项
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path int true "待办事项ID"
+// @Param If-Match header string true "获取时的 ETag，* 表示任意版本"
 // @Param request body request.UpdateTodoStatusRequest true "状态更新请求"
 // @Success 200 {object} response.Response{data=response.TodoResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 403 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /todos/{id}/status [patch]
 func (h *TodoHandler) UpdateTodoStatus(c *gin.Context) {
@@ -230,34 +620,134 @@ func (h *TodoHandler) UpdateTodoStatus(c *gin.Context) {
 		response.BadRequest(c, "参数错误: "+err.Error())
 		return
 	}
+	ifMatch, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
 	userID := middleware.GetUserIDFromContext(c)
-	todo, err := h.todoService.UpdateTodoStatus(c.Request.Context(), uint(id), userID, *req.Status)
+	todo, err := h.todoService.UpdateTodoStatus(c.Request.Context(), uint(id), userID, ifMatch, &req)
 	if err != nil {
-		if err.Error() == "待办事项不存在" {
+		if err.Error() == "待办事项不存在" || err.Error() == "状态不存在" {
 			response.NotFound(c, err.Error())
 		} else if err.Error() == "无权限修改此待办事项" {
 			response.Forbidden(c, err.Error())
+		} else if err.Error() == "数据已被修改" {
+			response.PreconditionFailed(c, err.Error())
+		} else if isStatusError(err) {
+			response.BadRequest(c, err.Error())
 		} else {
 			response.InternalServerError(c, "更新状态失败"+err.Error())
 		}
 		return
 	}
+	c.Header("ETag", service.ResponseETag(todo))
+	response.Success(c, todo)
+}
+
+// MoveTodo 在看板中移动待办事项
+// @Summary 在看板中移动待办事项
+// @Description 将顶层任务移动到指定列(状态)的指定位置：before_id/after_id 为移动后紧邻其前/后的任务，只指定一个时放在其后/前，都不指定时放在列首。只修改被移动的任务，移动到其他列时与更新状态的规则相同
+// @Description 须在 If-Match 中带上获取时的 ETag，待办事项已被修改时返回 412，响应头 ETag 为移动后内容的 ETag
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param If-Match header string true "获取时的 ETag，* 表示任意版本"
+// @Param request body request.MoveTodoRequest true "移动请求"
+// @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/move [put]
+func (h *TodoHandler) MoveTodo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.MoveTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	ifMatch, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	userID := middleware.GetUserIDFromContext(c)
+	todo, err := h.todoService.MoveTodo(c.Request.Context(), uint(id), userID, ifMatch, &req)
+	if err != nil {
+		switch msg := err.Error(); {
+		case msg == "待办事项不存在":
+			response.NotFound(c, msg)
+		case msg == "无权限修改此待办事项":
+			response.Forbidden(c, msg)
+		case msg == "数据已被修改":
+			response.PreconditionFailed(c, msg)
+		case msg == "子任务不能在看板中移动", msg == "相邻的待办事项无效", isStatusError(err):
+			response.BadRequest(c, msg)
+		default:
+			response.InternalServerError(c, "移动失败: "+msg)
+		}
+		return
+	}
+	c.Header("ETag", service.ResponseETag(todo))
 	response.Success(c, todo)
 }
 
+// GetBoard 获取看板
+// @Summary 获取看板
+// @Description 按状态分为待办、进行中、已完成三列，列内按看板中的顺序排列，只包含顶层任务(子任务随父任务返回)
+// @Tags 待办事项
+// @Produce json
+// @Security Bearer
+// @Param project query int false "项目ID，0 表示收件箱，为空表示全部"
+// @Param limit query int false "每列最多返回的数量，默认50，最大200"
+// @Success 200 {object} response.Response{data=response.BoardResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /board [get]
+func (h *TodoHandler) GetBoard(c *gin.Context) {
+	var query request.BoardQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	board, err := h.todoService.GetBoard(c.Request.Context(), userID, &query)
+	if err != nil {
+		if err.Error() == "项目不存在" {
+			response.NotFound(c, err.Error())
+		} else {
+			response.InternalServerError(c, "获取看板失败: "+err.Error())
+		}
+		return
+	}
+	response.Success(c, board)
+}
+
 // BatchUpdateStatus 批量更新待办事项状态
 // @Summary 批量更新待办事项状态
-// @Description 批量更新多个待办事项的状态
+// @Description 批量更新多个待办事项的状态，每个待办事项按状态机流转并返回结果，等同于 action 为 update 且只指定 status 的批量操作
 // @Tags 待办事项
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param request body request.BatchUpdateTodoRequest true "批量更新请求"
-// @Success 200 {object} response.Response
+// @Success 200 {object} response.Response{data=response.BatchTodoResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
 // @Failure 500 {object} response.Response
-// @Router /todos/batch-status [patch]
+// @Router /todos/batch/status [put]
 func (h *TodoHandler) BatchUpdateStatus(c *gin.Context) {
 	var req request.BatchUpdateTodoRequest
 	userID := middleware.GetUserIDFromContext(c)
@@ -266,11 +756,621 @@ func (h *TodoHandler) BatchUpdateStatus(c *gin.Context) {
 		return
 	}
 
-	err := h.todoService.BatchUpdateStatus(c.Request.Context(), userID, &req)
+	result, err := h.todoService.BatchUpdateStatus(c.Request.Context(), userID, &req)
 	if err != nil {
 		response.InternalServerError(c, "批量更新失败: "+err.Error())
 		return
 	}
 
-	response.Success(c, nil)
+	response.Success(c, result)
+}
+
+// BatchTodos 批量修改或删除待办事项
+// @Summary 批量修改或删除待办事项
+// @Description 在一个事务中对多个待办事项执行同一操作：update 修改指定的状态、优先级、截止时间、项目及标签，delete 移入回收站。每个待办事项单独返回结果(updated/deleted/not_found/forbidden/failed)，失败的不影响其他；all_or_nothing 为 true 时任一失败则全部回滚，其余结果为 rolled_back
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body request.BatchTodoRequest true "批量操作请求"
+// @Success 200 {object} response.Response{data=response.BatchTodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/batch [post]
+func (h *TodoHandler) BatchTodos(c *gin.Context) {
+	var req request.BatchTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	result, err := h.todoService.BatchTodos(c.Request.Context(), userID, &req)
+	if err != nil {
+		if err.Error() == "未指定要修改的字段" {
+			response.BadRequest(c, err.Error())
+		} else {
+			response.InternalServerError(c, "批量操作失败: "+err.Error())
+		}
+		return
+	}
+	response.Success(c, result)
+}
+
+// AttachTags 为待办事项添加标签
+// @Summary 为待办事项添加标签
+// @Description 将当前用户的一个或多个标签关联到待办事项
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param request body request.TodoTagsRequest true "标签ID列表"
+// @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/tags [post]
+func (h *TodoHandler) AttachTags(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.TodoTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	todo, err := h.todoService.AttachTags(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		if err.Error() == "待办事项不存在" || err.Error() == "标签不存在" {
+			response.NotFound(c, err.Error())
+		} else if err.Error() == "无权限修改此待办事项" {
+			response.Forbidden(c, err.Error())
+		} else {
+			response.InternalServerError(c, "添加标签失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, todo)
+}
+
+// DetachTag 移除待办事项上的标签
+// @Summary 移除待办事项上的标签
+// @Description 解除待办事项与指定标签的关联
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param tag_id path int true "标签ID"
+// @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/tags/{tag_id} [delete]
+func (h *TodoHandler) DetachTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+	tagID, err := strconv.ParseUint(c.Param("tag_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的标签ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	todo, err := h.todoService.DetachTag(c.Request.Context(), uint(id), userID, uint(tagID))
+	if err != nil {
+		if err.Error() == "待办事项不存在" {
+			response.NotFound(c, err.Error())
+		} else if err.Error() == "无权限修改此待办事项" {
+			response.Forbidden(c, err.Error())
+		} else {
+			response.InternalServerError(c, "移除标签失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, todo)
+}
+
+// GetSubtasks 获取子任务列表
+// @Summary 获取子任务列表
+// @Description 按排序位置获取待办事项的子任务
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=[]response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/subtasks [get]
+func (h *TodoHandler) GetSubtasks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	subtasks, err := h.todoService.GetSubtasks(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		handleSubtaskError(c, err, "获取子任务失败")
+		return
+	}
+	response.Success(c, subtasks)
+}
+
+// CreateSubtask 创建子任务
+// @Summary 创建子任务
+// @Description 在指定待办事项下创建子任务，子任务追加到末尾
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param request body request.CreateTodoRequest true "创建子任务请求"
+// @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/subtasks [post]
+func (h *TodoHandler) CreateSubtask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.CreateTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	subtask, err := h.todoService.CreateSubtask(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		handleSubtaskError(c, err, "创建子任务失败")
+		return
+	}
+	response.Success(c, subtask)
+}
+
+// ReorderSubtasks 调整子任务顺序
+// @Summary 调整子任务顺序
+// @Description 按给定的子任务ID顺序重新排列全部子任务
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param request body request.ReorderSubtasksRequest true "子任务排序请求"
+// @Success 200 {object} response.Response{data=[]response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/subtasks/order [put]
+func (h *TodoHandler) ReorderSubtasks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.ReorderSubtasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	subtasks, err := h.todoService.ReorderSubtasks(c.Request.Context(), uint(id), userID, &req)
+	if err != nil {
+		handleSubtaskError(c, err, "调整顺序失败")
+		return
+	}
+	response.Success(c, subtasks)
+}
+
+// handleSubtaskError 将子任务相关错误映射为HTTP响应
+func handleSubtaskError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "待办事项不存在":
+		response.NotFound(c, err.Error())
+	case "无权限访问此待办事项", "无权限修改此待办事项":
+		response.Forbidden(c, err.Error())
+	case "子任务不能再添加子任务", "子任务列表不匹配":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
+}
+
+// GetOccurrences 预览重复任务
+// @Summary 预览重复任务
+// @Description 从当前截止时间开始列出重复任务接下来的发生时间
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param limit query int false "预览数量" default(10)
+// @Success 200 {object} response.Response{data=response.OccurrencesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/occurrences [get]
+func (h *TodoHandler) GetOccurrences(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var query request.OccurrencePreviewRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	occurrences, err := h.todoService.GetOccurrences(c.Request.Context(), uint(id), userID, query.Limit)
+	if err != nil {
+		if err.Error() == "待办事项不存在" {
+			response.NotFound(c, err.Error())
+		} else if err.Error() == "无权限访问此待办事项" {
+			response.Forbidden(c, err.Error())
+		} else if err.Error() == "该待办事项不是重复任务" {
+			response.BadRequest(c, err.Error())
+		} else {
+			response.InternalServerError(c, "预览失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, occurrences)
+}
+
+// StopRecurrence 取消重复
+// @Summary 取消重复
+// @Description 移除待办事项的重复规则，当前实例保留；待办事项同时被其他请求修改时返回 412
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/recurrence [delete]
+func (h *TodoHandler) StopRecurrence(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	todo, err := h.todoService.StopRecurrence(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		if err.Error() == "待办事项不存在" {
+			response.NotFound(c, err.Error())
+		} else if err.Error() == "无权限修改此待办事项" {
+			response.Forbidden(c, err.Error())
+		} else if err.Error() == "数据已被修改" {
+			response.PreconditionFailed(c, err.Error())
+		} else {
+			response.InternalServerError(c, "取消重复失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, todo)
+}
+
+// isRecurrenceError 判断是否为重复规则校验错误
+func isRecurrenceError(err error) bool {
+	return err.Error() == "重复任务必须设置截止时间" ||
+		strings.HasPrefix(err.Error(), "重复规则无效")
+}
+
+// isStatusError 是否为状态机拒绝的状态流转
+func isStatusError(err error) bool {
+	switch err.Error() {
+	case "不允许的状态流转", "自定义状态不属于该分类", "存在未完成的子任务", "存在未完成的前置任务":
+		return true
+	default:
+		return false
+	}
+}
+
+// GetHistory 获取变更记录
+// @Summary 获取变更记录
+// @Description 按时间倒序列出待办事项的创建、字段修改与删除记录
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=[]response.TodoHistoryResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/history [get]
+func (h *TodoHandler) GetHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	history, err := h.todoService.GetHistory(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		if err.Error() == "待办事项不存在" {
+			response.NotFound(c, err.Error())
+		} else if err.Error() == "无权限访问此待办事项" {
+			response.Forbidden(c, err.Error())
+		} else {
+			response.InternalServerError(c, "获取变更记录失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, history)
+}
+
+// GetDependencies 获取依赖关系
+// @Summary 获取依赖关系
+// @Description 获取待办事项的前置任务(blocked_by)与被其阻塞的任务(blocks)，存在未完成的前置任务时 is_blocked 为 true
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=response.TodoDependenciesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/dependencies [get]
+func (h *TodoHandler) GetDependencies(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	dependencies, err := h.todoService.GetDependencies(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		if err.Error() == "待办事项不存在" {
+			response.NotFound(c, err.Error())
+		} else if err.Error() == "无权限访问此待办事项" {
+			response.Forbidden(c, err.Error())
+		} else {
+			response.InternalServerError(c, "获取依赖关系失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, dependencies)
+}
+
+// AddDependency 添加前置任务
+// @Summary 添加前置任务
+// @Description 前置任务完成前，该待办事项不能开始或完成。前置任务须属于同一所有者，且不能形成循环依赖
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param request body request.AddDependencyRequest true "前置任务"
+// @Success 200 {object} response.Response{data=response.TodoDependenciesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/dependencies [post]
+func (h *TodoHandler) AddDependency(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	var req request.AddDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+	userID := middleware.GetUserIDFromContext(c)
+	dependencies, err := h.todoService.AddDependency(c.Request.Context(), uint(id), userID, req.BlockerID)
+	if err != nil {
+		switch err.Error() {
+		case "待办事项不存在", "前置任务不存在":
+			response.NotFound(c, err.Error())
+		case "无权限修改此待办事项":
+			response.Forbidden(c, err.Error())
+		case "不能依赖自身", "依赖关系已存在", "依赖关系会形成循环":
+			response.BadRequest(c, err.Error())
+		default:
+			response.InternalServerError(c, "添加前置任务失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, dependencies)
+}
+
+// RemoveDependency 移除前置任务
+// @Summary 移除前置任务
+// @Description 解除待办事项与指定前置任务的依赖关系
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Param blocker_id path int true "前置任务ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/dependencies/{blocker_id} [delete]
+func (h *TodoHandler) RemoveDependency(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+	blockerID, err := strconv.ParseUint(c.Param("blocker_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的前置任务ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	if err := h.todoService.RemoveDependency(c.Request.Context(), uint(id), userID, uint(blockerID)); err != nil {
+		if err.Error() == "待办事项不存在" || err.Error() == "依赖关系不存在" {
+			response.NotFound(c, err.Error())
+		} else if err.Error() == "无权限修改此待办事项" {
+			response.Forbidden(c, err.Error())
+		} else {
+			response.InternalServerError(c, "移除前置任务失败"+err.Error())
+		}
+		return
+	}
+	response.Success(c, nil)
+}
+
+// GetTrash 获取回收站列表
+// @Summary 获取回收站列表
+// @Description 分页获取当前用户已删除的待办事项，最近删除的在前
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Success 200 {object} response.Response{data=response.TrashListResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/trash [get]
+func (h *TodoHandler) GetTrash(c *gin.Context) {
+	var query request.TrashQueryRequest
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.BadRequest(c, "参数错误: "+err.Error())
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	trash, err := h.todoService.GetTrash(c.Request.Context(), userID, &query)
+	if err != nil {
+		response.InternalServerError(c, "获取回收站失败"+err.Error())
+		return
+	}
+	response.Success(c, trash)
+}
+
+// RestoreTodo 恢复待办事项
+// @Summary 恢复待办事项
+// @Description 从回收站恢复待办事项及与其一同删除的子任务，所属项目已删除时移入收件箱
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response{data=response.TodoResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/restore [post]
+func (h *TodoHandler) RestoreTodo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	todo, err := h.todoService.RestoreTodo(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		handleTrashError(c, err, "恢复失败")
+		return
+	}
+	response.Success(c, todo)
+}
+
+// PurgeTodo 彻底删除待办事项
+// @Summary 彻底删除待办事项
+// @Description 彻底删除回收站中的待办事项及其子任务、附件文件，不可恢复
+// @Tags 待办事项
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path int true "待办事项ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /todos/{id}/purge [delete]
+func (h *TodoHandler) PurgeTodo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "无效的ID")
+		return
+	}
+
+	userID := middleware.GetUserIDFromContext(c)
+	if err := h.todoService.PurgeTodo(c.Request.Context(), uint(id), userID); err != nil {
+		handleTrashError(c, err, "彻底删除失败")
+		return
+	}
+	response.Success(c, nil)
+}
+
+// handleTrashError 将回收站操作错误映射为HTTP响应
+func handleTrashError(c *gin.Context, err error, prefix string) {
+	switch err.Error() {
+	case "待办事项不存在":
+		response.NotFound(c, err.Error())
+	case "无权限删除此待办事项":
+		response.Forbidden(c, err.Error())
+	case "父任务已删除，请先恢复父任务":
+		response.BadRequest(c, err.Error())
+	default:
+		response.InternalServerError(c, prefix+err.Error())
+	}
 }
@@ -19,12 +19,14 @@ func NewUserHandeler(userService service.UserService) *UserHandeler {
 
 // GetProfile 获取当前用户信息
 // @Summary 获取当前用户信息
-// @Description 获取当前已登录用户的个人信息
+// @Description 获取当前已登录用户的个人信息。响应头 ETag 由响应内容生成，请求头 If-None-Match 与之匹配时返回 304
 // @Tags 用户
 // @Accept json
 // @Produce json
 // @Security Bearer
+// @Param If-None-Match header string false "上次获取时的 ETag"
 // @Success 200 {object} response.Response{data=response.UserResponse}
+// @Success 304 "未修改"
 // @Failure 401 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /user/profile [get]
@@ -36,20 +38,26 @@ func (u *UserHandeler) GetProfile(c *gin.Context) {
 		response.InternalServerError(c, "获取用户信息失败")
 		return
 	}
+	if notModified(c, user) {
+		return
+	}
 	response.Success(c, user)
 }
 
 // UpdateProfile 更新用户信息
 // @Summary 更新用户信息
-// @Description 更新当前用户的个人信息（如用户名、邮箱等）
+// @Description 更新当前用户的个人信息（如用户名、邮箱等）。须在 If-Match 中带上获取时的 ETag，用户信息已被修改时返回 412
 // @Tags 用户
 // @Accept json
 // @Produce json
 // @Security Bearer
+// @Param If-Match header string true "获取时的 ETag，* 表示任意版本"
 // @Param request body request.UpdateProfileRequest true "用户信息更新请求"
 // @Success 200 {object} response.Response{data=response.UserResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 412 {object} response.Response
+// @Failure 428 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /user/profile [put]
 func (u *UserHandeler) UpdateProfile(c *gin.Context) {
@@ -60,17 +68,26 @@ func (u *UserHandeler) UpdateProfile(c *gin.Context) {
 		response.BadRequest(c, "参数错误")
 		return
 	}
-	user, err := u.userService.UpdateProfile(c.Request.Context(), userID, &req)
+	ifMatch, ok := requireIfMatch(c)
+	if !ok {
+		return
+	}
+	user, err := u.userService.UpdateProfile(c.Request.Context(), userID, ifMatch, &req)
 	if err != nil {
+		if err.Error() == "数据已被修改" {
+			response.PreconditionFailed(c, err.Error())
+			return
+		}
 		response.InternalServerError(c, "修改用户信息失败")
 		return
 	}
+	c.Header("ETag", service.ResponseETag(user))
 	response.Success(c, user)
 }
 
 // ChangePassword 修改密码
 // @Summary 修改密码
-// @Description 修改当前用户的登录密码
+// @Description 修改当前用户的登录密码；用户信息同时被其他请求修改时返回 412
 // @Tags 用户
 // @Accept json
 // @Produce json
@@ -79,6 +96,7 @@ func (u *UserHandeler) UpdateProfile(c *gin.Context) {
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 412 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /user/change-password [put]
 func (u *UserHandeler) ChangePassword(c *gin.Context) {
@@ -91,7 +109,11 @@ func (u *UserHandeler) ChangePassword(c *gin.Context) {
 
 	err := u.userService.ChangePassword(c.Request.Context(), userID, &req)
 	if err != nil {
-		response.InternalServerError(c, "修改密码失败: "+err.Error())
+		if err.Error() == "数据已被修改" {
+			response.PreconditionFailed(c, err.Error())
+		} else {
+			response.InternalServerError(c, "修改密码失败: "+err.Error())
+		}
 		return
 	}
 
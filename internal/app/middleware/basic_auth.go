@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"TODO_API/pkg/response"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BasicAuthenticator 校验 HTTP Basic 认证的用户名与密码，返回用户ID
+type BasicAuthenticator interface {
+	Authenticate(ctx context.Context, username, password string) (uint, error)
+}
+
+// BasicAuthMiddleWare HTTP Basic 认证，供无法使用JWT的客户端(如CalDAV)使用应用专用密码访问
+func BasicAuthMiddleWare(realm string, authenticator BasicAuthenticator) gin.HandlerFunc {
+	challenge := `Basic realm="` + realm + `", charset="UTF-8"`
+	return func(c *gin.Context) {
+		username, password, ok := c.Request.BasicAuth()
+		if !ok {
+			c.Header("WWW-Authenticate", challenge)
+			response.Unauthorized(c, "请提供用户名及应用专用密码")
+			c.Abort()
+			return
+		}
+
+		userID, err := authenticator.Authenticate(c.Request.Context(), username, password)
+		if err != nil {
+			c.Header("WWW-Authenticate", challenge)
+			response.Unauthorized(c, "用户名或应用专用密码错误")
+			c.Abort()
+			return
+		}
+
+		c.Set("UserID", userID)
+		c.Set("UserName", username)
+		zap.L().Debug("Basic认证成功",
+			zap.Uint("UserID", userID),
+			zap.String("Username", username),
+			zap.String("path", c.Request.URL.Path),
+		)
+		c.Next()
+	}
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// AppPassword 应用专用密码，供无法使用JWT的客户端(如CalDAV)通过 HTTP Basic 认证
+type AppPassword struct {
+	ID           uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       uint       `gorm:"not null;index" json:"user_id"`
+	Name         string     `gorm:"type:varchar(50);not null" json:"name"`
+	PasswordHash string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"` // 密码的SHA-256哈希，密码只在创建时返回
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (AppPassword) TableName() string {
+	return "app_passwords"
+}
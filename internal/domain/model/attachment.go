@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Attachment 待办事项附件，文件内容保存在存储后端
+type Attachment struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TodoID      uint      `gorm:"not null;index" json:"todo_id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"` // 上传者
+	FileName    string    `gorm:"type:varchar(255);not null" json:"file_name"`
+	ContentType string    `gorm:"type:varchar(100);not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	StorageKey  string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"` // 存储后端中的对象键
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Attachment) TableName() string {
+	return "attachments"
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment 待办事项评论
+type Comment struct {
+	ID        uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	TodoID    uint           `gorm:"not null;index" json:"todo_id"`
+	UserID    uint           `gorm:"not null;index" json:"user_id"` // 评论作者
+	Body      string         `gorm:"type:text;not null" json:"body"`
+	EditedAt  *time.Time     `json:"edited_at,omitempty"` // 最后编辑时间，为空表示未编辑过
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// 关联作者
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName 指定表名
+func (Comment) TableName() string {
+	return "comments"
+}
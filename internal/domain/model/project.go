@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Project 项目(清单)模型，用于对待办事项分组
+type Project struct {
+	ID          uint           `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID      uint           `gorm:"not null;index" json:"user_id"`
+	Name        string         `gorm:"type:varchar(100);not null" json:"name"`
+	Description *string        `gorm:"type:varchar(500)" json:"description,omitempty"`
+	Color       string         `gorm:"type:varchar(7);default:#1890ff" json:"color"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// TableName 指定表名
+func (Project) TableName() string {
+	return "projects"
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Reminder 待办事项提醒
+type Reminder struct {
+	ID            uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	TodoID        uint       `gorm:"not null;index" json:"todo_id"`
+	UserID        uint       `gorm:"not null;index" json:"user_id"`
+	RemindAt      time.Time  `gorm:"not null" json:"remind_at"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty"` // 相对截止时间提前的分钟数，为空表示绝对时间
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"` // 发送失败的次数
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`          // 发送失败后下次重试的时间
+	FailedAt      *time.Time `json:"failed_at,omitempty"`                // 多次发送失败后放弃的时间
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// 关联待办事项
+	Todo *Todo `gorm:"foreignKey:TodoID" json:"todo,omitempty"`
+}
+
+// TableName 指定表名
+func (Reminder) TableName() string {
+	return "reminders"
+}
+
+// IsRelative 检查是否为相对截止时间的提醒
+func (r *Reminder) IsRelative() bool {
+	return r.OffsetMinutes != nil
+}
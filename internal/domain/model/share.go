@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// ShareRole 共享角色
+type ShareRole string
+
+const (
+	ShareRoleViewer ShareRole = "viewer" //只读
+	ShareRoleEditor ShareRole = "editor" //可编辑
+	ShareRoleOwner  ShareRole = "owner"  //可删除及管理共享
+)
+
+// 共享资源类型
+const (
+	ShareResourceTodo    = "todo"
+	ShareResourceProject = "project"
+)
+
+// Share 待办事项或项目的共享记录
+type Share struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	ResourceType string    `gorm:"type:varchar(20);not null" json:"resource_type"`
+	ResourceID   uint      `gorm:"not null" json:"resource_id"`
+	OwnerID      uint      `gorm:"not null;index" json:"owner_id"` // 发起共享的用户
+	UserID       uint      `gorm:"not null;index" json:"user_id"`  // 被共享的用户
+	Role         ShareRole `gorm:"type:varchar(20);not null" json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// 关联用户
+	Owner User `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`
+	User  User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName 指定表名
+func (Share) TableName() string {
+	return "shares"
+}
+
+// Level 角色等级，数值越大权限越高，未知角色为0
+func (r ShareRole) Level() int {
+	switch r {
+	case ShareRoleViewer:
+		return 1
+	case ShareRoleEditor:
+		return 2
+	case ShareRoleOwner:
+		return 3
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Tag 标签模型
+type Tag struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Name      string    `gorm:"type:varchar(50);not null" json:"name"`
+	Color     string    `gorm:"type:varchar(7);default:#1890ff" json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// TodoTag 待办事项-标签关联模型
+type TodoTag struct {
+	TodoID    uint      `gorm:"primaryKey" json:"todo_id"`
+	TagID     uint      `gorm:"primaryKey" json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TodoTag) TableName() string {
+	return "todo_tags"
+}
@@ -28,18 +28,31 @@ const (
 type Todo struct {
 	ID          uint           `gorm:"primaryKey;autoIncrement" json:"id"`
 	UserID      uint           `gorm:"not null;index" json:"user_id"`
+	ProjectID   *uint          `gorm:"index" json:"project_id,omitempty"`             // 所属项目ID，为空表示收件箱
+	ParentID    *uint          `gorm:"index" json:"parent_id,omitempty"`              // 父任务ID，为空表示顶层任务
+	Position    int            `gorm:"default:0" json:"position"`                     // 子任务排序位置
+	BoardRank   string         `gorm:"type:varchar(64);not null;default:''" json:"-"` // 看板中的排序键，按字典序排列，只有顶层任务有值
 	Title       string         `gorm:"type:varchar(200);not null" json:"title"`
 	Description *string        `gorm:"type:text" json:"description,omitempty"`
 	Status      TodoStatus     `gorm:"type:tinyint;default:0" json:"status"`   // 0-待办,1-进行中,2-已完成
+	StatusID    *uint          `gorm:"index" json:"status_id,omitempty"`       // 自定义状态ID，必须属于 Status 分类，为空表示使用分类本身
 	Priority    TodosPriority  `gorm:"type:tinyint;default:1" json:"priority"` // 1-低,2-中,3-高,4-紧急
 	DueDate     *time.Time     `gorm:"index" json:"due_date,omitempty"`
+	Recurrence  *string        `gorm:"type:varchar(255)" json:"recurrence,omitempty"` // RRULE 重复规则
+	ICalUID     *string        `gorm:"column:ical_uid;type:varchar(255)" json:"-"`    // 从日历导入时的原始UID
+	CalDAVName  *string        `gorm:"column:caldav_name;type:varchar(255)" json:"-"` // CalDAV 客户端创建时指定的资源名
 	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	Version     uint           `gorm:"not null;default:1" json:"version"` // 版本号，每次更新加1，用于乐观锁
 	CreatedAt   *time.Time     `json:"created_at"`
 	UpdatedAt   *time.Time     `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 
 	// 关联用户
 	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	// 关联标签
+	Tags []Tag `gorm:"many2many:todo_tags" json:"tags,omitempty"`
+	// 子任务
+	Subtasks []Todo `gorm:"foreignKey:ParentID" json:"subtasks,omitempty"`
 }
 
 // TableName 指定表名
@@ -47,6 +60,11 @@ func (Todo) TableName() string {
 	return "todos"
 }
 
+// IsSubtask 检查是否为子任务
+func IsSubtask(t *Todo) bool {
+	return t.ParentID != nil
+}
+
 // IsCompleted 检查是否已完成
 func IsCompleted(t *Todo) bool {
 	return t.Status == todosCompleted
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// TodoDependency 待办事项依赖关系：TodoID 被 BlockerID 阻塞，BlockerID 完成前 TodoID 不能开始或完成
+type TodoDependency struct {
+	TodoID    uint      `gorm:"primaryKey" json:"todo_id"`
+	BlockerID uint      `gorm:"primaryKey;index" json:"blocker_id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"` // 两个待办事项共同的所有者
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TodoDependency) TableName() string {
+	return "todo_dependencies"
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// 历史记录动作
+const (
+	HistoryActionCreated  = "created"
+	HistoryActionUpdated  = "updated"
+	HistoryActionDeleted  = "deleted"
+	HistoryActionRestored = "restored"
+)
+
+// TodoHistory 待办事项变更记录，updated 动作按字段逐条记录
+type TodoHistory struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	TodoID    uint      `gorm:"not null;index" json:"todo_id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"` // 操作人
+	Action    string    `gorm:"type:varchar(20);not null" json:"action"`
+	Field     string    `gorm:"type:varchar(50)" json:"field,omitempty"` // 变更字段，仅 updated 动作有值
+	OldValue  *string   `gorm:"type:text" json:"old_value,omitempty"`
+	NewValue  *string   `gorm:"type:text" json:"new_value,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// 关联操作人
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName 指定表名
+func (TodoHistory) TableName() string {
+	return "todo_history"
+}
@@ -1,8 +1,34 @@
 package model
 
+import "time"
+
 type TodoStatistics struct {
 	TotalCount     uint `json:"total_count" gorm:"column:total_count"`
 	PendingCount   uint `json:"pending_count" gorm:"column:pending_count"`
 	ProgressCount  uint `json:"progress_count" gorm:"column:progress_count"`
 	CompletedCount uint `json:"completed_count" gorm:"column:completed_count"`
 }
+
+// TodoAnalytics 统计分析数据，时间窗口为 [From, To)
+type TodoAnalytics struct {
+	TodoStatistics
+	LowCount     uint `gorm:"column:low_count"`
+	MediumCount  uint `gorm:"column:medium_count"`
+	HighCount    uint `gorm:"column:high_count"`
+	UrgentCount  uint `gorm:"column:urgent_count"`
+	OverdueCount uint `gorm:"column:overdue_count"`
+
+	CreatedInWindow      uint    `gorm:"-"` // 窗口内创建的任务数
+	CompletedOfCreated   uint    `gorm:"-"` // 窗口内创建且已完成的任务数
+	CompletedInWindow    uint    `gorm:"-"` // 窗口内完成的任务数
+	AvgCompletionSeconds float64 `gorm:"-"` // 窗口内完成的任务从创建到完成的平均秒数
+
+	Daily []DailyTodoCount `gorm:"-"`
+}
+
+// DailyTodoCount 按天统计的创建与完成数量
+type DailyTodoCount struct {
+	Day       time.Time
+	Created   uint
+	Completed uint
+}
@@ -8,15 +8,17 @@ import (
 
 // User用户模型
 type User struct {
-	ID           uint           `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
-	Username     string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
-	Email        string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
-	PasswordHash string         `gorm:"type:varchar(255);not null" json:"-"`
-	AvatarURL    *string        `gorm:"type:varchar(255)" json:"avatar_url,omitempty"`
-	Status       uint8          `gorm:"type:tinyint;default:1" json:"status"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	ID                uint           `gorm:"primary_key;AUTO_INCREMENT" json:"id"`
+	Username          string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
+	Email             string         `gorm:"type:varchar(100);uniqueIndex;not null" json:"email"`
+	PasswordHash      string         `gorm:"type:varchar(255);not null" json:"-"`
+	AvatarURL         *string        `gorm:"type:varchar(255)" json:"avatar_url,omitempty"`
+	Status            uint8          `gorm:"type:tinyint;default:1" json:"status"`
+	CalendarTokenHash *string        `gorm:"type:char(64);uniqueIndex" json:"-"` // 日历订阅令牌的SHA-256哈希，令牌只在生成时返回
+	Version           uint           `gorm:"not null;default:1" json:"version"`  // 版本号，每次更新加1，用于乐观锁
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 }
 
 func (User) TableName() string {
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidTransition 状态机不允许的状态流转
+var ErrInvalidTransition = errors.New("不允许的状态流转")
+
+// todoTransitions 状态机：各分类允许流转到的其他分类，已完成的任务只能重新打开为待办
+var todoTransitions = map[TodoStatus][]TodoStatus{
+	todosPending:    {todosInProgress, todosCompleted},
+	todosInProgress: {todosPending, todosCompleted},
+	todosCompleted:  {todosPending},
+}
+
+// WorkflowStatus 用户自定义状态，如“已阻塞”“待审核”，每个自定义状态归属于一个分类(待办/进行中/已完成)
+type WorkflowStatus struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Name      string     `gorm:"type:varchar(50);not null" json:"name"`
+	Category  TodoStatus `gorm:"type:tinyint;not null" json:"category"` // 所属分类: 0-待办,1-进行中,2-已完成
+	Color     string     `gorm:"type:varchar(7);default:#8c8c8c" json:"color"`
+	Position  int        `gorm:"default:0" json:"position"` // 同一分类内的排序位置
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (WorkflowStatus) TableName() string {
+	return "workflow_statuses"
+}
+
+// ValidStatus 检查是否为有效的状态分类
+func ValidStatus(status TodoStatus) bool {
+	_, ok := todoTransitions[status]
+	return ok
+}
+
+// Transitions 获取分类允许流转到的其他分类
+func Transitions(from TodoStatus) []TodoStatus {
+	return todoTransitions[from]
+}
+
+// CanTransition 检查是否允许从一个分类流转到另一个分类，同一分类内的自定义状态之间可以任意切换
+func CanTransition(from, to TodoStatus) bool {
+	if !ValidStatus(to) {
+		return false
+	}
+	if from == to {
+		return true
+	}
+	for _, next := range todoTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition 按状态机将待办事项流转到目标分类，并执行进入该分类时的附加操作：
+// 进入已完成时记录完成时间，离开已完成时清除。分类不变时不做任何修改
+func Transition(t *Todo, to TodoStatus) error {
+	if !CanTransition(t.Status, to) {
+		return ErrInvalidTransition
+	}
+	if t.Status == to {
+		return nil
+	}
+	switch to {
+	case todosCompleted:
+		MarkCompleted(t)
+	case todosInProgress:
+		MarkProgress(t)
+	default:
+		MarkPending(t)
+	}
+	return nil
+}
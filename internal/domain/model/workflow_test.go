@@ -0,0 +1,94 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to TodoStatus
+		want     bool
+	}{
+		{"待办→待办", todosPending, todosPending, true},
+		{"待办→进行中", todosPending, todosInProgress, true},
+		{"待办→已完成", todosPending, todosCompleted, true},
+		{"进行中→待办", todosInProgress, todosPending, true},
+		{"进行中→进行中", todosInProgress, todosInProgress, true},
+		{"进行中→已完成", todosInProgress, todosCompleted, true},
+		{"已完成→待办", todosCompleted, todosPending, true},
+		{"已完成→已完成", todosCompleted, todosCompleted, true},
+		{"已完成不能直接回到进行中", todosCompleted, todosInProgress, false},
+		{"目标分类无效", todosPending, 3, false},
+		{"来源分类无效", 3, todosPending, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.want {
+				t.Errorf("CanTransition(%d, %d) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidStatus(t *testing.T) {
+	for _, status := range []TodoStatus{todosPending, todosInProgress, todosCompleted} {
+		if !ValidStatus(status) {
+			t.Errorf("ValidStatus(%d) = false", status)
+		}
+	}
+	if ValidStatus(3) {
+		t.Error("ValidStatus(3) = true")
+	}
+}
+
+func TestTransition(t *testing.T) {
+	completedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		from, to      TodoStatus
+		completedAt   *time.Time
+		wantErr       bool
+		wantStatus    TodoStatus
+		wantCompleted bool // 是否应当有完成时间
+		keepCompleted bool // 完成时间是否应当保持原值
+	}{
+		{name: "完成时记录完成时间", from: todosPending, to: todosCompleted,
+			wantStatus: todosCompleted, wantCompleted: true},
+		{name: "从进行中完成", from: todosInProgress, to: todosCompleted,
+			wantStatus: todosCompleted, wantCompleted: true},
+		{name: "开始", from: todosPending, to: todosInProgress,
+			wantStatus: todosInProgress},
+		{name: "重新打开时清除完成时间", from: todosCompleted, to: todosPending, completedAt: &completedAt,
+			wantStatus: todosPending},
+		{name: "分类不变时保留完成时间", from: todosCompleted, to: todosCompleted, completedAt: &completedAt,
+			wantStatus: todosCompleted, wantCompleted: true, keepCompleted: true},
+		{name: "拒绝的流转不修改任务", from: todosCompleted, to: todosInProgress, completedAt: &completedAt,
+			wantErr: true, wantStatus: todosCompleted, wantCompleted: true, keepCompleted: true},
+		{name: "无效的目标分类", from: todosPending, to: 3,
+			wantErr: true, wantStatus: todosPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &Todo{Status: tt.from, CompletedAt: tt.completedAt}
+			err := Transition(todo, tt.to)
+			if tt.wantErr {
+				if err != ErrInvalidTransition {
+					t.Fatalf("Transition error = %v, want %v", err, ErrInvalidTransition)
+				}
+			} else if err != nil {
+				t.Fatalf("Transition error: %v", err)
+			}
+			if todo.Status != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", todo.Status, tt.wantStatus)
+			}
+			if (todo.CompletedAt != nil) != tt.wantCompleted {
+				t.Fatalf("CompletedAt = %v, 期望有完成时间: %v", todo.CompletedAt, tt.wantCompleted)
+			}
+			if tt.keepCompleted && !todo.CompletedAt.Equal(completedAt) {
+				t.Errorf("CompletedAt = %v, 应当保持 %v", todo.CompletedAt, completedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Package migration 将其他待办工具的导出文件转换为统一的记录，由待办事项导入流程校验并写入。
+// 无法映射的内容不会中断转换，而是记录在迁移报告中
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxTitleLength       = 200
+	maxTagNameLength     = 50
+	maxProjectNameLength = 100
+)
+
+// Record 转换后的一条待办事项
+type Record struct {
+	Ref         string // 源数据中的ID，同一次转换内唯一
+	Parent      string // 父任务的 Ref，只有一层
+	Project     string // 项目名，为空表示收件箱
+	Title       string
+	Description string
+	Priority    int // 1-4，0 表示未设置
+	Status      int // 0-待办,1-进行中,2-已完成
+	DueDate     *time.Time
+	CompletedAt *time.Time
+	Recurrence  string // RRULE
+	Tags        []string
+}
+
+// Issue 迁移报告中无法映射或被调整的内容
+type Issue struct {
+	Item    string `json:"item"` // 源数据中的标题或名称
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Result 转换结果
+type Result struct {
+	Records []Record
+	Issues  []Issue
+}
+
+// addIssue 记录一条迁移问题
+func (r *Result) addIssue(item, field, message string) {
+	r.Issues = append(r.Issues, Issue{Item: item, Field: field, Message: message})
+}
+
+// Parser 解析某个工具的导出文件，filename 用于判断格式及推断项目名
+type Parser func(r io.Reader, filename string) (*Result, error)
+
+// 支持的来源
+const (
+	SourceTodoist = "todoist"
+	SourceTrello  = "trello"
+	SourceMSTodo  = "mstodo"
+)
+
+var parsers = map[string]Parser{
+	SourceTodoist: ParseTodoist,
+	SourceTrello:  ParseTrello,
+	SourceMSTodo:  ParseMSTodo,
+}
+
+// Parse 按来源解析导出文件，并规范化记录：截断过长的标题与名称、去除无效标签、
+// 将多级子任务合并到顶层任务下
+func Parse(source string, r io.Reader, filename string) (*Result, error) {
+	parser, ok := parsers[source]
+	if !ok {
+		return nil, errors.New("不支持的迁移来源")
+	}
+	result, err := parser(r, filename)
+	if err != nil {
+		return nil, err
+	}
+	result.normalize()
+	return result, nil
+}
+
+// normalize 规范化记录，调整的内容记录在报告中
+func (r *Result) normalize() {
+	records := r.Records[:0]
+	for _, record := range r.Records {
+		record.Title = strings.TrimSpace(record.Title)
+		if record.Title == "" {
+			r.addIssue(record.Ref, "title", "标题为空，已跳过")
+			continue
+		}
+		if truncated, ok := truncate(record.Title, maxTitleLength); ok {
+			r.addIssue(record.Title, "title", "标题过长，已截断")
+			record.Title = truncated
+		}
+		if truncated, ok := truncate(strings.TrimSpace(record.Project), maxProjectNameLength); ok {
+			r.addIssue(record.Title, "project", "项目名过长，已截断")
+			record.Project = truncated
+		} else {
+			record.Project = strings.TrimSpace(record.Project)
+		}
+
+		var tags []string
+		seen := make(map[string]bool)
+		for _, tag := range record.Tags {
+			tag = strings.TrimSpace(tag)
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+			if utf8.RuneCountInString(tag) > maxTagNameLength {
+				r.addIssue(record.Title, "tags", fmt.Sprintf("标签 %q 过长，已忽略", tag))
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+		record.Tags = tags
+		records = append(records, record)
+	}
+	r.Records = records
+	r.flattenSubtasks()
+}
+
+// flattenSubtasks 只支持一层子任务：多级子任务挂到顶层祖先下，父任务不存在或循环引用时作为顶层任务
+func (r *Result) flattenSubtasks() {
+	byRef := make(map[string]int, len(r.Records))
+	for i := range r.Records {
+		byRef[r.Records[i].Ref] = i
+	}
+
+	//先计算每条记录的顶层祖先，再统一修改，-1 表示作为顶层任务
+	roots := make([]int, len(r.Records))
+	for i := range r.Records {
+		roots[i] = -1
+		record := &r.Records[i]
+		if record.Parent == "" {
+			continue
+		}
+		if _, ok := byRef[record.Parent]; !ok {
+			r.addIssue(record.Title, "parent", "父任务不存在，已作为顶层任务导入")
+			continue
+		}
+
+		current, depth := i, 0
+		visited := map[int]bool{i: true}
+		cycle := false
+		for {
+			next, ok := byRef[r.Records[current].Parent]
+			if r.Records[current].Parent == "" || !ok {
+				break
+			}
+			if visited[next] {
+				cycle = true
+				break
+			}
+			visited[next] = true
+			current = next
+			depth++
+		}
+		switch {
+		case cycle:
+			r.addIssue(record.Title, "parent", "父任务循环引用，已作为顶层任务导入")
+		case depth > 1:
+			r.addIssue(record.Title, "parent", "多级子任务已合并到顶层任务「"+r.Records[current].Title+"」下")
+			roots[i] = current
+		default:
+			roots[i] = current
+		}
+	}
+
+	for i, root := range roots {
+		if root < 0 {
+			r.Records[i].Parent = ""
+			continue
+		}
+		r.Records[i].Parent = r.Records[root].Ref
+		r.Records[i].Project = r.Records[root].Project
+	}
+}
+
+// truncate 截断到指定字符数
+func truncate(text string, limit int) (string, bool) {
+	if utf8.RuneCountInString(text) <= limit {
+		return text, false
+	}
+	return string([]rune(text)[:limit]), true
+}
+
+// endOfDay 只有日期时截止到当天结束(本地时间)
+func endOfDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 23, 59, 59, 0, time.Local)
+}
@@ -0,0 +1,225 @@
+package migration
+
+import (
+	"TODO_API/pkg/recurrence"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// htmlTag 去除 HTML 描述中的标签
+var htmlTag = regexp.MustCompile(`(?s)<[^>]*>`)
+
+type msDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+// time 按给定时区解析，无法识别的时区按 UTC 处理
+func (d *msDateTime) time() (time.Time, bool) {
+	loc, err := time.LoadLocation(d.TimeZone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05.9999999", d.DateTime, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+type msTask struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Importance string `json:"importance"`
+	Status     string `json:"status"`
+	Body       struct {
+		Content     string `json:"content"`
+		ContentType string `json:"contentType"`
+	} `json:"body"`
+	DueDateTime       *msDateTime `json:"dueDateTime"`
+	CompletedDateTime *msDateTime `json:"completedDateTime"`
+	Categories        []string    `json:"categories"`
+	IsReminderOn      bool        `json:"isReminderOn"`
+	Recurrence        *struct {
+		Pattern struct {
+			Type       string   `json:"type"`
+			Interval   int      `json:"interval"`
+			DaysOfWeek []string `json:"daysOfWeek"`
+			DayOfMonth int      `json:"dayOfMonth"`
+		} `json:"pattern"`
+	} `json:"recurrence"`
+	ChecklistItems []struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"displayName"`
+		IsChecked   bool   `json:"isChecked"`
+	} `json:"checklistItems"`
+	LinkedResources []json.RawMessage `json:"linkedResources"`
+	Attachments     []json.RawMessage `json:"attachments"`
+}
+
+type msList struct {
+	DisplayName       string   `json:"displayName"`
+	WellknownListName string   `json:"wellknownListName"`
+	Tasks             []msTask `json:"tasks"`
+}
+
+// ParseMSTodo 解析 Microsoft To Do 的 JSON 导出(Microsoft Graph todoTaskList 及其 tasks)，
+// 支持列表数组或 {"lists": [...]}、{"value": [...]}。默认列表导入收件箱，其余列表对应项目，
+// 步骤对应子任务
+func ParseMSTodo(r io.Reader, filename string) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+
+	var lists []msList
+	if len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &lists)
+	} else {
+		var wrapper struct {
+			Lists []msList `json:"lists"`
+			Value []msList `json:"value"`
+		}
+		err = json.Unmarshal(data, &wrapper)
+		lists = append(wrapper.Lists, wrapper.Value...)
+	}
+	if err != nil {
+		return nil, errors.New("文件格式无效: " + err.Error())
+	}
+	if len(lists) == 0 {
+		return nil, errors.New("文件格式无效: 不是 Microsoft To Do 导出")
+	}
+
+	result := &Result{}
+	for i, list := range lists {
+		project := list.DisplayName
+		switch list.WellknownListName {
+		case "defaultList":
+			project = ""
+		case "flaggedEmails":
+			result.addIssue(list.DisplayName, "list", "已标记邮件列表未导入")
+			continue
+		}
+		for j, task := range list.Tasks {
+			ref := task.ID
+			if ref == "" {
+				ref = strconv.Itoa(i) + "/" + strconv.Itoa(j)
+			}
+			result.Records = append(result.Records, msTaskRecord(result, &task, ref, project))
+			for k, item := range task.ChecklistItems {
+				record := Record{
+					Ref:     ref + "/" + strconv.Itoa(k),
+					Parent:  ref,
+					Project: project,
+					Title:   item.DisplayName,
+				}
+				if item.IsChecked {
+					record.Status = 2
+				}
+				result.Records = append(result.Records, record)
+			}
+		}
+	}
+	return result, nil
+}
+
+// msTaskRecord 转换单个任务
+func msTaskRecord(result *Result, task *msTask, ref, project string) Record {
+	record := Record{
+		Ref:      ref,
+		Project:  project,
+		Title:    task.Title,
+		Priority: 1,
+		Tags:     task.Categories,
+	}
+	if task.Importance == "high" {
+		record.Priority = 3
+	}
+
+	record.Description = task.Body.Content
+	if strings.EqualFold(task.Body.ContentType, "html") {
+		record.Description = strings.TrimSpace(html.UnescapeString(htmlTag.ReplaceAllString(task.Body.Content, "\n")))
+	}
+
+	switch task.Status {
+	case "completed":
+		record.Status = 2
+		if task.CompletedDateTime != nil {
+			if t, ok := task.CompletedDateTime.time(); ok {
+				record.CompletedAt = &t
+			}
+		}
+	case "inProgress":
+		record.Status = 1
+	case "waitingOnOthers", "deferred":
+		result.addIssue(task.Title, "status", fmt.Sprintf("状态 %q 已按待办导入", task.Status))
+	}
+
+	// 截止日期在 To Do 中只精确到天
+	if task.DueDateTime != nil {
+		if t, ok := task.DueDateTime.time(); ok {
+			due := endOfDay(t.Date())
+			record.DueDate = &due
+		} else {
+			result.addIssue(task.Title, "dueDateTime", fmt.Sprintf("日期 %q 无法识别", task.DueDateTime.DateTime))
+		}
+	}
+
+	if task.Recurrence != nil {
+		if rule, ok := msRecurrence(task); ok && record.DueDate != nil {
+			record.Recurrence = rule
+		} else {
+			result.addIssue(task.Title, "recurrence", fmt.Sprintf("重复规则 %q 无法映射", task.Recurrence.Pattern.Type))
+		}
+	}
+	if task.IsReminderOn {
+		result.addIssue(task.Title, "reminder", "提醒未迁移")
+	}
+	if len(task.LinkedResources) > 0 {
+		result.addIssue(task.Title, "linkedResources", "关联资源未迁移")
+	}
+	if len(task.Attachments) > 0 {
+		result.addIssue(task.Title, "attachments", fmt.Sprintf("%d 个附件未迁移", len(task.Attachments)))
+	}
+	return record
+}
+
+// msRecurrence 将每天、每周、每月(按日期)重复转换为 RRULE
+func msRecurrence(task *msTask) (string, bool) {
+	pattern := task.Recurrence.Pattern
+	rule := &recurrence.Rule{Interval: max(pattern.Interval, 1)}
+	switch pattern.Type {
+	case "daily":
+		rule.Freq = recurrence.Daily
+	case "weekly":
+		rule.Freq = recurrence.Weekly
+		for _, day := range pattern.DaysOfWeek {
+			if len(day) < 2 {
+				return "", false
+			}
+			weekday, ok := recurrence.ParseWeekday(strings.ToUpper(day[:2]))
+			if !ok {
+				return "", false
+			}
+			rule.ByWeekday = append(rule.ByWeekday, weekday)
+		}
+	case "absoluteMonthly":
+		rule.Freq = recurrence.Monthly
+		rule.ByMonthDay = pattern.DayOfMonth
+	default:
+		return "", false
+	}
+	if rule.Validate() != nil {
+		return "", false
+	}
+	return rule.String(), true
+}
@@ -0,0 +1,328 @@
+package migration
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todoistLabel 任务内容中的 @标签
+var todoistLabel = regexp.MustCompile(`(^|\s)@([^\s@]+)`)
+
+// ParseTodoist 解析 Todoist 的项目 CSV 导出或 Sync API 的 JSON 备份，按内容的第一个字符判断格式
+func ParseTodoist(r io.Reader, filename string) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("文件格式无效: 文件为空")
+	}
+	if trimmed[0] == '{' {
+		return parseTodoistJSON(bytes.NewReader(trimmed))
+	}
+	return parseTodoistCSV(bytes.NewReader(data), filename)
+}
+
+// parseDate 解析日期时间：带时区的按原时区，不带时区的按本地时间，只有日期时截止到当天结束
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return endOfDay(t.Date()), true
+	}
+	return time.Time{}, false
+}
+
+// todoistPriorityFromCSV CSV 中 1 表示最高优先级(p1)
+func todoistPriorityFromCSV(value string) int {
+	switch strings.TrimSpace(value) {
+	case "1":
+		return 4
+	case "2":
+		return 3
+	case "3":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// splitTodoistLabels 取出内容中的 @标签，返回去掉标签后的标题
+func splitTodoistLabels(content string) (string, []string) {
+	var labels []string
+	for _, match := range todoistLabel.FindAllStringSubmatch(content, -1) {
+		labels = append(labels, match[2])
+	}
+	title := todoistLabel.ReplaceAllString(content, "$1")
+	return strings.Join(strings.Fields(title), " "), labels
+}
+
+// parseTodoistCSV 解析项目 CSV，项目名取自文件名，INDENT 表示层级，note 行附加到上一任务的描述
+func parseTodoistCSV(r io.Reader, filename string) (*Result, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("文件格式无效: " + err.Error())
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["TYPE"]; !ok {
+		return nil, errors.New("文件格式无效: 不是 Todoist CSV 导出")
+	}
+	if _, ok := columns["CONTENT"]; !ok {
+		return nil, errors.New("文件格式无效: 不是 Todoist CSV 导出")
+	}
+
+	project := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	if strings.EqualFold(project, "inbox") {
+		project = ""
+	}
+
+	result := &Result{}
+	var parents []string // 每一层最近的任务 Ref
+	last := -1           // 上一个任务在 Records 中的下标
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("文件格式无效: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		switch strings.ToLower(field("TYPE")) {
+		case "section":
+			result.addIssue(field("CONTENT"), "section", "分区未映射，其中的任务直接归入项目")
+			parents = parents[:0]
+			continue
+		case "note":
+			if last >= 0 && field("CONTENT") != "" {
+				record := &result.Records[last]
+				record.Description = strings.TrimSpace(record.Description + "\n\n" + field("CONTENT"))
+			}
+			continue
+		case "task":
+		default:
+			continue
+		}
+
+		title, labels := splitTodoistLabels(field("CONTENT"))
+		record := Record{
+			Ref:         "row-" + strconv.Itoa(line),
+			Project:     project,
+			Title:       title,
+			Description: field("DESCRIPTION"),
+			Priority:    todoistPriorityFromCSV(field("PRIORITY")),
+			Tags:        labels,
+		}
+
+		indent, err := strconv.Atoi(field("INDENT"))
+		if err != nil || indent < 1 {
+			indent = 1
+		}
+		if indent > len(parents)+1 {
+			indent = len(parents) + 1
+		}
+		parents = append(parents[:indent-1], record.Ref)
+		if indent > 1 {
+			record.Parent = parents[indent-2]
+		}
+
+		date := field("DATE")
+		if date == "" {
+			date = field("DEADLINE")
+		}
+		if date != "" {
+			if due, ok := parseDate(date); ok {
+				record.DueDate = &due
+			} else if strings.HasPrefix(strings.ToLower(date), "every") {
+				result.addIssue(title, "date", fmt.Sprintf("重复日期 %q 无法映射", date))
+			} else {
+				result.addIssue(title, "date", fmt.Sprintf("日期 %q 无法识别", date))
+			}
+		}
+		if responsible := field("RESPONSIBLE"); responsible != "" {
+			result.addIssue(title, "responsible", "负责人未映射")
+		}
+		if duration := field("DURATION"); duration != "" {
+			result.addIssue(title, "duration", "时长未映射")
+		}
+
+		result.Records = append(result.Records, record)
+		last = len(result.Records) - 1
+	}
+	return result, nil
+}
+
+// todoistID Todoist 的ID在不同版本的接口中可能是数字或字符串
+type todoistID string
+
+func (id *todoistID) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err == nil {
+		*id = todoistID(number.String())
+		return nil
+	}
+	var text *string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if text != nil {
+		*id = todoistID(*text)
+	}
+	return nil
+}
+
+type todoistDue struct {
+	Date        string `json:"date"`
+	String      string `json:"string"`
+	IsRecurring bool   `json:"is_recurring"`
+}
+
+type todoistBackup struct {
+	Projects []struct {
+		ID           todoistID `json:"id"`
+		Name         string    `json:"name"`
+		ParentID     todoistID `json:"parent_id"`
+		InboxProject bool      `json:"inbox_project"`
+		IsArchived   bool      `json:"is_archived"`
+	} `json:"projects"`
+	Sections []struct {
+		ID   todoistID `json:"id"`
+		Name string    `json:"name"`
+	} `json:"sections"`
+	Items []struct {
+		ID             todoistID   `json:"id"`
+		Content        string      `json:"content"`
+		Description    string      `json:"description"`
+		ProjectID      todoistID   `json:"project_id"`
+		SectionID      todoistID   `json:"section_id"`
+		ParentID       todoistID   `json:"parent_id"`
+		Priority       int         `json:"priority"`
+		Labels         []string    `json:"labels"`
+		Due            *todoistDue `json:"due"`
+		Deadline       *todoistDue `json:"deadline"`
+		Checked        bool        `json:"checked"`
+		CompletedAt    string      `json:"completed_at"`
+		ResponsibleUID todoistID   `json:"responsible_uid"`
+		IsDeleted      bool        `json:"is_deleted"`
+	} `json:"items"`
+	Notes []struct {
+		ItemID    todoistID `json:"item_id"`
+		Content   string    `json:"content"`
+		IsDeleted bool      `json:"is_deleted"`
+	} `json:"notes"`
+}
+
+// parseTodoistJSON 解析 Sync API 的全量同步结果，收件箱项目的任务导入收件箱
+func parseTodoistJSON(r io.Reader) (*Result, error) {
+	var backup todoistBackup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return nil, errors.New("文件格式无效: " + err.Error())
+	}
+	if backup.Items == nil {
+		return nil, errors.New("文件格式无效: 缺少 items")
+	}
+
+	result := &Result{}
+	projects := make(map[todoistID]string)
+	for _, project := range backup.Projects {
+		if project.InboxProject {
+			projects[project.ID] = ""
+			continue
+		}
+		projects[project.ID] = project.Name
+		if project.ParentID != "" {
+			result.addIssue(project.Name, "project", "子项目已作为独立项目导入")
+		}
+		if project.IsArchived {
+			result.addIssue(project.Name, "project", "已归档的项目按普通项目导入")
+		}
+	}
+	for _, section := range backup.Sections {
+		result.addIssue(section.Name, "section", "分区未映射，其中的任务直接归入项目")
+	}
+	notes := make(map[todoistID][]string)
+	for _, note := range backup.Notes {
+		if !note.IsDeleted && strings.TrimSpace(note.Content) != "" {
+			notes[note.ItemID] = append(notes[note.ItemID], strings.TrimSpace(note.Content))
+		}
+	}
+
+	for _, item := range backup.Items {
+		if item.IsDeleted {
+			continue
+		}
+		record := Record{
+			Ref:         string(item.ID),
+			Parent:      string(item.ParentID),
+			Title:       item.Content,
+			Description: strings.TrimSpace(strings.Join(append([]string{item.Description}, notes[item.ID]...), "\n\n")),
+			Priority:    item.Priority,
+			Tags:        item.Labels,
+		}
+		if record.Priority < 1 || record.Priority > 4 {
+			record.Priority = 1
+		}
+		project, ok := projects[item.ProjectID]
+		if !ok && item.ProjectID != "" {
+			result.addIssue(item.Content, "project", "所属项目不在导出文件中，已导入收件箱")
+		}
+		record.Project = project
+
+		due := item.Due
+		if due == nil || due.Date == "" {
+			due = item.Deadline
+		}
+		if due != nil && due.Date != "" {
+			if t, ok := parseDate(due.Date); ok {
+				record.DueDate = &t
+			} else {
+				result.addIssue(item.Content, "due", fmt.Sprintf("日期 %q 无法识别", due.Date))
+			}
+			if due.IsRecurring {
+				result.addIssue(item.Content, "due", fmt.Sprintf("重复规则 %q 无法映射，仅导入截止时间", due.String))
+			}
+		}
+
+		if item.Checked {
+			record.Status = 2
+			if t, err := time.Parse(time.RFC3339, item.CompletedAt); err == nil {
+				record.CompletedAt = &t
+			}
+		}
+		if item.ResponsibleUID != "" {
+			result.addIssue(item.Content, "responsible", "负责人未映射")
+		}
+		result.Records = append(result.Records, record)
+	}
+	return result, nil
+}
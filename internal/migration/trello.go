@@ -0,0 +1,147 @@
+package migration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		Desc        string   `json:"desc"`
+		IDList      string   `json:"idList"`
+		Closed      bool     `json:"closed"`
+		Due         string   `json:"due"`
+		DueComplete bool     `json:"dueComplete"`
+		Start       string   `json:"start"`
+		IDMembers   []string `json:"idMembers"`
+		Labels      []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+		Badges struct {
+			Attachments int `json:"attachments"`
+			Comments    int `json:"comments"`
+		} `json:"badges"`
+	} `json:"cards"`
+	Checklists []struct {
+		ID         string `json:"id"`
+		IDCard     string `json:"idCard"`
+		CheckItems []struct {
+			ID    string `json:"id"`
+			Name  string `json:"name"`
+			State string `json:"state"`
+			Due   string `json:"due"`
+		} `json:"checkItems"`
+	} `json:"checklists"`
+}
+
+// ParseTrello 解析 Trello 看板的 JSON 导出：看板对应项目，卡片所在列表及标签对应标签，
+// 检查项对应子任务，已归档的卡片及列表不导入
+func ParseTrello(r io.Reader, filename string) (*Result, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, errors.New("文件格式无效: " + err.Error())
+	}
+	if board.Cards == nil || board.Lists == nil {
+		return nil, errors.New("文件格式无效: 不是 Trello 看板导出")
+	}
+
+	result := &Result{}
+	lists := make(map[string]string, len(board.Lists))
+	closedLists := make(map[string]bool)
+	for _, list := range board.Lists {
+		lists[list.ID] = list.Name
+		if list.Closed {
+			closedLists[list.ID] = true
+			result.addIssue(list.Name, "list", "列表已归档，其中的卡片未导入")
+		}
+	}
+
+	cards := make(map[string]bool, len(board.Cards))
+	for _, card := range board.Cards {
+		if closedLists[card.IDList] {
+			continue
+		}
+		if card.Closed {
+			result.addIssue(card.Name, "closed", "卡片已归档，未导入")
+			continue
+		}
+		cards[card.ID] = true
+
+		record := Record{
+			Ref:         card.ID,
+			Project:     board.Name,
+			Title:       card.Name,
+			Description: card.Desc,
+		}
+		if name := lists[card.IDList]; name != "" {
+			record.Tags = append(record.Tags, name)
+		}
+		for _, label := range card.Labels {
+			if label.Name != "" {
+				record.Tags = append(record.Tags, label.Name)
+			} else if label.Color != "" {
+				result.addIssue(card.Name, "labels", fmt.Sprintf("无名称的标签已按颜色 %q 导入", label.Color))
+				record.Tags = append(record.Tags, label.Color)
+			}
+		}
+		if card.Due != "" {
+			if due, err := time.Parse(time.RFC3339, card.Due); err == nil {
+				record.DueDate = &due
+			} else {
+				result.addIssue(card.Name, "due", fmt.Sprintf("日期 %q 无法识别", card.Due))
+			}
+		}
+		if card.DueComplete {
+			record.Status = 2
+		}
+		if card.Start != "" {
+			result.addIssue(card.Name, "start", "开始时间未映射")
+		}
+		if len(card.IDMembers) > 0 {
+			result.addIssue(card.Name, "members", "成员未映射")
+		}
+		if card.Badges.Attachments > 0 {
+			result.addIssue(card.Name, "attachments", fmt.Sprintf("%d 个附件未迁移", card.Badges.Attachments))
+		}
+		if card.Badges.Comments > 0 {
+			result.addIssue(card.Name, "comments", fmt.Sprintf("%d 条评论未迁移", card.Badges.Comments))
+		}
+		result.Records = append(result.Records, record)
+	}
+
+	for _, checklist := range board.Checklists {
+		if !cards[checklist.IDCard] {
+			continue
+		}
+		for _, item := range checklist.CheckItems {
+			record := Record{
+				Ref:     checklist.ID + "/" + item.ID,
+				Parent:  checklist.IDCard,
+				Project: board.Name,
+				Title:   item.Name,
+			}
+			if item.State == "complete" {
+				record.Status = 2
+			}
+			if item.Due != "" {
+				if due, err := time.Parse(time.RFC3339, item.Due); err == nil {
+					record.DueDate = &due
+				}
+			}
+			result.Records = append(result.Records, record)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AppPasswordRepository 应用专用密码仓储接口
+type AppPasswordRepository interface {
+	Create(ctx context.Context, password *model.AppPassword) error
+	GetByUserID(ctx context.Context, userID uint) ([]model.AppPassword, error)
+	GetByHash(ctx context.Context, passwordHash string) (*model.AppPassword, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	Delete(ctx context.Context, id, userID uint) (bool, error)
+	TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error
+}
+
+type appPasswordRepository struct {
+	db *gorm.DB
+}
+
+// NewAppPasswordRepository 创建应用专用密码仓储实例
+func NewAppPasswordRepository(db *gorm.DB) AppPasswordRepository {
+	return &appPasswordRepository{db: db}
+}
+
+// Create 创建应用专用密码
+func (r *appPasswordRepository) Create(ctx context.Context, password *model.AppPassword) error {
+	return conn(ctx, r.db).Create(password).Error
+}
+
+// GetByUserID 获取用户的全部应用专用密码，最新创建的在前
+func (r *appPasswordRepository) GetByUserID(ctx context.Context, userID uint) ([]model.AppPassword, error) {
+	var passwords []model.AppPassword
+	err := conn(ctx, r.db).Where("user_id = ?", userID).
+		Order("created_at DESC").Order("id DESC").
+		Find(&passwords).Error
+	return passwords, err
+}
+
+// GetByHash 根据密码哈希获取应用专用密码
+func (r *appPasswordRepository) GetByHash(ctx context.Context, passwordHash string) (*model.AppPassword, error) {
+	var password model.AppPassword
+	err := conn(ctx, r.db).First(&password, "password_hash = ?", passwordHash).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &password, nil
+}
+
+// CountByUserID 统计用户的应用专用密码数量
+func (r *appPasswordRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.AppPassword{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Delete 删除用户的应用专用密码，返回是否存在
+func (r *appPasswordRepository) Delete(ctx context.Context, id, userID uint) (bool, error) {
+	result := conn(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).Delete(&model.AppPassword{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// TouchLastUsed 更新最近使用时间
+func (r *appPasswordRepository) TouchLastUsed(ctx context.Context, id uint, usedAt time.Time) error {
+	return conn(ctx, r.db).Model(&model.AppPassword{}).Where("id = ?", id).
+		Update("last_used_at", usedAt).Error
+}
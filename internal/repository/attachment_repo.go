@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// AttachmentRepository 附件仓储接口
+type AttachmentRepository interface {
+	Create(ctx context.Context, attachment *model.Attachment) error
+	GetByID(ctx context.Context, id uint) (*model.Attachment, error)
+	GetByTodoID(ctx context.Context, todoID uint) ([]model.Attachment, error)
+	GetByTodoIDs(ctx context.Context, todoIDs []uint) ([]model.Attachment, error)
+	Delete(ctx context.Context, id uint) error
+	DeleteByTodoIDs(ctx context.Context, todoIDs []uint) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAttachmentRepository 创建附件仓储实例
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+// Create 创建附件记录
+func (r *attachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	return conn(ctx, r.db).Create(attachment).Error
+}
+
+// GetByID 根据ID获取附件
+func (r *attachmentRepository) GetByID(ctx context.Context, id uint) (*model.Attachment, error) {
+	var attachment model.Attachment
+	err := conn(ctx, r.db).First(&attachment, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetByTodoID 获取待办事项的全部附件
+func (r *attachmentRepository) GetByTodoID(ctx context.Context, todoID uint) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	err := conn(ctx, r.db).Where("todo_id = ?", todoID).
+		Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// GetByTodoIDs 获取多个待办事项的附件
+func (r *attachmentRepository) GetByTodoIDs(ctx context.Context, todoIDs []uint) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	if len(todoIDs) == 0 {
+		return attachments, nil
+	}
+	err := conn(ctx, r.db).Where("todo_id IN ?", todoIDs).Find(&attachments).Error
+	return attachments, err
+}
+
+// Delete 删除附件记录
+func (r *attachmentRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Attachment{}, id).Error
+}
+
+// DeleteByTodoIDs 删除多个待办事项的附件记录
+func (r *attachmentRepository) DeleteByTodoIDs(ctx context.Context, todoIDs []uint) error {
+	if len(todoIDs) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Where("todo_id IN ?", todoIDs).Delete(&model.Attachment{}).Error
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// CommentRepository 评论仓储接口
+type CommentRepository interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	GetByID(ctx context.Context, id uint) (*model.Comment, error)
+	GetByTodoID(ctx context.Context, todoID uint, page, pageSize uint) ([]model.Comment, int64, error)
+	CountByTodoIDs(ctx context.Context, todoIDs []uint) (map[uint]uint, error)
+	Update(ctx context.Context, comment *model.Comment) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+// NewCommentRepository 创建评论仓储实例
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+// Create 创建评论
+func (r *commentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	return conn(ctx, r.db).Create(comment).Error
+}
+
+// GetByID 根据ID获取评论
+func (r *commentRepository) GetByID(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	err := conn(ctx, r.db).Preload("User").First(&comment, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetByTodoID 分页获取待办事项的评论，按时间正序
+func (r *commentRepository) GetByTodoID(ctx context.Context, todoID uint, page, pageSize uint) ([]model.Comment, int64, error) {
+	var comments []model.Comment
+	var totalCount int64
+
+	query := conn(ctx, r.db).Model(&model.Comment{}).Where("todo_id = ?", todoID)
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := int((page - 1) * pageSize)
+	err := query.Preload("User").
+		Order("created_at ASC").Order("id ASC").
+		Offset(offset).Limit(int(pageSize)).
+		Find(&comments).Error
+	return comments, totalCount, err
+}
+
+// CountByTodoIDs 统计多个待办事项的评论数
+func (r *commentRepository) CountByTodoIDs(ctx context.Context, todoIDs []uint) (map[uint]uint, error) {
+	counts := make(map[uint]uint, len(todoIDs))
+	if len(todoIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TodoID uint
+		Count  uint
+	}
+	err := conn(ctx, r.db).Model(&model.Comment{}).
+		Select("todo_id, COUNT(*) AS count").
+		Where("todo_id IN ?", todoIDs).
+		Group("todo_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TodoID] = row.Count
+	}
+	return counts, nil
+}
+
+// Update 更新评论
+func (r *commentRepository) Update(ctx context.Context, comment *model.Comment) error {
+	return conn(ctx, r.db).Omit("User").Save(comment).Error
+}
+
+// Delete 删除评论(软删除)
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Comment{}, id).Error
+}
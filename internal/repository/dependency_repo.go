@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DependencyRepository 待办事项依赖仓储接口
+type DependencyRepository interface {
+	Create(ctx context.Context, dependency *model.TodoDependency) error
+	Delete(ctx context.Context, todoID, blockerID uint) (bool, error)
+	Exists(ctx context.Context, todoID, blockerID uint) (bool, error)
+	LockOwner(ctx context.Context, userID uint) error
+	GetByUserID(ctx context.Context, userID uint) ([]model.TodoDependency, error)
+	GetBlockers(ctx context.Context, todoID uint) ([]model.Todo, error)
+	GetBlocking(ctx context.Context, blockerID uint) ([]model.Todo, error)
+	GetBlockedIDs(ctx context.Context, todoIDs []uint) (map[uint]bool, error)
+}
+
+type dependencyRepository struct {
+	db *gorm.DB
+}
+
+// NewDependencyRepository 创建待办事项依赖仓储实例
+func NewDependencyRepository(db *gorm.DB) DependencyRepository {
+	return &dependencyRepository{db: db}
+}
+
+// Create 创建依赖关系
+func (r *dependencyRepository) Create(ctx context.Context, dependency *model.TodoDependency) error {
+	return conn(ctx, r.db).Create(dependency).Error
+}
+
+// Delete 删除依赖关系，返回是否存在
+func (r *dependencyRepository) Delete(ctx context.Context, todoID, blockerID uint) (bool, error) {
+	result := conn(ctx, r.db).Where("todo_id = ? AND blocker_id = ?", todoID, blockerID).
+		Delete(&model.TodoDependency{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Exists 检查依赖关系是否已存在
+func (r *dependencyRepository) Exists(ctx context.Context, todoID, blockerID uint) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.TodoDependency{}).
+		Where("todo_id = ? AND blocker_id = ?", todoID, blockerID).Count(&count).Error
+	return count > 0, err
+}
+
+// LockOwner 锁定所有者的用户行直到事务结束，串行化同一用户的依赖关系修改，须在事务中调用
+func (r *dependencyRepository) LockOwner(ctx context.Context, userID uint) error {
+	var user model.User
+	return conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").First(&user, userID).Error
+}
+
+// GetByUserID 获取用户的全部依赖关系，用于环检测
+func (r *dependencyRepository) GetByUserID(ctx context.Context, userID uint) ([]model.TodoDependency, error) {
+	var dependencies []model.TodoDependency
+	err := conn(ctx, r.db).Where("user_id = ?", userID).Find(&dependencies).Error
+	return dependencies, err
+}
+
+// GetBlockers 获取阻塞该待办事项的前置任务，不含回收站中的任务
+func (r *dependencyRepository) GetBlockers(ctx context.Context, todoID uint) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := conn(ctx, r.db).Preload("Tags").
+		Where("id IN (?)", r.db.Model(&model.TodoDependency{}).Select("blocker_id").Where("todo_id = ?", todoID)).
+		Order("id ASC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// GetBlocking 获取被该待办事项阻塞的任务，不含回收站中的任务
+func (r *dependencyRepository) GetBlocking(ctx context.Context, blockerID uint) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := conn(ctx, r.db).Preload("Tags").
+		Where("id IN (?)", r.db.Model(&model.TodoDependency{}).Select("todo_id").Where("blocker_id = ?", blockerID)).
+		Order("id ASC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// GetBlockedIDs 查询存在未完成前置任务的待办事项，回收站中的前置任务不再阻塞
+func (r *dependencyRepository) GetBlockedIDs(ctx context.Context, todoIDs []uint) (map[uint]bool, error) {
+	blocked := make(map[uint]bool)
+	if len(todoIDs) == 0 {
+		return blocked, nil
+	}
+	var ids []uint
+	err := conn(ctx, r.db).Table("todo_dependencies AS d").
+		Joins("JOIN todos AS b ON b.id = d.blocker_id AND b.deleted_at IS NULL").
+		Where("d.todo_id IN ? AND b.status <> ?", todoIDs, 2).
+		Distinct().Pluck("d.todo_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		blocked[id] = true
+	}
+	return blocked, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// HistoryRepository 待办事项变更记录仓储接口
+type HistoryRepository interface {
+	Create(ctx context.Context, events []model.TodoHistory) error
+	GetByTodoID(ctx context.Context, todoID uint) ([]model.TodoHistory, error)
+}
+
+type historyRepository struct {
+	db *gorm.DB
+}
+
+// NewHistoryRepository 创建变更记录仓储实例
+func NewHistoryRepository(db *gorm.DB) HistoryRepository {
+	return &historyRepository{db: db}
+}
+
+// Create 批量写入变更记录
+func (r *historyRepository) Create(ctx context.Context, events []model.TodoHistory) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Omit("User").Create(&events).Error
+}
+
+// GetByTodoID 获取待办事项的变更记录，最新的在前
+func (r *historyRepository) GetByTodoID(ctx context.Context, todoID uint) ([]model.TodoHistory, error) {
+	var events []model.TodoHistory
+	err := conn(ctx, r.db).Preload("User").
+		Where("todo_id = ?", todoID).
+		Order("created_at DESC").Order("id DESC").
+		Find(&events).Error
+	return events, err
+}
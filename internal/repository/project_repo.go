@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ProjectRepository 项目仓储接口
+type ProjectRepository interface {
+	Create(ctx context.Context, project *model.Project) error
+	GetByID(ctx context.Context, id uint) (*model.Project, error)
+	GetByName(ctx context.Context, userID uint, name string) (*model.Project, error)
+	GetByUserID(ctx context.Context, userID uint) ([]model.Project, error)
+	Update(ctx context.Context, project *model.Project) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type projectRepository struct {
+	db *gorm.DB
+}
+
+// NewProjectRepository 创建项目仓储实例
+func NewProjectRepository(db *gorm.DB) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+// Create 创建项目
+func (r *projectRepository) Create(ctx context.Context, project *model.Project) error {
+	return conn(ctx, r.db).Create(project).Error
+}
+
+// GetByID 根据ID获取项目
+func (r *projectRepository) GetByID(ctx context.Context, id uint) (*model.Project, error) {
+	var project model.Project
+	err := conn(ctx, r.db).First(&project, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetByName 根据名称获取用户的项目
+func (r *projectRepository) GetByName(ctx context.Context, userID uint, name string) (*model.Project, error) {
+	var project model.Project
+	err := conn(ctx, r.db).First(&project, "user_id = ? AND name = ?", userID, name).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetByUserID 获取用户的全部项目
+func (r *projectRepository) GetByUserID(ctx context.Context, userID uint) ([]model.Project, error) {
+	var projects []model.Project
+	err := conn(ctx, r.db).Where("user_id = ?", userID).
+		Order("created_at ASC").Find(&projects).Error
+	return projects, err
+}
+
+// Update 更新项目
+func (r *projectRepository) Update(ctx context.Context, project *model.Project) error {
+	return conn(ctx, r.db).Save(project).Error
+}
+
+// Delete 删除项目，项目内的待办事项由调用方先行处理
+func (r *projectRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Project{}, id).Error
+}
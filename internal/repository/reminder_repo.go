@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReminderRepository 提醒仓储接口
+type ReminderRepository interface {
+	Create(ctx context.Context, reminder *model.Reminder) error
+	GetByID(ctx context.Context, id uint) (*model.Reminder, error)
+	GetByTodoID(ctx context.Context, todoID uint) ([]model.Reminder, error)
+	Delete(ctx context.Context, id uint) error
+	GetDue(ctx context.Context, now time.Time, limit int) ([]model.Reminder, error)
+	MarkSent(ctx context.Context, id uint, sentAt time.Time) error
+	MarkRetry(ctx context.Context, id uint, attempts int, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id uint, attempts int, failedAt time.Time) error
+	RescheduleRelative(ctx context.Context, todoID uint, dueDate time.Time) error
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository 创建提醒仓储实例
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+// Create 创建提醒
+func (r *reminderRepository) Create(ctx context.Context, reminder *model.Reminder) error {
+	return conn(ctx, r.db).Create(reminder).Error
+}
+
+// GetByID 根据ID获取提醒
+func (r *reminderRepository) GetByID(ctx context.Context, id uint) (*model.Reminder, error) {
+	var reminder model.Reminder
+	err := conn(ctx, r.db).First(&reminder, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &reminder, nil
+}
+
+// GetByTodoID 获取待办事项的全部提醒
+func (r *reminderRepository) GetByTodoID(ctx context.Context, todoID uint) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	err := conn(ctx, r.db).Where("todo_id = ?", todoID).
+		Order("remind_at ASC").Find(&reminders).Error
+	return reminders, err
+}
+
+// Delete 删除提醒
+func (r *reminderRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Reminder{}, id).Error
+}
+
+// GetDue 获取已到提醒时间且未发送的提醒，跳过未到重试时间及已放弃的提醒，已删除的待办事项不会被预加载
+func (r *reminderRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	err := conn(ctx, r.db).
+		Where("sent_at IS NULL AND failed_at IS NULL AND remind_at <= ?", now).
+		Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+		Order("remind_at ASC").Limit(limit).
+		Preload("Todo").Find(&reminders).Error
+	return reminders, err
+}
+
+// MarkSent 标记提醒已发送
+func (r *reminderRepository) MarkSent(ctx context.Context, id uint, sentAt time.Time) error {
+	return conn(ctx, r.db).Model(&model.Reminder{}).
+		Where("id = ?", id).Update("sent_at", sentAt).Error
+}
+
+// MarkRetry 记录发送失败，到 nextAttemptAt 后再重试
+func (r *reminderRepository) MarkRetry(ctx context.Context, id uint, attempts int, nextAttemptAt time.Time) error {
+	return conn(ctx, r.db).Model(&model.Reminder{}).Where("id = ?", id).
+		Updates(map[string]any{"attempts": attempts, "next_attempt_at": nextAttemptAt}).Error
+}
+
+// MarkFailed 多次发送失败后标记放弃，不再重试
+func (r *reminderRepository) MarkFailed(ctx context.Context, id uint, attempts int, failedAt time.Time) error {
+	return conn(ctx, r.db).Model(&model.Reminder{}).Where("id = ?", id).
+		Updates(map[string]any{"attempts": attempts, "next_attempt_at": nil, "failed_at": failedAt}).Error
+}
+
+// RescheduleRelative 截止时间变化后重新计算未发送的相对提醒，并重新开始计算发送失败的次数
+func (r *reminderRepository) RescheduleRelative(ctx context.Context, todoID uint, dueDate time.Time) error {
+	return conn(ctx, r.db).Model(&model.Reminder{}).
+		Where("todo_id = ? AND offset_minutes IS NOT NULL AND sent_at IS NULL", todoID).
+		Updates(map[string]any{
+			"remind_at":       gorm.Expr("DATE_SUB(?, INTERVAL offset_minutes MINUTE)", dueDate),
+			"attempts":        0,
+			"next_attempt_at": nil,
+			"failed_at":       nil,
+		}).Error
+}
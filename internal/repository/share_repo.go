@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ShareRepository 共享仓储接口
+type ShareRepository interface {
+	Create(ctx context.Context, share *model.Share) error
+	GetByID(ctx context.Context, id uint) (*model.Share, error)
+	Get(ctx context.Context, resourceType string, resourceID, userID uint) (*model.Share, error)
+	GetByResource(ctx context.Context, resourceType string, resourceID uint) ([]model.Share, error)
+	GetByUserID(ctx context.Context, userID uint) ([]model.Share, error)
+	FindForTodo(ctx context.Context, userID uint, todoIDs []uint, projectID *uint) ([]model.Share, error)
+	Update(ctx context.Context, share *model.Share) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type shareRepository struct {
+	db *gorm.DB
+}
+
+// NewShareRepository 创建共享仓储实例
+func NewShareRepository(db *gorm.DB) ShareRepository {
+	return &shareRepository{db: db}
+}
+
+// Create 创建共享
+func (r *shareRepository) Create(ctx context.Context, share *model.Share) error {
+	return conn(ctx, r.db).Create(share).Error
+}
+
+// GetByID 根据ID获取共享
+func (r *shareRepository) GetByID(ctx context.Context, id uint) (*model.Share, error) {
+	var share model.Share
+	err := conn(ctx, r.db).Preload("Owner").Preload("User").First(&share, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// Get 获取资源对某个用户的共享
+func (r *shareRepository) Get(ctx context.Context, resourceType string, resourceID, userID uint) (*model.Share, error) {
+	var share model.Share
+	err := conn(ctx, r.db).
+		First(&share, "resource_type = ? AND resource_id = ? AND user_id = ?", resourceType, resourceID, userID).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &share, nil
+}
+
+// GetByResource 获取资源的全部共享
+func (r *shareRepository) GetByResource(ctx context.Context, resourceType string, resourceID uint) ([]model.Share, error) {
+	var shares []model.Share
+	err := conn(ctx, r.db).Preload("Owner").Preload("User").
+		Where("resource_type = ? AND resource_id = ?", resourceType, resourceID).
+		Order("created_at ASC").Find(&shares).Error
+	return shares, err
+}
+
+// GetByUserID 获取共享给某用户的全部记录
+func (r *shareRepository) GetByUserID(ctx context.Context, userID uint) ([]model.Share, error) {
+	var shares []model.Share
+	err := conn(ctx, r.db).Preload("Owner").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").Find(&shares).Error
+	return shares, err
+}
+
+// FindForTodo 查找用户在待办事项(及其父任务)或所属项目上的共享
+func (r *shareRepository) FindForTodo(ctx context.Context, userID uint, todoIDs []uint, projectID *uint) ([]model.Share, error) {
+	var shares []model.Share
+	query := conn(ctx, r.db).Where("user_id = ?", userID)
+	if projectID != nil {
+		query = query.Where(r.db.Where("resource_type = ? AND resource_id IN (?)", model.ShareResourceTodo, todoIDs).
+			Or("resource_type = ? AND resource_id = ?", model.ShareResourceProject, *projectID))
+	} else {
+		query = query.Where("resource_type = ? AND resource_id IN (?)", model.ShareResourceTodo, todoIDs)
+	}
+	err := query.Find(&shares).Error
+	return shares, err
+}
+
+// Update 更新共享
+func (r *shareRepository) Update(ctx context.Context, share *model.Share) error {
+	return conn(ctx, r.db).Omit("Owner", "User").Save(share).Error
+}
+
+// Delete 删除共享
+func (r *shareRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Share{}, id).Error
+}
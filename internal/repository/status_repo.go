@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// StatusRepository 自定义状态仓储接口
+type StatusRepository interface {
+	Create(ctx context.Context, status *model.WorkflowStatus) error
+	GetByID(ctx context.Context, id uint) (*model.WorkflowStatus, error)
+	GetByName(ctx context.Context, userID uint, name string) (*model.WorkflowStatus, error)
+	GetByUserID(ctx context.Context, userID uint) ([]model.WorkflowStatus, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]model.WorkflowStatus, error)
+	CountByUserID(ctx context.Context, userID uint) (int64, error)
+	Update(ctx context.Context, status *model.WorkflowStatus) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type statusRepository struct {
+	db *gorm.DB
+}
+
+// NewStatusRepository 创建自定义状态仓储实例
+func NewStatusRepository(db *gorm.DB) StatusRepository {
+	return &statusRepository{db: db}
+}
+
+// Create 创建自定义状态
+func (r *statusRepository) Create(ctx context.Context, status *model.WorkflowStatus) error {
+	return conn(ctx, r.db).Create(status).Error
+}
+
+// GetByID 根据ID获取自定义状态
+func (r *statusRepository) GetByID(ctx context.Context, id uint) (*model.WorkflowStatus, error) {
+	var status model.WorkflowStatus
+	err := conn(ctx, r.db).First(&status, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetByName 根据名称获取用户的自定义状态
+func (r *statusRepository) GetByName(ctx context.Context, userID uint, name string) (*model.WorkflowStatus, error) {
+	var status model.WorkflowStatus
+	err := conn(ctx, r.db).First(&status, "user_id = ? AND name = ?", userID, name).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetByUserID 获取用户的全部自定义状态，按分类及分类内的位置排列
+func (r *statusRepository) GetByUserID(ctx context.Context, userID uint) ([]model.WorkflowStatus, error) {
+	var statuses []model.WorkflowStatus
+	err := conn(ctx, r.db).Where("user_id = ?", userID).
+		Order("category ASC, position ASC, id ASC").Find(&statuses).Error
+	return statuses, err
+}
+
+// GetByIDs 根据ID列表获取自定义状态，用于填充待办事项的状态名称
+func (r *statusRepository) GetByIDs(ctx context.Context, ids []uint) ([]model.WorkflowStatus, error) {
+	var statuses []model.WorkflowStatus
+	if len(ids) == 0 {
+		return statuses, nil
+	}
+	err := conn(ctx, r.db).Where("id IN (?)", ids).Find(&statuses).Error
+	return statuses, err
+}
+
+// CountByUserID 统计用户的自定义状态数量
+func (r *statusRepository) CountByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.WorkflowStatus{}).
+		Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Update 更新自定义状态
+func (r *statusRepository) Update(ctx context.Context, status *model.WorkflowStatus) error {
+	return conn(ctx, r.db).Save(status).Error
+}
+
+// Delete 删除自定义状态，使用该状态的待办事项由外键置空，保留所属分类
+func (r *statusRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.WorkflowStatus{}, id).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository 标签仓储接口
+type TagRepository interface {
+	Create(ctx context.Context, tag *model.Tag) error
+	GetByID(ctx context.Context, id uint) (*model.Tag, error)
+	GetByName(ctx context.Context, userID uint, name string) (*model.Tag, error)
+	GetByUserID(ctx context.Context, userID uint) ([]model.Tag, error)
+	GetByIDs(ctx context.Context, userID uint, ids []uint) ([]model.Tag, error)
+	Update(ctx context.Context, tag *model.Tag) error
+	Delete(ctx context.Context, id uint) error
+	AttachToTodo(ctx context.Context, todoID uint, tagIDs []uint) error
+	DetachFromTodo(ctx context.Context, todoID uint, tagIDs []uint) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository 创建标签仓储实例
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// Create 创建标签
+func (r *tagRepository) Create(ctx context.Context, tag *model.Tag) error {
+	return conn(ctx, r.db).Create(tag).Error
+}
+
+// GetByID 根据ID获取标签
+func (r *tagRepository) GetByID(ctx context.Context, id uint) (*model.Tag, error) {
+	var tag model.Tag
+	err := conn(ctx, r.db).First(&tag, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByName 根据名称获取用户的标签
+func (r *tagRepository) GetByName(ctx context.Context, userID uint, name string) (*model.Tag, error) {
+	var tag model.Tag
+	err := conn(ctx, r.db).First(&tag, "user_id = ? AND name = ?", userID, name).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetByUserID 获取用户的全部标签
+func (r *tagRepository) GetByUserID(ctx context.Context, userID uint) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := conn(ctx, r.db).Where("user_id = ?", userID).
+		Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+// GetByIDs 根据ID列表获取用户的标签，不属于该用户的标签会被忽略
+func (r *tagRepository) GetByIDs(ctx context.Context, userID uint, ids []uint) ([]model.Tag, error) {
+	var tags []model.Tag
+	err := conn(ctx, r.db).Where("user_id = ? AND id IN (?)", userID, ids).
+		Find(&tags).Error
+	return tags, err
+}
+
+// Update 更新标签
+func (r *tagRepository) Update(ctx context.Context, tag *model.Tag) error {
+	return conn(ctx, r.db).Save(tag).Error
+}
+
+// Delete 删除标签，关联关系由外键级联删除
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Tag{}, id).Error
+}
+
+// AttachToTodo 为待办事项添加标签，已存在的关联会被忽略
+func (r *tagRepository) AttachToTodo(ctx context.Context, todoID uint, tagIDs []uint) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	links := make([]model.TodoTag, len(tagIDs))
+	for i, tagID := range tagIDs {
+		links[i] = model.TodoTag{TodoID: todoID, TagID: tagID}
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&links).Error
+}
+
+// DetachFromTodo 移除待办事项上的标签
+func (r *tagRepository) DetachFromTodo(ctx context.Context, todoID uint, tagIDs []uint) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).
+		Where("todo_id = ? AND tag_id IN (?)", todoID, tagIDs).
+		Delete(&model.TodoTag{}).Error
+}
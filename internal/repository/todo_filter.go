@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/filter"
+	"fmt"
+	"time"
+)
+
+// filterColumns 筛选字段对应的列，列名只来自这里，不拼接用户输入
+var filterColumns = map[string]string{
+	"status":    "status",
+	"priority":  "priority",
+	"project":   "COALESCE(project_id, 0)", // 收件箱为 0
+	"due":       "due_date",
+	"created":   "created_at",
+	"updated":   "updated_at",
+	"completed": "completed_at",
+}
+
+// nullableColumns 可能为 NULL 的列。SQL 中与 NULL 比较的结果为 NULL，NOT 之后仍为 NULL，
+// 因此比较条件先排除 NULL，使 not due < 2026-11-01 也能匹配未设置截止时间的待办事项
+var nullableColumns = map[string]bool{
+	"due_date":     true,
+	"completed_at": true,
+}
+
+// filterCondition 将筛选表达式转换为 SQL 条件，值全部以参数形式传入
+func (r *todoRepository) filterCondition(node filter.Node, now time.Time) (string, []any) {
+	switch n := node.(type) {
+	case filter.And:
+		left, leftArgs := r.filterCondition(n.Left, now)
+		right, rightArgs := r.filterCondition(n.Right, now)
+		return "(" + left + ") AND (" + right + ")", append(leftArgs, rightArgs...)
+	case filter.Or:
+		left, leftArgs := r.filterCondition(n.Left, now)
+		right, rightArgs := r.filterCondition(n.Right, now)
+		return "(" + left + ") OR (" + right + ")", append(leftArgs, rightArgs...)
+	case filter.Not:
+		expr, args := r.filterCondition(n.Expr, now)
+		return "NOT (" + expr + ")", args
+	case filter.Flag:
+		return flagCondition(n.Name, now)
+	case filter.Compare:
+		if n.Field.Name == "tag" {
+			return r.tagCondition(n)
+		}
+		if n.Field.Type == filter.TypeTime {
+			column := filterColumns[n.Field.Name]
+			condition, args := timeCondition(column, n.Op, n.Values[0])
+			if nullableColumns[column] {
+				condition = column + " IS NOT NULL AND (" + condition + ")"
+			}
+			return condition, args
+		}
+		return intCondition(filterColumns[n.Field.Name], n.Op, n.Values)
+	}
+	return "1 = 1", nil
+}
+
+// flagCondition 无参数条件，过期的判断与响应中的 is_overdue 保持一致
+func flagCondition(name string, now time.Time) (string, []any) {
+	switch name {
+	case filter.FlagOverdue:
+		return "due_date IS NOT NULL AND due_date < ? AND status <> ?", []any{now, 2}
+	case filter.FlagHasDue:
+		return "due_date IS NOT NULL", nil
+	case filter.FlagNoDue:
+		return "due_date IS NULL", nil
+	}
+	return "1 = 1", nil
+}
+
+// intValues 取出数值列表
+func intValues(values []filter.Value) []int64 {
+	ints := make([]int64, len(values))
+	for i, value := range values {
+		ints[i] = value.Int
+	}
+	return ints
+}
+
+// intCondition 数值字段比较
+func intCondition(column string, op filter.Op, values []filter.Value) (string, []any) {
+	switch op {
+	case filter.OpIn:
+		return column + " IN ?", []any{intValues(values)}
+	case filter.OpNotIn:
+		return column + " NOT IN ?", []any{intValues(values)}
+	case filter.OpNe:
+		return column + " <> ?", []any{values[0].Int}
+	}
+	return fmt.Sprintf("%s %s ?", column, op), []any{values[0].Int}
+}
+
+// timeCondition 时间字段比较，只有日期时按整天处理，如 due <= 2026-11-01 包含当天
+func timeCondition(column string, op filter.Op, value filter.Value) (string, []any) {
+	if !value.DateOnly {
+		if op == filter.OpNe {
+			return column + " <> ?", []any{value.Time}
+		}
+		return fmt.Sprintf("%s %s ?", column, op), []any{value.Time}
+	}
+
+	dayStart, nextDay := value.Time, value.Time.AddDate(0, 0, 1)
+	switch op {
+	case filter.OpEq:
+		return column + " >= ? AND " + column + " < ?", []any{dayStart, nextDay}
+	case filter.OpNe:
+		return column + " < ? OR " + column + " >= ?", []any{dayStart, nextDay}
+	case filter.OpLt:
+		return column + " < ?", []any{dayStart}
+	case filter.OpLe:
+		return column + " < ?", []any{nextDay}
+	case filter.OpGt:
+		return column + " >= ?", []any{nextDay}
+	}
+	return column + " >= ?", []any{dayStart}
+}
+
+// tagCondition 标签条件，通过关联表子查询匹配
+func (r *todoRepository) tagCondition(n filter.Compare) (string, []any) {
+	tagIDs := intValues(n.Values)
+	subQuery := r.db.Model(&model.TodoTag{}).Select("todo_id").Where("tag_id IN ?", tagIDs)
+	if n.Op == filter.OpNe || n.Op == filter.OpNotIn {
+		return "id NOT IN (?)", []any{subQuery}
+	}
+	return "id IN (?)", []any{subQuery}
+}
@@ -2,21 +2,69 @@ package repository
 
 import (
 	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/filter"
+	"TODO_API/pkg/rank"
 	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// TodoFilter 待办事项列表筛选条件
+type TodoFilter struct {
+	Status    *uint8
+	Priority  *uint8
+	TagID     *uint
+	ProjectID *uint       // 0 表示收件箱(未归属项目)
+	IDs       []uint      // 限定在关键词的搜索结果内，nil 表示不限
+	Expr      filter.Node // filter 参数解析后的筛选表达式
+}
+
+// TodoImportItem 批量导入的一条待办事项
+type TodoImportItem struct {
+	Todo        *model.Todo
+	TagNames    []string // 标签名，不存在的标签会被创建
+	ProjectName string   // 项目名，设置时使用同名的项目，不存在则创建
+	Parent      int      // 父任务在同一批次中的下标，-1 表示没有或已在 Todo.ParentID 中指定
+}
+
 // TodoRepository 待办事项仓储接口
 type TodoRepository interface {
 	Create(ctx context.Context, todo *model.Todo) error
 	GetByID(ctx context.Context, id uint) (*model.Todo, error)
+	GetByIDs(ctx context.Context, userID uint, ids []uint) ([]model.Todo, error)
 	GetByUserID(ctx context.Context, userID uint, page, pageSize uint,
-		status, priority *uint8, keyword string) ([]model.Todo, int64, error)
+		filter *TodoFilter, sort *TodoSort) ([]model.Todo, int64, error)
 	Update(ctx context.Context, todo *model.Todo) error
 	Delete(ctx context.Context, id uint) error
-	BatchUpdateStatus(ctx context.Context, userID uint, todoIDs []uint, status model.TodoStatus) error
-	GetStatistics(ctx context.Context, userID uint) (*model.TodoStatistics, error)
+	GetStatistics(ctx context.Context, userID uint, projectID *uint) (*model.TodoStatistics, error)
+	GetAnalytics(ctx context.Context, userID uint, projectID *uint, from, to, now time.Time) (*model.TodoAnalytics, error)
+	GetSubtasks(ctx context.Context, parentID uint) ([]model.Todo, error)
+	CountUnfinishedSubtasks(ctx context.Context, parentID uint) (int64, error)
+	UpdateSubtaskPositions(ctx context.Context, parentID uint, subtaskIDs []uint) error
+	GetBoardColumn(ctx context.Context, userID uint, status model.TodoStatus, projectID *uint, limit int) ([]model.Todo, int64, error)
+	GetBoardNeighbor(ctx context.Context, userID uint, status model.TodoStatus, from *model.Todo, next bool, excludeID uint) (*model.Todo, error)
+	RebalanceRanks(ctx context.Context, userID uint) error
+	GetForIndex(ctx context.Context, afterID uint, limit int) ([]model.Todo, error)
+	GetAllByUserID(ctx context.Context, userID uint) ([]model.Todo, error)
+	GetByProjectID(ctx context.Context, projectID uint) ([]model.Todo, error)
+	MoveProjectToInbox(ctx context.Context, projectID uint) error
+	MoveSubtasksToProject(ctx context.Context, parentID uint, projectID *uint) error
+	GetByICalUIDs(ctx context.Context, userID uint, uids []string) ([]model.Todo, error)
+	GetBatchByUserID(ctx context.Context, userID, afterID uint, limit int) ([]model.Todo, error)
+	Import(ctx context.Context, userID uint, items []TodoImportItem) error
+
+	// 回收站
+	GetTrashed(ctx context.Context, userID uint, page, pageSize uint) ([]model.Todo, int64, error)
+	GetTrashedByID(ctx context.Context, id uint) (*model.Todo, error)
+	GetExpiredTrashIDs(ctx context.Context, deletedBefore time.Time, limit int) ([]uint, error)
+	GetSubtaskIDs(ctx context.Context, parentIDs []uint) ([]uint, error)
+	Restore(ctx context.Context, todo *model.Todo, moveToInbox bool) error
+	Purge(ctx context.Context, ids []uint) error
 }
 
 type todoRepository struct {
@@ -30,13 +78,49 @@ func NewTodoRepository(db *gorm.DB) TodoRepository {
 
 // Create 创建待办事项
 func (r *todoRepository) Create(ctx context.Context, todo *model.Todo) error {
-	return r.db.WithContext(ctx).Create(todo).Error
+	db := conn(ctx, r.db)
+	if todo.ParentID == nil && todo.BoardRank == "" {
+		first, err := firstRank(db, todo.UserID)
+		if err != nil {
+			return err
+		}
+		if todo.BoardRank, err = rank.Between("", first); err != nil {
+			return err
+		}
+	}
+	todo.Version = 1
+	return db.Create(todo).Error
+}
+
+// boardOrder 看板中的顺序，排序键相同(如并发创建)时较新的在前
+const boardOrder = "board_rank ASC, id DESC"
+
+// firstRank 用户看板中最靠前的排序键，新建的顶层任务排在它之前；没有有效的排序键时为空
+func firstRank(db *gorm.DB, userID uint) (string, error) {
+	var first string
+	err := db.Model(&model.Todo{}).
+		Where("user_id = ? AND parent_id IS NULL AND board_rank <> ''", userID).
+		Select("COALESCE(MIN(board_rank), '')").Scan(&first).Error
+	if err != nil {
+		return "", err
+	}
+	if !rank.Valid(first) {
+		return "", nil
+	}
+	return first, nil
+}
+
+// preloadSubtasks 按排序位置预加载子任务
+func preloadSubtasks(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
 }
 
 // GetByID 根据ID获取待办事项
 func (r *todoRepository) GetByID(ctx context.Context, id uint) (*model.Todo, error) {
 	var todo model.Todo
-	err := r.db.WithContext(ctx).Preload("User").First(&todo, id).Error
+	err := conn(ctx, r.db).Preload("User").Preload("Tags").
+		Preload("Subtasks", preloadSubtasks).Preload("Subtasks.Tags").
+		First(&todo, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
@@ -46,24 +130,38 @@ func (r *todoRepository) GetByID(ctx context.Context, id uint) (*model.Todo, err
 	return &todo, nil
 }
 
-// GetByUserID 根据用户ID获取待办事项列表
+// GetByUserID 根据用户ID获取待办事项列表，sort 中带有游标时按游标位置查询，忽略页码
 func (r *todoRepository) GetByUserID(ctx context.Context, userID uint,
-	page, pageSize uint, status, priority *uint8,
-	keyword string) ([]model.Todo, int64, error) {
+	page, pageSize uint, filter *TodoFilter, sort *TodoSort) ([]model.Todo, int64, error) {
 	var todos []model.Todo
 	var totalCount int64
 
-	query := r.db.WithContext(ctx).Model(&model.Todo{}).Where("user_id = ?", userID)
+	// 子任务随父任务一起返回，列表只包含顶层任务
+	query := conn(ctx, r.db).Model(&model.Todo{}).
+		Where("user_id = ? AND parent_id IS NULL", userID)
 	// 条件筛选
-	if status != nil {
-		query = query.Where("status = ?", *status)
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.Priority != nil {
+		query = query.Where("priority = ?", *filter.Priority)
 	}
-	if priority != nil {
-		query = query.Where("priority = ?", *priority)
+	if filter.TagID != nil {
+		query = query.Where("id IN (?)", r.db.Model(&model.TodoTag{}).
+			Select("todo_id").Where("tag_id = ?", *filter.TagID))
 	}
-	if keyword != "" {
-		query = query.Where("title LIKE ? OR description LIKE ?",
-			"%"+keyword+"%", "%"+keyword+"%")
+	if filter.ProjectID != nil {
+		query = scopeProject(query, *filter.ProjectID)
+	}
+	if filter.IDs != nil {
+		if len(filter.IDs) == 0 {
+			return []model.Todo{}, 0, nil
+		}
+		query = query.Where("id IN (" + joinIDs(filter.IDs) + ")")
+	}
+	if filter.Expr != nil {
+		condition, args := r.filterCondition(filter.Expr, time.Now())
+		query = query.Where(condition, args...)
 	}
 
 	//获取总数
@@ -71,48 +169,534 @@ func (r *todoRepository) GetByUserID(ctx context.Context, userID uint,
 		return nil, 0, err
 	}
 	// 分页查询
-	offset := int((page - 1) * pageSize)
-	err := query.Order("CASE WHEN due_date IS NOT NULL THEN due_date ELSE '9999-12-31' END ASC").
-		Order("priority DESC").
-		Order("created_at DESC").
-		Offset(offset).Limit(int(pageSize)).Find(&todos).Error
+	query, err := applySort(query, sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	if sort.After == nil && sort.Before == nil {
+		query = query.Offset(int((page - 1) * pageSize))
+	}
+	err = query.Limit(int(pageSize)).Preload("Tags").
+		Preload("Subtasks", preloadSubtasks).Preload("Subtasks.Tags").
+		Find(&todos).Error
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return todos, totalCount, err
+	//向前翻页时按相反顺序查询，恢复为正常顺序
+	if sort.Before != nil {
+		slices.Reverse(todos)
+	}
+	return todos, totalCount, nil
+}
+
+// joinIDs 将ID列表拼接为 SQL 中的列表。搜索结果的数量没有上限，直接写入 SQL 而不是逐个绑定参数，
+// 避免超出预处理语句的参数个数上限；ID 均为整数，不存在注入问题
+func joinIDs(ids []uint) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, ",")
 }
 
-// Update 更新待办事项
+// Update 更新待办事项，不级联保存关联数据。读取后已被其他请求修改时返回 ErrVersionConflict
 func (r *todoRepository) Update(ctx context.Context, todo *model.Todo) error {
-	return r.db.WithContext(ctx).Save(todo).Error
+	return saveVersioned(conn(ctx, r.db).Omit(clause.Associations), todo, &todo.Version)
 }
 
-// Delete 删除待办事项
+// Delete 删除待办事项及其子任务
 func (r *todoRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&model.Todo{}, id).Error
+	return conn(ctx, r.db).
+		Where("id = ? OR parent_id = ?", id, id).
+		Delete(&model.Todo{}).Error
 }
 
-// BatchUpdateStatus 批量更新状态
-func (r *todoRepository) BatchUpdateStatus(ctx context.Context, userID uint,
-	todoIDs []uint, status model.TodoStatus) error {
-	return r.db.WithContext(ctx).Model(&model.Todo{}).
-		Where("user_id = ? AND id IN (?)", userID, todoIDs).
-		Update("status", status).Error
+// GetByIDs 批量获取用户的待办事项及其标签，不属于该用户的ID会被忽略
+func (r *todoRepository) GetByIDs(ctx context.Context, userID uint, ids []uint) ([]model.Todo, error) {
+	var todos []model.Todo
+	if len(ids) == 0 {
+		return todos, nil
+	}
+	err := conn(ctx, r.db).Preload("Tags").Where("user_id = ? AND id IN ?", userID, ids).Find(&todos).Error
+	return todos, err
+}
+
+// scopeProject 按项目筛选，0 表示收件箱
+func scopeProject(query *gorm.DB, projectID uint) *gorm.DB {
+	if projectID == 0 {
+		return query.Where("project_id IS NULL")
+	}
+	return query.Where("project_id = ?", projectID)
 }
 
-// GetStatistics 获取统计信息
+// GetStatistics 获取统计信息，只统计顶层任务，避免子任务重复计数；
+// projectID 不为空时只统计该项目
 func (r *todoRepository) GetStatistics(ctx context.Context,
-	userID uint) (*model.TodoStatistics, error) {
+	userID uint, projectID *uint) (*model.TodoStatistics, error) {
 	var sta model.TodoStatistics
 
-	err := r.db.WithContext(ctx).Model(&model.Todo{}).
+	query := conn(ctx, r.db).Model(&model.Todo{}).
 		Select("COUNT(*) as total_count, "+
 			"SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END) as pending_count, "+
 			"SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END) as progress_count, "+
 			"SUM(CASE WHEN status = 2 THEN 1 ELSE 0 END) as completed_count").
-		Where("user_id = ?", userID).
-		Scan(&sta).Error
+		Where("user_id = ? AND parent_id IS NULL", userID)
+	if projectID != nil {
+		query = scopeProject(query, *projectID)
+	}
+	err := query.Scan(&sta).Error
 
 	if err != nil {
 		return nil, err
 	}
 	return &sta, nil
 }
+
+// GetAnalytics 获取统计分析数据，与 GetStatistics 一样只统计顶层任务；
+// 时间窗口为 [from, to)，now 用于判断是否过期
+func (r *todoRepository) GetAnalytics(ctx context.Context, userID uint, projectID *uint,
+	from, to, now time.Time) (*model.TodoAnalytics, error) {
+	scope := func() *gorm.DB {
+		query := conn(ctx, r.db).Model(&model.Todo{}).
+			Where("user_id = ? AND parent_id IS NULL", userID)
+		if projectID != nil {
+			query = scopeProject(query, *projectID)
+		}
+		return query
+	}
+	var analytics model.TodoAnalytics
+
+	//按状态、优先级及是否过期计数
+	err := scope().Select("COUNT(*) as total_count, "+
+		"COALESCE(SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END), 0) as pending_count, "+
+		"COALESCE(SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END), 0) as progress_count, "+
+		"COALESCE(SUM(CASE WHEN status = 2 THEN 1 ELSE 0 END), 0) as completed_count, "+
+		"COALESCE(SUM(CASE WHEN priority = 1 THEN 1 ELSE 0 END), 0) as low_count, "+
+		"COALESCE(SUM(CASE WHEN priority = 2 THEN 1 ELSE 0 END), 0) as medium_count, "+
+		"COALESCE(SUM(CASE WHEN priority = 3 THEN 1 ELSE 0 END), 0) as high_count, "+
+		"COALESCE(SUM(CASE WHEN priority = 4 THEN 1 ELSE 0 END), 0) as urgent_count, "+
+		"COALESCE(SUM(CASE WHEN due_date < ? AND status <> 2 THEN 1 ELSE 0 END), 0) as overdue_count", now).
+		Scan(&analytics).Error
+	if err != nil {
+		return nil, err
+	}
+
+	//窗口内创建的任务及其中已完成的数量
+	var created struct {
+		Total     uint
+		Completed uint
+	}
+	err = scope().Select("COUNT(*) as total, "+
+		"COALESCE(SUM(CASE WHEN status = 2 THEN 1 ELSE 0 END), 0) as completed").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Scan(&created).Error
+	if err != nil {
+		return nil, err
+	}
+	analytics.CreatedInWindow, analytics.CompletedOfCreated = created.Total, created.Completed
+
+	//窗口内完成的任务数及平均完成耗时
+	var completed struct {
+		Total      uint
+		AvgSeconds float64
+	}
+	err = scope().Select("COUNT(*) as total, "+
+		"COALESCE(AVG(TIMESTAMPDIFF(SECOND, created_at, completed_at)), 0) as avg_seconds").
+		Where("status = 2 AND completed_at >= ? AND completed_at < ?", from, to).
+		Scan(&completed).Error
+	if err != nil {
+		return nil, err
+	}
+	analytics.CompletedInWindow, analytics.AvgCompletionSeconds = completed.Total, completed.AvgSeconds
+
+	//按天统计创建与完成数量
+	var createdDaily, completedDaily []struct {
+		Day   time.Time
+		Count uint
+	}
+	err = scope().Select("DATE(created_at) as day, COUNT(*) as count").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("day").Scan(&createdDaily).Error
+	if err != nil {
+		return nil, err
+	}
+	err = scope().Select("DATE(completed_at) as day, COUNT(*) as count").
+		Where("status = 2 AND completed_at >= ? AND completed_at < ?", from, to).
+		Group("day").Scan(&completedDaily).Error
+	if err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]*model.DailyTodoCount)
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		analytics.Daily = append(analytics.Daily, model.DailyTodoCount{Day: day})
+	}
+	for i := range analytics.Daily {
+		days[analytics.Daily[i].Day.Format(time.DateOnly)] = &analytics.Daily[i]
+	}
+	for _, row := range createdDaily {
+		if day, ok := days[row.Day.Format(time.DateOnly)]; ok {
+			day.Created = row.Count
+		}
+	}
+	for _, row := range completedDaily {
+		if day, ok := days[row.Day.Format(time.DateOnly)]; ok {
+			day.Completed = row.Count
+		}
+	}
+	return &analytics, nil
+}
+
+// GetSubtasks 获取子任务列表
+func (r *todoRepository) GetSubtasks(ctx context.Context, parentID uint) ([]model.Todo, error) {
+	var subtasks []model.Todo
+	err := conn(ctx, r.db).Where("parent_id = ?", parentID).
+		Scopes(preloadSubtasks).Preload("Tags").Find(&subtasks).Error
+	return subtasks, err
+}
+
+// CountUnfinishedSubtasks 统计未完成的子任务数量
+func (r *todoRepository) CountUnfinishedSubtasks(ctx context.Context, parentID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&model.Todo{}).
+		Where("parent_id = ? AND status <> ?", parentID, 2).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateSubtaskPositions 按给定顺序重排子任务，版本号加1，避免之前读取的子任务保存时写回旧位置
+func (r *todoRepository) UpdateSubtaskPositions(ctx context.Context, parentID uint, subtaskIDs []uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for i, id := range subtaskIDs {
+			err := tx.Model(&model.Todo{}).
+				Where("id = ? AND parent_id = ?", id, parentID).
+				Updates(map[string]any{"position": i, "version": gorm.Expr("version + 1")}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetForIndex 按ID顺序分批获取未删除的待办事项，只查询构建搜索索引所需的字段
+func (r *todoRepository) GetForIndex(ctx context.Context, afterID uint, limit int) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := conn(ctx, r.db).Select("id", "user_id", "title", "description").
+		Where("id > ?", afterID).Order("id ASC").Limit(limit).
+		Find(&todos).Error
+	return todos, err
+}
+
+// GetAllByUserID 获取用户全部未删除的待办事项及其标签，父任务在前，用于导出日历
+func (r *todoRepository) GetAllByUserID(ctx context.Context, userID uint) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := conn(ctx, r.db).Preload("Tags").
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&todos).Error
+	return todos, err
+}
+
+// GetByProjectID 获取项目内全部未删除的待办事项(含子任务)
+func (r *todoRepository) GetByProjectID(ctx context.Context, projectID uint) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := conn(ctx, r.db).Where("project_id = ?", projectID).Order("id ASC").Find(&todos).Error
+	return todos, err
+}
+
+// MoveProjectToInbox 将项目内的待办事项移入收件箱，同时递增版本号
+func (r *todoRepository) MoveProjectToInbox(ctx context.Context, projectID uint) error {
+	return conn(ctx, r.db).Model(&model.Todo{}).Where("project_id = ?", projectID).
+		Updates(map[string]any{"project_id": nil, "version": gorm.Expr("version + 1")}).Error
+}
+
+// MoveSubtasksToProject 将子任务(含回收站中的)移到父任务所在的项目，同时递增版本号
+func (r *todoRepository) MoveSubtasksToProject(ctx context.Context, parentID uint, projectID *uint) error {
+	return conn(ctx, r.db).Unscoped().Model(&model.Todo{}).Where("parent_id = ?", parentID).
+		Updates(map[string]any{"project_id": projectID, "version": gorm.Expr("version + 1")}).Error
+}
+
+// GetByICalUIDs 按日历UID获取用户已导入的待办事项
+func (r *todoRepository) GetByICalUIDs(ctx context.Context, userID uint, uids []string) ([]model.Todo, error) {
+	var todos []model.Todo
+	if len(uids) == 0 {
+		return todos, nil
+	}
+	err := conn(ctx, r.db).Where("user_id = ? AND ical_uid IN ?", userID, uids).Find(&todos).Error
+	return todos, err
+}
+
+// GetBatchByUserID 按ID顺序分批获取用户未删除的待办事项及其标签，用于流式导出
+func (r *todoRepository) GetBatchByUserID(ctx context.Context, userID, afterID uint, limit int) ([]model.Todo, error) {
+	var todos []model.Todo
+	err := conn(ctx, r.db).Preload("Tags").
+		Where("user_id = ? AND id > ?", userID, afterID).
+		Order("id ASC").Limit(limit).
+		Find(&todos).Error
+	return todos, err
+}
+
+// Import 在一个事务中创建缺少的标签、项目及全部待办事项，任一失败时全部回滚。
+// 父任务必须排在子任务之前，成功后每条待办事项的 Tags 会被填充
+func (r *todoRepository) Import(ctx context.Context, userID uint, items []TodoImportItem) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var names []string
+		for _, item := range items {
+			names = append(names, item.TagNames...)
+		}
+		tags := make(map[string]model.Tag)
+		if len(names) > 0 {
+			var existing []model.Tag
+			if err := tx.Where("user_id = ? AND name IN ?", userID, names).Find(&existing).Error; err != nil {
+				return err
+			}
+			for _, tag := range existing {
+				tags[strings.ToLower(tag.Name)] = tag
+			}
+		}
+		//标签名唯一索引不区分大小写
+		for _, name := range names {
+			if _, ok := tags[strings.ToLower(name)]; ok {
+				continue
+			}
+			tag := model.Tag{UserID: userID, Name: name}
+			if err := tx.Create(&tag).Error; err != nil {
+				return err
+			}
+			tags[strings.ToLower(name)] = tag
+		}
+
+		projects := make(map[string]uint)
+		for _, item := range items {
+			if item.ProjectName == "" {
+				continue
+			}
+			key := strings.ToLower(item.ProjectName)
+			if _, ok := projects[key]; !ok {
+				var existing []model.Project
+				err := tx.Where("user_id = ? AND name = ?", userID, item.ProjectName).
+					Order("id ASC").Limit(1).Find(&existing).Error
+				if err != nil {
+					return err
+				}
+				if len(existing) == 0 {
+					project := model.Project{UserID: userID, Name: item.ProjectName}
+					if err := tx.Create(&project).Error; err != nil {
+						return err
+					}
+					existing = append(existing, project)
+				}
+				projects[key] = existing[0].ID
+			}
+			projectID := projects[key]
+			item.Todo.ProjectID = &projectID
+		}
+
+		//新建的顶层任务按文件中的顺序排在看板最前面
+		var top []*model.Todo
+		for _, item := range items {
+			if item.Parent < 0 && item.Todo.BoardRank == "" {
+				top = append(top, item.Todo)
+			}
+		}
+		first, err := firstRank(tx, userID)
+		if err != nil {
+			return err
+		}
+		ranks, err := rank.NBetween("", first, len(top))
+		if err != nil {
+			return err
+		}
+		for i, todo := range top {
+			todo.BoardRank = ranks[i]
+		}
+
+		var links []model.TodoTag
+		for _, item := range items {
+			if item.Parent >= 0 {
+				item.Todo.ParentID = &items[item.Parent].Todo.ID
+			}
+			item.Todo.Version = 1
+			if err := tx.Omit(clause.Associations).Create(item.Todo).Error; err != nil {
+				return err
+			}
+			item.Todo.Tags = nil
+			seen := make(map[uint]bool)
+			for _, name := range item.TagNames {
+				tag := tags[strings.ToLower(name)]
+				if seen[tag.ID] {
+					continue
+				}
+				seen[tag.ID] = true
+				item.Todo.Tags = append(item.Todo.Tags, tag)
+				links = append(links, model.TodoTag{TodoID: item.Todo.ID, TagID: tag.ID})
+			}
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&links, 500).Error
+	})
+}
+
+// GetBoardColumn 获取看板中某一状态的顶层任务，projectID 为 0 表示收件箱，为空表示全部项目
+func (r *todoRepository) GetBoardColumn(ctx context.Context, userID uint, status model.TodoStatus,
+	projectID *uint, limit int) ([]model.Todo, int64, error) {
+	query := conn(ctx, r.db).Model(&model.Todo{}).
+		Where("user_id = ? AND parent_id IS NULL AND status = ?", userID, status)
+	if projectID != nil {
+		query = scopeProject(query, *projectID)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var todos []model.Todo
+	err := query.Order(boardOrder).Limit(limit).Preload("Tags").
+		Preload("Subtasks", preloadSubtasks).Preload("Subtasks.Tags").
+		Find(&todos).Error
+	return todos, total, err
+}
+
+// GetBoardNeighbor 获取看板某一列中 from 之后(next)或之前的一项，from 为空时取列中第一项；
+// 不包括 excludeID，没有时返回 nil
+func (r *todoRepository) GetBoardNeighbor(ctx context.Context, userID uint, status model.TodoStatus,
+	from *model.Todo, next bool, excludeID uint) (*model.Todo, error) {
+	query := conn(ctx, r.db).
+		Where("user_id = ? AND parent_id IS NULL AND status = ? AND id <> ?", userID, status, excludeID)
+	switch {
+	case from == nil:
+		query = query.Order(boardOrder)
+	case next:
+		query = query.Where("board_rank > ? OR (board_rank = ? AND id < ?)", from.BoardRank, from.BoardRank, from.ID).
+			Order(boardOrder)
+	default:
+		query = query.Where("board_rank < ? OR (board_rank = ? AND id > ?)", from.BoardRank, from.BoardRank, from.ID).
+			Order("board_rank DESC, id ASC")
+	}
+
+	var todos []model.Todo
+	if err := query.Limit(1).Find(&todos).Error; err != nil {
+		return nil, err
+	}
+	if len(todos) == 0 {
+		return nil, nil
+	}
+	return &todos[0], nil
+}
+
+// RebalanceRanks 按当前顺序为用户的全部顶层任务(含回收站)重新分配均匀的排序键，
+// 用于排序键过长、重复或缺失(升级前创建的任务)时；版本号加1，避免之前读取的任务保存时写回旧排序键
+func (r *todoRepository) RebalanceRanks(ctx context.Context, userID uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		err := tx.Unscoped().Model(&model.Todo{}).
+			Where("user_id = ? AND parent_id IS NULL", userID).
+			Order(boardOrder).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		ranks, err := rank.NBetween("", "", len(ids))
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			err := tx.Unscoped().Model(&model.Todo{}).Where("id = ?", id).
+				UpdateColumns(map[string]any{"board_rank": ranks[i], "version": gorm.Expr("version + 1")}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// trashedScope 回收站中可见的待办事项：顶层任务，或父任务未被删除的子任务
+func trashedScope(query *gorm.DB) *gorm.DB {
+	return query.Where("deleted_at IS NOT NULL").
+		Where("parent_id IS NULL OR parent_id IN (?)",
+			query.Session(&gorm.Session{NewDB: true}).Model(&model.Todo{}).Select("id"))
+}
+
+// GetTrashed 分页获取用户回收站中的待办事项，最近删除的在前
+func (r *todoRepository) GetTrashed(ctx context.Context, userID uint, page, pageSize uint) ([]model.Todo, int64, error) {
+	var todos []model.Todo
+	var totalCount int64
+
+	query := trashedScope(conn(ctx, r.db).Unscoped().Model(&model.Todo{}).Where("user_id = ?", userID))
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := int((page - 1) * pageSize)
+	err := query.Order("deleted_at DESC").
+		Offset(offset).Limit(int(pageSize)).
+		Preload("Tags").Find(&todos).Error
+	return todos, totalCount, err
+}
+
+// GetTrashedByID 获取回收站中的待办事项，未删除或不存在时返回 nil
+func (r *todoRepository) GetTrashedByID(ctx context.Context, id uint) (*model.Todo, error) {
+	var todo model.Todo
+	err := conn(ctx, r.db).Unscoped().Preload("Tags").
+		Where("deleted_at IS NOT NULL").
+		First(&todo, id).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// GetExpiredTrashIDs 获取删除时间早于 deletedBefore 的待办事项ID
+func (r *todoRepository) GetExpiredTrashIDs(ctx context.Context, deletedBefore time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := conn(ctx, r.db).Unscoped().Model(&model.Todo{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Order("deleted_at ASC").Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetSubtaskIDs 获取子任务ID，包含已删除的子任务
+func (r *todoRepository) GetSubtaskIDs(ctx context.Context, parentIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(parentIDs) == 0 {
+		return ids, nil
+	}
+	err := conn(ctx, r.db).Unscoped().Model(&model.Todo{}).
+		Where("parent_id IN ?", parentIDs).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restore 恢复待办事项及与其一同删除的子任务，moveToInbox 为 true 时移入收件箱，同时递增版本号
+func (r *todoRepository) Restore(ctx context.Context, todo *model.Todo, moveToInbox bool) error {
+	updates := map[string]any{"deleted_at": nil, "version": gorm.Expr("version + 1")}
+	if moveToInbox {
+		updates["project_id"] = nil
+	}
+	return conn(ctx, r.db).Unscoped().Model(&model.Todo{}).
+		Where("id = ? OR (parent_id = ? AND deleted_at = ?)", todo.ID, todo.ID, todo.DeletedAt.Time).
+		Updates(updates).Error
+}
+
+// Purge 彻底删除待办事项，关联的标签、提醒、评论等由外键级联删除，
+// 共享记录没有外键约束，需要一并删除
+func (r *todoRepository) Purge(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("resource_type = ? AND resource_id IN ?", model.ShareResourceTodo, ids).
+			Delete(&model.Share{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&model.Todo{}).Error
+	})
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"TODO_API/internal/domain/model"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxSortFields 最多允许的排序字段数
+const maxSortFields = 4
+
+// noDueDate 未设置截止时间的任务按该时间参与排序，升序时排在最后；
+// 与数据库连接一样使用本地时区，保证与SQL中的常量一致
+var noDueDate = time.Date(9999, 12, 31, 23, 59, 59, 0, time.Local)
+
+// sortColumn 可排序字段对应的SQL表达式及从记录中取排序键的方法
+type sortColumn struct {
+	expr string
+	key  func(todo *model.Todo) string
+	// parse 将游标中的排序键还原为查询参数
+	parse func(key string) (any, error)
+}
+
+// timeKey 时间排序键统一使用UTC，为空时使用 fallback
+func timeKey(t *time.Time, fallback time.Time) string {
+	if t == nil {
+		t = &fallback
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTimeKey(key string) (any, error) {
+	return time.Parse(time.RFC3339Nano, key)
+}
+
+func parseIntKey(key string) (any, error) {
+	return strconv.ParseInt(key, 10, 64)
+}
+
+// sortColumns 支持的排序字段
+var sortColumns = map[string]sortColumn{
+	"due_date": {
+		expr:  "COALESCE(due_date, CAST('9999-12-31 23:59:59' AS DATETIME))",
+		key:   func(todo *model.Todo) string { return timeKey(todo.DueDate, noDueDate) },
+		parse: parseTimeKey,
+	},
+	"priority": {
+		expr:  "priority",
+		key:   func(todo *model.Todo) string { return strconv.Itoa(int(todo.Priority)) },
+		parse: parseIntKey,
+	},
+	"status": {
+		expr:  "status",
+		key:   func(todo *model.Todo) string { return strconv.Itoa(int(todo.Status)) },
+		parse: parseIntKey,
+	},
+	"created_at": {
+		expr:  "created_at",
+		key:   func(todo *model.Todo) string { return timeKey(todo.CreatedAt, time.Time{}) },
+		parse: parseTimeKey,
+	},
+	"updated_at": {
+		expr:  "updated_at",
+		key:   func(todo *model.Todo) string { return timeKey(todo.UpdatedAt, time.Time{}) },
+		parse: parseTimeKey,
+	},
+	"title": {
+		expr:  "title",
+		key:   func(todo *model.Todo) string { return todo.Title },
+		parse: func(key string) (any, error) { return key, nil },
+	},
+	// id 作为最后的排序字段保证顺序唯一，不对外开放
+	"id": {
+		expr:  "id",
+		key:   func(todo *model.Todo) string { return strconv.FormatUint(uint64(todo.ID), 10) },
+		parse: func(key string) (any, error) { return strconv.ParseUint(key, 10, 64) },
+	},
+}
+
+// SortField 排序字段
+type SortField struct {
+	Name string
+	Desc bool
+}
+
+// DefaultTodoSort 默认排序：截止时间升序(未设置的在后)、优先级降序、创建时间降序
+var DefaultTodoSort = []SortField{
+	{Name: "due_date"},
+	{Name: "priority", Desc: true},
+	{Name: "created_at", Desc: true},
+}
+
+// TodoSort 列表排序方式及游标位置，After/Before 为游标中的排序键，
+// 设置后从该位置之后/之前开始查询，忽略页码
+type TodoSort struct {
+	Fields []SortField
+	After  []string
+	Before []string
+}
+
+// ParseTodoSort 解析排序参数，如 -priority,due_date，字段前加 - 表示降序，为空时使用默认排序
+func ParseTodoSort(spec string) ([]SortField, error) {
+	if strings.TrimSpace(spec) == "" {
+		return DefaultTodoSort, nil
+	}
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Name: strings.TrimLeft(part, "+-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := sortColumns[field.Name]; !ok || field.Name == "id" {
+			return nil, fmt.Errorf("不支持的排序字段: %s", part)
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("排序字段重复: %s", field.Name)
+		}
+		seen[field.Name] = true
+		fields = append(fields, field)
+	}
+	if len(fields) > maxSortFields {
+		return nil, fmt.Errorf("排序字段不能超过 %d 个", maxSortFields)
+	}
+	return fields, nil
+}
+
+// FormatTodoSort 将排序字段格式化为规范形式，用于校验游标与排序方式是否一致
+func FormatTodoSort(fields []SortField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Name
+		if field.Desc {
+			parts[i] = "-" + field.Name
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// withID 在排序字段末尾追加 id，方向与最后一个字段相同
+func withID(fields []SortField) []SortField {
+	desc := len(fields) > 0 && fields[len(fields)-1].Desc
+	return append(append([]SortField{}, fields...), SortField{Name: "id", Desc: desc})
+}
+
+// TodoSortKey 取记录在该排序方式下的排序键，用于生成游标
+func TodoSortKey(todo *model.Todo, fields []SortField) []string {
+	fields = withID(fields)
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = sortColumns[field.Name].key(todo)
+	}
+	return keys
+}
+
+// applySort 添加排序及游标条件；向前翻页时按相反顺序查询，调用方需将结果反转
+func applySort(query *gorm.DB, sort *TodoSort) (*gorm.DB, error) {
+	fields := withID(sort.Fields)
+	keys, backward := sort.After, false
+	if sort.Before != nil {
+		keys, backward = sort.Before, true
+	}
+
+	if keys != nil {
+		condition, args, err := keysetCondition(fields, keys, backward)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(condition, args...)
+	}
+
+	for _, field := range fields {
+		desc := field.Desc != backward
+		direction := " ASC"
+		if desc {
+			direction = " DESC"
+		}
+		query = query.Order(sortColumns[field.Name].expr + direction)
+	}
+	return query, nil
+}
+
+// keysetCondition 生成游标条件，如 (a > ?) OR (a = ? AND b < ?) OR (a = ? AND b = ? AND id < ?)
+func keysetCondition(fields []SortField, keys []string, backward bool) (string, []any, error) {
+	if len(keys) != len(fields) {
+		return "", nil, errors.New("游标无效")
+	}
+	values := make([]any, len(keys))
+	for i, key := range keys {
+		value, err := sortColumns[fields[i].Name].parse(key)
+		if err != nil {
+			return "", nil, errors.New("游标无效")
+		}
+		values[i] = value
+	}
+
+	var clauses []string
+	var args []any
+	for i, field := range fields {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, sortColumns[fields[j].Name].expr+" = ?")
+			args = append(args, values[j])
+		}
+		op := " > ?"
+		if field.Desc != backward {
+			op = " < ?"
+		}
+		parts = append(parts, sortColumns[field.Name].expr+op)
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return strings.Join(clauses, " OR "), args, nil
+}
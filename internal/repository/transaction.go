@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey 上下文中保存事务的键
+type txKey struct{}
+
+// Transactor 在一个数据库事务中执行多个仓储操作：fn 收到的上下文携带事务，
+// 使用该上下文调用的仓储方法都在同一事务中执行。已在事务中时开启保存点，fn 出错只回滚到保存点
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor 创建事务管理实例
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+// WithinTransaction 在事务中执行 fn，fn 返回错误时回滚
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn 获取执行查询的连接：上下文中有事务时使用该事务，否则使用默认连接
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -12,6 +12,8 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uint) (*model.User, error)
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	GetByCalendarToken(ctx context.Context, tokenHash string) (*model.User, error)
+	UpdateCalendarToken(ctx context.Context, userID uint, tokenHash *string) error
 	Update(ctx context.Context, user *model.User) error
 }
 
@@ -26,13 +28,14 @@ func NewUserRepository(db *gorm.DB) *userRepo {
 
 // 创建用户
 func (r *userRepo) Create(ctx context.Context, user *model.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	user.Version = 1
+	return conn(ctx, r.db).Create(user).Error
 }
 
 // 通过ID获取用户
 func (r *userRepo) GetByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx).First(&user, id).Error
+	err := conn(ctx, r.db).First(&user, id).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -45,7 +48,7 @@ func (r *userRepo) GetByID(ctx context.Context, id uint) (*model.User, error) {
 // 通过username获取用户
 func (r *userRepo) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx).First(&user, "username = ?", username).Error
+	err := conn(ctx, r.db).First(&user, "username = ?", username).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -59,7 +62,7 @@ func (r *userRepo) GetByUsername(ctx context.Context, username string) (*model.U
 // 通过Email获取用户
 func (r *userRepo) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error
+	err := conn(ctx, r.db).First(&user, "email = ?", email).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -69,7 +72,26 @@ func (r *userRepo) GetByEmail(ctx context.Context, email string) (*model.User, e
 	return &user, nil
 }
 
-// 更新用户
+// 通过日历订阅令牌哈希获取用户
+func (r *userRepo) GetByCalendarToken(ctx context.Context, tokenHash string) (*model.User, error) {
+	var user model.User
+	err := conn(ctx, r.db).First(&user, "calendar_token_hash = ?", tokenHash).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// 更新日历订阅令牌哈希，为空表示停用订阅；版本号加1，避免之前读取的用户保存时写回旧令牌
+func (r *userRepo) UpdateCalendarToken(ctx context.Context, userID uint, tokenHash *string) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", userID).
+		Updates(map[string]any{"calendar_token_hash": tokenHash, "version": gorm.Expr("version + 1")}).Error
+}
+
+// 更新用户，读取后已被其他请求修改时返回 ErrVersionConflict
 func (r *userRepo) Update(ctx context.Context, user *model.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return saveVersioned(conn(ctx, r.db), user, &user.Version)
 }
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 保存时数据库中的版本号与读取时不一致，说明数据已被其他请求修改
+var ErrVersionConflict = errors.New("数据已被修改")
+
+// saveVersioned 以乐观锁保存整条记录：只有数据库中的版本号仍为 *version 时才写入，
+// 写入成功后版本号加1，失败时保持不变
+func saveVersioned(db *gorm.DB, value any, version *uint) error {
+	current := *version
+	*version = current + 1
+	result := db.Model(value).Where("version = ?", current).Select("*").Updates(value)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = current
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// snippetRunes 片段最大字符数
+	snippetRunes = 80
+	// snippetContext 片段中第一个命中词之前保留的字符数
+	snippetContext = 20
+)
+
+// span 原文中需要高亮的字节区间
+type span struct {
+	start, end int
+}
+
+// matchWord 判断分词结果是否命中查询词
+func matchWord(text, word string, prefix bool) bool {
+	if prefix {
+		return strings.HasPrefix(text, word)
+	}
+	return text == word
+}
+
+// matchSpans 找出原文中命中查询词项的区间，短语从第一个词到最后一个词整体高亮
+func matchSpans(tokens []token, query *Query) []span {
+	marked := make([]bool, len(tokens))
+	for _, term := range query.Terms {
+		n := len(term.Words)
+		for i := 0; i+n <= len(tokens); i++ {
+			matched := true
+			for j, word := range term.Words {
+				if !matchWord(tokens[i+j].text, word, term.Prefix) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				for j := i; j < i+n; j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	var spans []span
+	for i, token := range tokens {
+		if !marked[i] {
+			continue
+		}
+		//相邻的命中词合并为一个区间，中间的分隔符一并高亮
+		if len(spans) > 0 && i > 0 && marked[i-1] {
+			spans[len(spans)-1].end = token.end
+			continue
+		}
+		spans = append(spans, span{start: token.start, end: token.end})
+	}
+	return spans
+}
+
+// Highlight 生成带高亮的片段：以第一个命中词为中心截取，命中词用 <em></em> 包裹，
+// 其余内容做 HTML 转义；没有命中时返回开头部分
+func Highlight(text string, query *Query) string {
+	if text == "" {
+		return ""
+	}
+	spans := matchSpans(tokenize(text), query)
+
+	from := 0
+	if len(spans) > 0 {
+		from = spans[0].start
+		for i := 0; i < snippetContext && from > 0; i++ {
+			_, size := utf8.DecodeLastRuneInString(text[:from])
+			from -= size
+		}
+	}
+	to := from
+	for i := 0; i < snippetRunes && to < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+	pos := from
+	for _, s := range spans {
+		if s.end <= pos {
+			continue
+		}
+		if s.start >= to {
+			break
+		}
+		start, end := max(s.start, pos), min(s.end, to)
+		b.WriteString(html.EscapeString(text[pos:start]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[start:end]))
+		b.WriteString("</em>")
+		pos = end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("...")
+	}
+	return b.String()
+}
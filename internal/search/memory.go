@@ -0,0 +1,263 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BM25 参数及字段权重，标题命中的权重高于描述
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// 被索引的字段
+const (
+	fieldTitle = iota
+	fieldDescription
+	fieldCount
+)
+
+var fieldBoost = [fieldCount]float64{2.0, 1.0}
+
+// posting 某个词在一篇文档各字段中出现的位置
+type posting [fieldCount][]int
+
+// indexedDoc 已索引文档的元信息，词表用于删除时清理倒排表
+type indexedDoc struct {
+	userID  uint
+	lengths [fieldCount]int
+	terms   []string
+}
+
+// MemoryIndex 进程内倒排索引，使用 BM25 排序，依靠位置信息支持短语查询
+// 索引只存在于内存中，启动时需要全量重建
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[uint]*indexedDoc
+	postings map[string]map[uint]*posting
+	totalLen [fieldCount]int
+}
+
+// NewMemoryIndex 创建进程内倒排索引
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[uint]*indexedDoc),
+		postings: make(map[string]map[uint]*posting),
+	}
+}
+
+// Index 写入或更新文档
+func (m *MemoryIndex) Index(ctx context.Context, doc Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(doc.ID)
+	indexed := &indexedDoc{userID: doc.UserID}
+	for field, text := range [fieldCount]string{doc.Title, doc.Description} {
+		tokens := tokenize(text)
+		indexed.lengths[field] = len(tokens)
+		m.totalLen[field] += len(tokens)
+		for pos, token := range tokens {
+			docs, ok := m.postings[token.text]
+			if !ok {
+				docs = make(map[uint]*posting)
+				m.postings[token.text] = docs
+			}
+			p, ok := docs[doc.ID]
+			if !ok {
+				p = &posting{}
+				docs[doc.ID] = p
+				indexed.terms = append(indexed.terms, token.text)
+			}
+			p[field] = append(p[field], pos)
+		}
+	}
+	m.docs[doc.ID] = indexed
+	return nil
+}
+
+// Remove 从索引中移除文档
+func (m *MemoryIndex) Remove(ctx context.Context, ids ...uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		m.remove(id)
+	}
+	return nil
+}
+
+// remove 移除文档，调用方需持有写锁
+func (m *MemoryIndex) remove(id uint) {
+	doc, ok := m.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range doc.terms {
+		delete(m.postings[term], id)
+		if len(m.postings[term]) == 0 {
+			delete(m.postings, term)
+		}
+	}
+	for field := range doc.lengths {
+		m.totalLen[field] -= doc.lengths[field]
+	}
+	delete(m.docs, id)
+}
+
+// Search 在用户的文档中搜索，所有词项都必须命中
+func (m *MemoryIndex) Search(ctx context.Context, userID uint, query *Query, offset, limit int) ([]Hit, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := m.score(userID, query)
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+
+	total := len(hits)
+	if offset >= total {
+		return []Hit{}, total, nil
+	}
+	return hits[offset:min(offset+limit, total)], total, nil
+}
+
+// Match 返回全部命中的ID
+func (m *MemoryIndex) Match(ctx context.Context, userID uint, query *Query) ([]uint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := m.score(userID, query)
+	ids := make([]uint, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// score 计算命中全部词项的文档得分
+func (m *MemoryIndex) score(userID uint, query *Query) map[uint]float64 {
+	var scores map[uint]float64
+	for _, term := range query.Terms {
+		termScores := m.scoreTerm(userID, term)
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for id, score := range scores {
+			if termScore, ok := termScores[id]; ok {
+				scores[id] = score + termScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	return scores
+}
+
+// scoreTerm 计算单个词项在用户各文档中的得分，未命中的文档不在结果中
+func (m *MemoryIndex) scoreTerm(userID uint, term Term) map[uint]float64 {
+	var tf map[uint][fieldCount]int
+	switch {
+	case term.IsPhrase():
+		tf = m.phraseFrequencies(userID, term.Words)
+	case term.Prefix:
+		tf = make(map[uint][fieldCount]int)
+		for word, docs := range m.postings {
+			if strings.HasPrefix(word, term.Words[0]) {
+				m.addFrequencies(tf, userID, docs)
+			}
+		}
+	default:
+		tf = make(map[uint][fieldCount]int)
+		m.addFrequencies(tf, userID, m.postings[term.Words[0]])
+	}
+
+	//文档频率按全部文档计算，避免用户文档较少时 IDF 失真
+	n := float64(len(m.docs))
+	df := float64(len(tf))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	scores := make(map[uint]float64, len(tf))
+	for id, freqs := range tf {
+		doc := m.docs[id]
+		var score float64
+		for field, freq := range freqs {
+			if freq == 0 {
+				continue
+			}
+			avgLen := float64(m.totalLen[field]) / n
+			norm := 1 - bm25B
+			if avgLen > 0 {
+				norm += bm25B * float64(doc.lengths[field]) / avgLen
+			}
+			f := float64(freq)
+			score += fieldBoost[field] * idf * f * (bm25K1 + 1) / (f + bm25K1*norm)
+		}
+		scores[id] = score
+	}
+	return scores
+}
+
+// addFrequencies 累加用户文档中各字段的词频
+func (m *MemoryIndex) addFrequencies(tf map[uint][fieldCount]int, userID uint, docs map[uint]*posting) {
+	for id, p := range docs {
+		if m.docs[id].userID != userID {
+			continue
+		}
+		freqs := tf[id]
+		for field := range p {
+			freqs[field] += len(p[field])
+		}
+		tf[id] = freqs
+	}
+}
+
+// phraseFrequencies 统计短语在用户文档中各字段出现的次数，要求各词位置连续
+func (m *MemoryIndex) phraseFrequencies(userID uint, words []string) map[uint][fieldCount]int {
+	tf := make(map[uint][fieldCount]int)
+	for id, first := range m.postings[words[0]] {
+		if m.docs[id].userID != userID {
+			continue
+		}
+		var freqs [fieldCount]int
+		for field := range first {
+			for _, pos := range first[field] {
+				if m.phraseAt(id, field, pos, words[1:]) {
+					freqs[field]++
+				}
+			}
+		}
+		if freqs != ([fieldCount]int{}) {
+			tf[id] = freqs
+		}
+	}
+	return tf
+}
+
+// phraseAt 判断从 pos 之后的位置是否依次为剩余的词
+func (m *MemoryIndex) phraseAt(id uint, field, pos int, rest []string) bool {
+	for i, word := range rest {
+		p, ok := m.postings[word][id]
+		if !ok {
+			return false
+		}
+		positions := p[field]
+		j := sort.SearchInts(positions, pos+i+1)
+		if j == len(positions) || positions[j] != pos+i+1 {
+			return false
+		}
+	}
+	return true
+}
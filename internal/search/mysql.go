@@ -0,0 +1,106 @@
+package search
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// MySQLSearcher 基于 MySQL FULLTEXT 索引(ngram 分词)的搜索实现
+// 索引由数据库随写入自动维护，Index/Remove 无需处理
+type MySQLSearcher struct {
+	db *gorm.DB
+}
+
+// NewMySQLSearcher 创建 MySQL 全文搜索实例，依赖 todos 表上的 ft_title 与 ft_content 索引
+func NewMySQLSearcher(db *gorm.DB) *MySQLSearcher {
+	return &MySQLSearcher{db: db}
+}
+
+// Index 由 FULLTEXT 索引自动维护
+func (m *MySQLSearcher) Index(ctx context.Context, doc Document) error {
+	return nil
+}
+
+// Remove 由 FULLTEXT 索引自动维护，软删除的记录在查询时排除
+func (m *MySQLSearcher) Remove(ctx context.Context, ids ...uint) error {
+	return nil
+}
+
+// Search 使用 BOOLEAN MODE 查询，标题匹配得分加倍
+func (m *MySQLSearcher) Search(ctx context.Context, userID uint, query *Query, offset, limit int) ([]Hit, int, error) {
+	against := booleanQuery(query)
+	base := m.db.WithContext(ctx).Model(&model.Todo{}).
+		Where("user_id = ?", userID).
+		Where("MATCH(title, description) AGAINST (? IN BOOLEAN MODE)", against)
+
+	var total int64
+	if err := base.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []struct {
+		ID    uint
+		Score float64
+	}
+	err := base.Select("id, MATCH(title) AGAINST (? IN BOOLEAN MODE) * 2 + "+
+		"MATCH(title, description) AGAINST (? IN BOOLEAN MODE) AS score", against, against).
+		Order("score DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	hits := make([]Hit, len(rows))
+	for i, row := range rows {
+		hits[i] = Hit{ID: row.ID, Score: row.Score}
+	}
+	return hits, int(total), nil
+}
+
+// Match 返回全部命中的ID
+func (m *MySQLSearcher) Match(ctx context.Context, userID uint, query *Query) ([]uint, error) {
+	ids := []uint{}
+	err := m.db.WithContext(ctx).Model(&model.Todo{}).
+		Where("user_id = ?", userID).
+		Where("MATCH(title, description) AGAINST (? IN BOOLEAN MODE)", booleanQuery(query)).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// booleanQuery 将查询转换为 BOOLEAN MODE 表达式，每个词项都加 + 表示必须命中
+// 词项只包含字母数字与汉字，不会引入额外的布尔运算符
+func booleanQuery(query *Query) string {
+	parts := make([]string, len(query.Terms))
+	for i, term := range query.Terms {
+		switch {
+		case term.IsPhrase():
+			parts[i] = `+"` + joinWords(term.Words) + `"`
+		case term.Prefix:
+			parts[i] = "+" + term.Words[0] + "*"
+		default:
+			parts[i] = "+" + term.Words[0]
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// joinWords 还原短语文本，汉字之间不加空格以便 ngram 分词
+func joinWords(words []string) string {
+	var b strings.Builder
+	for i, word := range words {
+		if i > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(words[i-1])
+			next, _ := utf8.DecodeRuneInString(word)
+			if !isHan(prev) || !isHan(next) {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
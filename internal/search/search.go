@@ -0,0 +1,140 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// maxTerms 单次查询允许的最大词项数
+const maxTerms = 10
+
+// Document 被索引的待办事项
+type Document struct {
+	ID          uint
+	UserID      uint
+	Title       string
+	Description string
+}
+
+// Hit 搜索命中结果，高亮片段由调用方根据文档内容通过 Highlight 生成
+type Hit struct {
+	ID    uint
+	Score float64
+}
+
+// Searcher 搜索后端接口
+type Searcher interface {
+	// Index 写入或更新文档
+	Index(ctx context.Context, doc Document) error
+	// Remove 从索引中移除文档
+	Remove(ctx context.Context, ids ...uint) error
+	// Search 在用户的文档中搜索，所有词项都必须命中，按相关度降序返回
+	Search(ctx context.Context, userID uint, query *Query, offset, limit int) ([]Hit, int, error)
+	// Match 返回用户文档中命中查询的全部ID，不排序也不分页，用于列表按关键词筛选
+	Match(ctx context.Context, userID uint, query *Query) ([]uint, error)
+}
+
+// Term 查询词项：单个词、前缀(foo*)或短语("foo bar")
+type Term struct {
+	Words  []string // 短语包含多个词
+	Prefix bool     // 仅单个词时有效
+}
+
+// IsPhrase 是否为短语
+func (t Term) IsPhrase() bool {
+	return len(t.Words) > 1
+}
+
+// Query 解析后的查询
+type Query struct {
+	Raw   string
+	Terms []Term
+}
+
+// ParseQuery 解析查询字符串：双引号包裹的内容为短语，以 * 结尾的词为前缀查询，
+// 连续的中文会按字切分后作为短语匹配
+func ParseQuery(raw string) (*Query, error) {
+	query := &Query{Raw: raw}
+	rest := strings.TrimSpace(raw)
+	for rest != "" {
+		var chunk string
+		quoted := false
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				return nil, errors.New("短语缺少结束引号")
+			}
+			chunk, rest = rest[1:end+1], rest[end+2:]
+			quoted = true
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			chunk, rest = rest[:end], rest[end:]
+		}
+		rest = strings.TrimSpace(rest)
+
+		prefix := !quoted && strings.HasSuffix(chunk, "*")
+		tokens := tokenize(strings.TrimSuffix(chunk, "*"))
+		if len(tokens) == 0 {
+			continue
+		}
+		term := Term{Words: make([]string, len(tokens))}
+		for i, token := range tokens {
+			term.Words[i] = token.text
+		}
+		term.Prefix = prefix && len(tokens) == 1
+		query.Terms = append(query.Terms, term)
+	}
+
+	if len(query.Terms) == 0 {
+		return nil, errors.New("搜索关键词不能为空")
+	}
+	if len(query.Terms) > maxTerms {
+		return nil, errors.New("搜索关键词过多")
+	}
+	return query, nil
+}
+
+// token 分词结果，start/end 为原文中的字节偏移
+type token struct {
+	text       string
+	start, end int
+}
+
+// isHan 是否为汉字，汉字按单字切分
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// tokenize 分词：字母数字连续组成一个词并转为小写，汉字每个字一个词，其余字符作为分隔符
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{text: strings.ToLower(text[start:end]), start: start, end: end})
+			start = -1
+		}
+	}
+
+	for i, r := range text {
+		switch {
+		case isHan(r):
+			flush(i)
+			end := i + len(string(r))
+			tokens = append(tokens, token{text: text[i:end], start: i, end: end})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return tokens
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"context"
+	"errors"
+)
+
+// Action 对共享资源的操作
+type Action int
+
+const (
+	ActionView   Action = iota // 查看，需要 viewer 及以上
+	ActionEdit                 // 修改，需要 editor 及以上
+	ActionManage               // 删除及管理共享，需要 owner
+)
+
+// requiredRole 操作所需的最低角色
+func (a Action) requiredRole() model.ShareRole {
+	switch a {
+	case ActionEdit:
+		return model.ShareRoleEditor
+	case ActionManage:
+		return model.ShareRoleOwner
+	default:
+		return model.ShareRoleViewer
+	}
+}
+
+// todoPermissionError 返回与操作对应的待办事项权限错误
+func todoPermissionError(action Action) error {
+	switch action {
+	case ActionEdit:
+		return errors.New("无权限修改此待办事项")
+	case ActionManage:
+		return errors.New("无权限删除此待办事项")
+	default:
+		return errors.New("无权限访问此待办事项")
+	}
+}
+
+// AccessControl 共享权限检查，资源创建者始终拥有 owner 角色
+type AccessControl interface {
+	TodoRole(ctx context.Context, todo *model.Todo, userID uint) (model.ShareRole, error)
+	ProjectRole(ctx context.Context, project *model.Project, userID uint) (model.ShareRole, error)
+	CanAccessTodo(ctx context.Context, todo *model.Todo, userID uint, action Action) (bool, error)
+	CanAccessProject(ctx context.Context, project *model.Project, userID uint, action Action) (bool, error)
+}
+
+type accessControl struct {
+	shareRepo repository.ShareRepository
+}
+
+// NewAccessControl 创建权限检查实例
+func NewAccessControl(shareRepo repository.ShareRepository) AccessControl {
+	return &accessControl{shareRepo: shareRepo}
+}
+
+// TodoRole 计算用户对待办事项的角色，取待办事项、父任务与所属项目共享中的最高角色
+func (a *accessControl) TodoRole(ctx context.Context, todo *model.Todo, userID uint) (model.ShareRole, error) {
+	if todo.UserID == userID {
+		return model.ShareRoleOwner, nil
+	}
+
+	todoIDs := []uint{todo.ID}
+	if todo.ParentID != nil {
+		todoIDs = append(todoIDs, *todo.ParentID)
+	}
+	shares, err := a.shareRepo.FindForTodo(ctx, userID, todoIDs, todo.ProjectID)
+	if err != nil {
+		return "", err
+	}
+
+	var role model.ShareRole
+	for _, share := range shares {
+		if share.Role.Level() > role.Level() {
+			role = share.Role
+		}
+	}
+	return role, nil
+}
+
+// ProjectRole 计算用户对项目的角色
+func (a *accessControl) ProjectRole(ctx context.Context, project *model.Project, userID uint) (model.ShareRole, error) {
+	if project.UserID == userID {
+		return model.ShareRoleOwner, nil
+	}
+
+	share, err := a.shareRepo.Get(ctx, model.ShareResourceProject, project.ID, userID)
+	if err != nil || share == nil {
+		return "", err
+	}
+	return share.Role, nil
+}
+
+// CanAccessTodo 检查用户能否对待办事项执行操作
+func (a *accessControl) CanAccessTodo(ctx context.Context, todo *model.Todo, userID uint, action Action) (bool, error) {
+	role, err := a.TodoRole(ctx, todo, userID)
+	if err != nil {
+		return false, err
+	}
+	return role.Level() >= action.requiredRole().Level(), nil
+}
+
+// CanAccessProject 检查用户能否对项目执行操作
+func (a *accessControl) CanAccessProject(ctx context.Context, project *model.Project, userID uint, action Action) (bool, error) {
+	role, err := a.ProjectRole(ctx, project, userID)
+	if err != nil {
+		return false, err
+	}
+	return role.Level() >= action.requiredRole().Level(), nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"TODO_API/pkg/logger"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxAppPasswords 每个用户最多创建的应用专用密码数
+	maxAppPasswords = 20
+	// appPasswordTouchInterval 最近使用时间的更新间隔，避免客户端频繁同步时每次都写库
+	appPasswordTouchInterval = 5 * time.Minute
+)
+
+// appPasswordEncoding 小写 base32，不含易混淆的 0/1/8/9
+var appPasswordEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// AppPasswordService 应用专用密码服务接口
+type AppPasswordService interface {
+	CreateAppPassword(ctx context.Context, userID uint, req *request.CreateAppPasswordRequest) (*response.AppPasswordResponse, error)
+	GetAppPasswords(ctx context.Context, userID uint) ([]response.AppPasswordResponse, error)
+	DeleteAppPassword(ctx context.Context, id, userID uint) error
+	Authenticate(ctx context.Context, username, password string) (uint, error)
+}
+
+type appPasswordService struct {
+	passwordRepo repository.AppPasswordRepository
+	userRepo     repository.UserRepository
+}
+
+// NewAppPasswordService 创建应用专用密码服务实例
+func NewAppPasswordService(passwordRepo repository.AppPasswordRepository, userRepo repository.UserRepository) AppPasswordService {
+	return &appPasswordService{passwordRepo: passwordRepo, userRepo: userRepo}
+}
+
+// normalizeAppPassword 去掉分隔符并转为小写，用户输入时可以带或不带 -
+func normalizeAppPassword(password string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(password))
+}
+
+// hashAppPassword 应用专用密码为随机生成的高熵字符串，使用 SHA-256 即可，便于按哈希直接查找
+func hashAppPassword(password string) string {
+	sum := sha256.Sum256([]byte(normalizeAppPassword(password)))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateAppPassword 生成16位随机密码，按4位一组以 - 分隔，如 abcd-efgh-ijkl-mnop
+func generateAppPassword() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	text := appPasswordEncoding.EncodeToString(buf)
+	return text[0:4] + "-" + text[4:8] + "-" + text[8:12] + "-" + text[12:16], nil
+}
+
+// appPasswordToResponse 将AppPassword模型转换为响应格式
+func appPasswordToResponse(password *model.AppPassword) response.AppPasswordResponse {
+	return response.AppPasswordResponse{
+		ID:         password.ID,
+		Name:       password.Name,
+		LastUsedAt: password.LastUsedAt,
+		CreatedAt:  password.CreatedAt,
+	}
+}
+
+// CreateAppPassword 创建应用专用密码，密码只在此时返回
+func (s *appPasswordService) CreateAppPassword(ctx context.Context, userID uint, req *request.CreateAppPasswordRequest) (*response.AppPasswordResponse, error) {
+	count, err := s.passwordRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAppPasswords {
+		return nil, errors.New("应用专用密码数量已达上限")
+	}
+
+	plain, err := generateAppPassword()
+	if err != nil {
+		return nil, err
+	}
+	password := &model.AppPassword{
+		UserID:       userID,
+		Name:         strings.TrimSpace(req.Name),
+		PasswordHash: hashAppPassword(plain),
+	}
+	if err := s.passwordRepo.Create(ctx, password); err != nil {
+		return nil, err
+	}
+
+	resp := appPasswordToResponse(password)
+	resp.Password = plain
+	return &resp, nil
+}
+
+// GetAppPasswords 获取应用专用密码列表
+func (s *appPasswordService) GetAppPasswords(ctx context.Context, userID uint) ([]response.AppPasswordResponse, error) {
+	passwords, err := s.passwordRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	responses := make([]response.AppPasswordResponse, len(passwords))
+	for i := range passwords {
+		responses[i] = appPasswordToResponse(&passwords[i])
+	}
+	return responses, nil
+}
+
+// DeleteAppPassword 吊销应用专用密码
+func (s *appPasswordService) DeleteAppPassword(ctx context.Context, id, userID uint) error {
+	found, err := s.passwordRepo.Delete(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("应用专用密码不存在")
+	}
+	return nil
+}
+
+// Authenticate 校验用户名与应用专用密码，返回用户ID；不区分用户名不存在与密码错误
+func (s *appPasswordService) Authenticate(ctx context.Context, username, password string) (uint, error) {
+	invalid := errors.New("用户名或应用专用密码错误")
+	if username == "" || password == "" {
+		return 0, invalid
+	}
+
+	appPassword, err := s.passwordRepo.GetByHash(ctx, hashAppPassword(password))
+	if err != nil {
+		return 0, err
+	}
+	if appPassword == nil {
+		return 0, invalid
+	}
+	user, err := s.userRepo.GetByID(ctx, appPassword.UserID)
+	if err != nil {
+		return 0, err
+	}
+	if user == nil || user.Status == 0 || user.Username != username {
+		return 0, invalid
+	}
+
+	now := time.Now()
+	if appPassword.LastUsedAt == nil || now.Sub(*appPassword.LastUsedAt) > appPasswordTouchInterval {
+		if err := s.passwordRepo.TouchLastUsed(ctx, appPassword.ID, now); err != nil {
+			logger.Warn("更新应用专用密码使用时间失败", zap.Uint("id", appPassword.ID), zap.Error(err))
+		}
+	}
+	return user.ID, nil
+}
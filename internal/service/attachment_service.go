@@ -0,0 +1,278 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"TODO_API/pkg/logger"
+	"TODO_API/pkg/storage"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// AttachmentService 附件服务接口
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, todoID, userID uint, file *multipart.FileHeader) (*response.AttachmentResponse, error)
+	GetAttachments(ctx context.Context, todoID, userID uint) ([]response.AttachmentResponse, error)
+	OpenAttachment(ctx context.Context, todoID, attachmentID, userID uint) (*response.AttachmentResponse, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, todoID, attachmentID, userID uint) error
+	// PurgeTodoFiles 删除待办事项的全部附件文件及记录，供彻底删除待办事项时调用
+	PurgeTodoFiles(ctx context.Context, todoIDs []uint) error
+}
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	todoRepo       repository.TodoRepository
+	access         AccessControl
+	store          storage.Storage
+	maxSize        int64
+	allowedTypes   []string
+}
+
+// NewAttachmentService 创建附件服务实例，maxSize 为单个文件最大字节数，allowedTypes 支持 image/* 通配
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, todoRepo repository.TodoRepository,
+	access AccessControl, store storage.Storage, maxSize int64, allowedTypes []string) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		todoRepo:       todoRepo,
+		access:         access,
+		store:          store,
+		maxSize:        maxSize,
+		allowedTypes:   allowedTypes,
+	}
+}
+
+// attachmentToResponse 将Attachment模型转换为响应格式
+func attachmentToResponse(attachment *model.Attachment) response.AttachmentResponse {
+	return response.AttachmentResponse{
+		ID:          attachment.ID,
+		TodoID:      attachment.TodoID,
+		UserID:      attachment.UserID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// getAuthorizedTodo 获取待办事项并检查当前用户的共享权限
+func (s *attachmentService) getAuthorizedTodo(ctx context.Context, todoID, userID uint, action Action) (*model.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, errors.New("待办事项不存在")
+	}
+
+	ok, err := s.access.CanAccessTodo(ctx, todo, userID, action)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, todoPermissionError(action)
+	}
+	return todo, nil
+}
+
+// getTodoAttachment 获取属于该待办事项的附件
+func (s *attachmentService) getTodoAttachment(ctx context.Context, todoID, attachmentID uint) (*model.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment == nil || attachment.TodoID != todoID {
+		return nil, errors.New("附件不存在")
+	}
+	return attachment, nil
+}
+
+// isAllowedType 检查MIME类型是否在白名单中，未配置白名单时不限制
+func (s *attachmentService) isAllowedType(contentType string) bool {
+	if len(s.allowedTypes) == 0 {
+		return true
+	}
+	for _, allowed := range s.allowedTypes {
+		if allowed == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType 根据文件内容判断MIME类型，无法识别时按扩展名判断
+// 不信任客户端提交的 Content-Type，避免伪装类型绕过白名单
+func detectContentType(head []byte, fileName string) string {
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	//Office 文档按内容会被识别为 zip 或 octet-stream，此时用扩展名细化
+	if contentType == "application/octet-stream" || contentType == "application/zip" {
+		if byExt, _, err := mime.ParseMediaType(mime.TypeByExtension(filepath.Ext(fileName))); err == nil {
+			return byExt
+		}
+	}
+	return contentType
+}
+
+// newStorageKey 生成随机对象键，避免文件名冲突及路径注入
+func newStorageKey(todoID uint, fileName string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if len(ext) > 10 {
+		ext = ""
+	}
+	return fmt.Sprintf("todos/%d/%s%s", todoID, hex.EncodeToString(buf), ext), nil
+}
+
+// UploadAttachment 上传附件
+func (s *attachmentService) UploadAttachment(ctx context.Context, todoID, userID uint, file *multipart.FileHeader) (*response.AttachmentResponse, error) {
+	if _, err := s.getAuthorizedTodo(ctx, todoID, userID, ActionEdit); err != nil {
+		return nil, err
+	}
+
+	if file.Size == 0 {
+		return nil, errors.New("文件不能为空")
+	}
+	if s.maxSize > 0 && file.Size > s.maxSize {
+		return nil, errors.New("文件大小超出限制")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	fileName := filepath.Base(filepath.Clean(file.Filename))
+	contentType := detectContentType(head[:n], fileName)
+	if !s.isAllowedType(contentType) {
+		return nil, errors.New("不支持的文件类型")
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	key, err := newStorageKey(todoID, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, key, src, file.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	//保留包含扩展名的末尾部分，在字符边界截断
+	if len(fileName) > 255 {
+		cut := len(fileName) - 255
+		for cut < len(fileName) && !utf8.RuneStart(fileName[cut]) {
+			cut++
+		}
+		fileName = fileName[cut:]
+	}
+	attachment := &model.Attachment{
+		TodoID:      todoID,
+		UserID:      userID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        file.Size,
+		StorageKey:  key,
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		//记录写入失败时清理已上传的文件
+		if delErr := s.store.Delete(ctx, key); delErr != nil {
+			logger.Error("清理附件文件失败", zap.String("key", key), zap.Error(delErr))
+		}
+		return nil, err
+	}
+	resp := attachmentToResponse(attachment)
+	return &resp, nil
+}
+
+// GetAttachments 获取待办事项的附件列表
+func (s *attachmentService) GetAttachments(ctx context.Context, todoID, userID uint) ([]response.AttachmentResponse, error) {
+	if _, err := s.getAuthorizedTodo(ctx, todoID, userID, ActionView); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.GetByTodoID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	attachmentResponses := make([]response.AttachmentResponse, len(attachments))
+	for i := range attachments {
+		attachmentResponses[i] = attachmentToResponse(&attachments[i])
+	}
+	return attachmentResponses, nil
+}
+
+// OpenAttachment 打开附件文件用于下载，调用方负责关闭
+func (s *attachmentService) OpenAttachment(ctx context.Context, todoID, attachmentID, userID uint) (*response.AttachmentResponse, io.ReadCloser, error) {
+	if _, err := s.getAuthorizedTodo(ctx, todoID, userID, ActionView); err != nil {
+		return nil, nil, err
+	}
+	attachment, err := s.getTodoAttachment(ctx, todoID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, errors.New("附件文件不存在")
+		}
+		return nil, nil, err
+	}
+	resp := attachmentToResponse(attachment)
+	return &resp, content, nil
+}
+
+// DeleteAttachment 删除附件及其文件
+func (s *attachmentService) DeleteAttachment(ctx context.Context, todoID, attachmentID, userID uint) error {
+	if _, err := s.getAuthorizedTodo(ctx, todoID, userID, ActionEdit); err != nil {
+		return err
+	}
+	attachment, err := s.getTodoAttachment(ctx, todoID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+	return s.attachmentRepo.Delete(ctx, attachment.ID)
+}
+
+// PurgeTodoFiles 删除待办事项的全部附件文件及记录
+func (s *attachmentService) PurgeTodoFiles(ctx context.Context, todoIDs []uint) error {
+	attachments, err := s.attachmentRepo.GetByTodoIDs(ctx, todoIDs)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+			return err
+		}
+	}
+	return s.attachmentRepo.DeleteByTodoIDs(ctx, todoIDs)
+}
@@ -55,6 +55,7 @@ func (s *authService) generateAuthServiceWithToken(user *model.User) (*response.
 			Username: user.Username,
 			Email:    user.Email,
 			Avatar:   user.AvatarURL,
+			Version:  user.Version,
 		},
 	}, nil
 }
@@ -147,6 +148,7 @@ func (s *authService) RefreshToken(ctx context.Context, refreshToken string) (*r
 			Username: user.Username,
 			Email:    user.Email,
 			Avatar:   user.AvatarURL,
+			Version:  user.Version,
 		},
 	}, nil
 }
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"mime/multipart"
+)
+
+// maxCalendarFileSize 导入的日历文件最大字节数
+const maxCalendarFileSize = 5 << 20
+
+// CalendarService 日历订阅与导入导出服务接口
+type CalendarService interface {
+	GetToken(ctx context.Context, userID uint) (*response.CalendarTokenResponse, error)
+	RotateToken(ctx context.Context, userID uint) (*response.CalendarTokenResponse, error)
+	RevokeToken(ctx context.Context, userID uint) error
+	Feed(ctx context.Context, token string) ([]byte, error)
+	Export(ctx context.Context, userID uint) ([]byte, error)
+	Import(ctx context.Context, userID uint, file *multipart.FileHeader) (*response.CalendarImportResponse, error)
+}
+
+type calendarService struct {
+	userRepo repository.UserRepository
+	todos    TodoService
+}
+
+// NewCalendarService 创建日历服务实例
+func NewCalendarService(userRepo repository.UserRepository, todos TodoService) CalendarService {
+	return &calendarService{userRepo: userRepo, todos: todos}
+}
+
+// hashCalendarToken 数据库中只保存令牌的哈希
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// GetToken 获取日历订阅状态
+func (s *calendarService) GetToken(ctx context.Context, userID uint) (*response.CalendarTokenResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.New("用户不存在")
+	}
+	return &response.CalendarTokenResponse{Enabled: user.CalendarTokenHash != nil}, nil
+}
+
+// RotateToken 生成新的订阅令牌，旧的订阅地址随之失效
+func (s *calendarService) RotateToken(ctx context.Context, userID uint) (*response.CalendarTokenResponse, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	tokenHash := hashCalendarToken(token)
+	if err := s.userRepo.UpdateCalendarToken(ctx, userID, &tokenHash); err != nil {
+		return nil, err
+	}
+	return &response.CalendarTokenResponse{Enabled: true, Token: token}, nil
+}
+
+// RevokeToken 停用日历订阅
+func (s *calendarService) RevokeToken(ctx context.Context, userID uint) error {
+	return s.userRepo.UpdateCalendarToken(ctx, userID, nil)
+}
+
+// Feed 根据订阅令牌生成日历，令牌无效或用户已禁用时返回"订阅地址无效"
+func (s *calendarService) Feed(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, errors.New("订阅地址无效")
+	}
+	user, err := s.userRepo.GetByCalendarToken(ctx, hashCalendarToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Status == 0 {
+		return nil, errors.New("订阅地址无效")
+	}
+	return s.todos.ExportCalendar(ctx, user.ID)
+}
+
+// Export 导出当前用户的待办事项
+func (s *calendarService) Export(ctx context.Context, userID uint) ([]byte, error) {
+	return s.todos.ExportCalendar(ctx, userID)
+}
+
+// Import 导入 .ics 文件
+func (s *calendarService) Import(ctx context.Context, userID uint, file *multipart.FileHeader) (*response.CalendarImportResponse, error) {
+	if file.Size == 0 {
+		return nil, errors.New("文件不能为空")
+	}
+	if file.Size > maxCalendarFileSize {
+		return nil, errors.New("文件大小超出限制")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	return s.todos.ImportCalendar(ctx, userID, src)
+}
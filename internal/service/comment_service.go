@@ -0,0 +1,162 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+// CommentService 评论服务接口
+type CommentService interface {
+	CreateComment(ctx context.Context, todoID, userID uint, req *request.CreateCommentRequest) (*response.CommentResponse, error)
+	GetComments(ctx context.Context, todoID, userID uint, query *request.CommentQueryRequest) (*response.CommentListResponse, error)
+	UpdateComment(ctx context.Context, todoID, commentID, userID uint, req *request.UpdateCommentRequest) (*response.CommentResponse, error)
+	DeleteComment(ctx context.Context, todoID, commentID, userID uint) error
+}
+
+type commentService struct {
+	commentRepo repository.CommentRepository
+	todoRepo    repository.TodoRepository
+	access      AccessControl
+}
+
+// NewCommentService 创建评论服务实例
+func NewCommentService(commentRepo repository.CommentRepository, todoRepo repository.TodoRepository,
+	access AccessControl) CommentService {
+	return &commentService{commentRepo: commentRepo, todoRepo: todoRepo, access: access}
+}
+
+// commentToResponse 将Comment模型转换为响应格式
+func commentToResponse(comment *model.Comment) response.CommentResponse {
+	return response.CommentResponse{
+		ID:        comment.ID,
+		TodoID:    comment.TodoID,
+		UserID:    comment.UserID,
+		Username:  comment.User.Username,
+		Body:      comment.Body,
+		EditedAt:  comment.EditedAt,
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+// getAuthorizedTodo 获取待办事项并检查当前用户的共享权限
+func (s *commentService) getAuthorizedTodo(ctx context.Context, todoID, userID uint, action Action) (*model.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, errors.New("待办事项不存在")
+	}
+
+	ok, err := s.access.CanAccessTodo(ctx, todo, userID, action)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, todoPermissionError(action)
+	}
+	return todo, nil
+}
+
+// getChangeableComment 获取评论，只有评论作者或待办事项所有者可以修改
+func (s *commentService) getChangeableComment(ctx context.Context, todoID, commentID, userID uint) (*model.Comment, error) {
+	todo, err := s.getAuthorizedTodo(ctx, todoID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment == nil || comment.TodoID != todoID {
+		return nil, errors.New("评论不存在")
+	}
+	if comment.UserID != userID && todo.UserID != userID {
+		return nil, errors.New("无权限修改此评论")
+	}
+	return comment, nil
+}
+
+// CreateComment 发表评论，只读用户也可以参与讨论
+func (s *commentService) CreateComment(ctx context.Context, todoID, userID uint, req *request.CreateCommentRequest) (*response.CommentResponse, error) {
+	if _, err := s.getAuthorizedTodo(ctx, todoID, userID, ActionView); err != nil {
+		return nil, err
+	}
+
+	comment := &model.Comment{
+		TodoID: todoID,
+		UserID: userID,
+		Body:   req.Body,
+	}
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	created, err := s.commentRepo.GetByID(ctx, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := commentToResponse(created)
+	return &resp, nil
+}
+
+// GetComments 分页获取评论列表
+func (s *commentService) GetComments(ctx context.Context, todoID, userID uint, query *request.CommentQueryRequest) (*response.CommentListResponse, error) {
+	if _, err := s.getAuthorizedTodo(ctx, todoID, userID, ActionView); err != nil {
+		return nil, err
+	}
+
+	comments, totalCount, err := s.commentRepo.GetByTodoID(ctx, todoID, query.Page, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	commentResponses := make([]response.CommentResponse, len(comments))
+	for i := range comments {
+		commentResponses[i] = commentToResponse(&comments[i])
+	}
+	totalPage := (uint(totalCount) + query.PageSize - 1) / query.PageSize
+
+	return &response.CommentListResponse{
+		Comments: commentResponses,
+		Pagination: response.Pagination{
+			Page:       query.Page,
+			PageSize:   query.PageSize,
+			Total:      uint(totalCount),
+			TotalPages: totalPage,
+		},
+	}, nil
+}
+
+// UpdateComment 编辑评论
+func (s *commentService) UpdateComment(ctx context.Context, todoID, commentID, userID uint, req *request.UpdateCommentRequest) (*response.CommentResponse, error) {
+	comment, err := s.getChangeableComment(ctx, todoID, commentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Body != comment.Body {
+		now := time.Now()
+		comment.Body = req.Body
+		comment.EditedAt = &now
+		if err := s.commentRepo.Update(ctx, comment); err != nil {
+			return nil, err
+		}
+	}
+	resp := commentToResponse(comment)
+	return &resp, nil
+}
+
+// DeleteComment 删除评论
+func (s *commentService) DeleteComment(ctx context.Context, todoID, commentID, userID uint) error {
+	if _, err := s.getChangeableComment(ctx, todoID, commentID, userID); err != nil {
+		return err
+	}
+	return s.commentRepo.Delete(ctx, commentID)
+}
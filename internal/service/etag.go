@@ -0,0 +1,56 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"TODO_API/pkg/etag"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ResponseETag 由响应内容生成强 ETag。标签、评论数、前置任务等关联数据的变化不一定使版本号加1，
+// 但都会改变响应内容，ETag 随之变化
+func ResponseETag(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		//响应结构总能编码，无法编码时不返回 ETag，If-Match 也不会匹配
+		return ""
+	}
+	return etag.Compute(data)
+}
+
+// checkIfMatch 校验 If-Match：为 * 或包含与当前响应内容一致的强 ETag 时通过，否则说明客户端持有的数据已过期
+func checkIfMatch(ifMatch string, current any) error {
+	if !etag.StrongMatch(ifMatch, ResponseETag(current)) {
+		return repository.ErrVersionConflict
+	}
+	return nil
+}
+
+// checkTodoIfMatch 校验 If-Match 是否与待办事项当前的响应内容一致，* 时不生成响应
+func (s *todoService) checkTodoIfMatch(ctx context.Context, todo *model.Todo, ifMatch string) error {
+	if strings.TrimSpace(ifMatch) == "*" {
+		return nil
+	}
+	current, err := s.buildResponse(ctx, todo)
+	if err != nil {
+		return err
+	}
+	return checkIfMatch(ifMatch, current)
+}
+
+// reloadResponse 写入后重新读取待办事项生成响应，时间字段与数据库保存的精度一致，
+// 响应的 ETag 与随后获取时相同，客户端可直接用于下一次修改
+func (s *todoService) reloadResponse(ctx context.Context, id uint) (*response.TodoResponse, error) {
+	todo, err := s.todoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, errors.New("待办事项不存在")
+	}
+	return s.buildResponse(ctx, todo)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"context"
+	"errors"
+)
+
+// ProjectService 项目服务接口
+type ProjectService interface {
+	CreateProject(ctx context.Context, userID uint, req *request.CreateProjectRequest) (*response.ProjectResponse, error)
+	GetProjects(ctx context.Context, userID uint) ([]response.ProjectResponse, error)
+	GetProjectByID(ctx context.Context, id, userID uint) (*response.ProjectResponse, error)
+	UpdateProject(ctx context.Context, id, userID uint, req *request.UpdateProjectRequest) (*response.ProjectResponse, error)
+	DeleteProject(ctx context.Context, id, userID uint, deleteTodos bool) error
+	GetProjectStatistics(ctx context.Context, id, userID uint) (*response.Statistics, error)
+}
+
+type projectService struct {
+	projectRepo repository.ProjectRepository
+	todoRepo    repository.TodoRepository
+	todos       TodoService
+	access      AccessControl
+}
+
+// NewProjectService 创建项目服务实例
+func NewProjectService(projectRepo repository.ProjectRepository, todoRepo repository.TodoRepository,
+	todos TodoService, access AccessControl) ProjectService {
+	return &projectService{projectRepo: projectRepo, todoRepo: todoRepo, todos: todos, access: access}
+}
+
+// projectToResponse 将Project模型转换为响应格式
+func projectToResponse(project *model.Project) *response.ProjectResponse {
+	var description string
+	if project.Description != nil {
+		description = *project.Description
+	}
+	return &response.ProjectResponse{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: description,
+		Color:       project.Color,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+	}
+}
+
+// getAuthorizedProject 获取项目并检查当前用户的共享权限
+func (s *projectService) getAuthorizedProject(ctx context.Context, id, userID uint, action Action) (*model.Project, error) {
+	project, err := s.projectRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, errors.New("项目不存在")
+	}
+
+	ok, err := s.access.CanAccessProject(ctx, project, userID, action)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("无权限访问此项目")
+	}
+	return project, nil
+}
+
+// CreateProject 创建项目
+func (s *projectService) CreateProject(ctx context.Context, userID uint, req *request.CreateProjectRequest) (*response.ProjectResponse, error) {
+	existing, err := s.projectRepo.GetByName(ctx, userID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("项目名已存在")
+	}
+
+	project := &model.Project{
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	}
+	if req.Description != "" {
+		project.Description = &req.Description
+	}
+	if project.Color == "" {
+		project.Color = "#1890ff"
+	}
+
+	if err := s.projectRepo.Create(ctx, project); err != nil {
+		return nil, err
+	}
+	return projectToResponse(project), nil
+}
+
+// GetProjects 获取当前用户的全部项目
+func (s *projectService) GetProjects(ctx context.Context, userID uint) ([]response.ProjectResponse, error) {
+	projects, err := s.projectRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectResponses := make([]response.ProjectResponse, len(projects))
+	for i := range projects {
+		projectResponses[i] = *projectToResponse(&projects[i])
+	}
+	return projectResponses, nil
+}
+
+// GetProjectByID 获取项目详情，包含统计信息
+func (s *projectService) GetProjectByID(ctx context.Context, id, userID uint) (*response.ProjectResponse, error) {
+	project, err := s.getAuthorizedProject(ctx, id, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.todoRepo.GetStatistics(ctx, project.UserID, &project.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := projectToResponse(project)
+	statistics := statsToResponse(stats)
+	resp.Statistics = &statistics
+	return resp, nil
+}
+
+// UpdateProject 更新项目
+func (s *projectService) UpdateProject(ctx context.Context, id, userID uint, req *request.UpdateProjectRequest) (*response.ProjectResponse, error) {
+	project, err := s.getAuthorizedProject(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	//项目名在所有者的项目中唯一
+	if req.Name != "" && req.Name != project.Name {
+		existing, err := s.projectRepo.GetByName(ctx, project.UserID, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New("项目名已存在")
+		}
+		project.Name = req.Name
+	}
+	if req.Description != "" {
+		project.Description = &req.Description
+	}
+	if req.Color != "" {
+		project.Color = req.Color
+	}
+
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, err
+	}
+	return projectToResponse(project), nil
+}
+
+// DeleteProject 删除项目，deleteTodos 决定项目内待办事项是删除还是移入收件箱
+func (s *projectService) DeleteProject(ctx context.Context, id, userID uint, deleteTodos bool) error {
+	if _, err := s.getAuthorizedProject(ctx, id, userID, ActionManage); err != nil {
+		return err
+	}
+	return s.todos.DeleteProject(ctx, id, userID, deleteTodos)
+}
+
+// GetProjectStatistics 获取项目统计信息
+func (s *projectService) GetProjectStatistics(ctx context.Context, id, userID uint) (*response.Statistics, error) {
+	project, err := s.getAuthorizedProject(ctx, id, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.todoRepo.GetStatistics(ctx, project.UserID, &project.ID)
+	if err != nil {
+		return nil, err
+	}
+	statistics := statsToResponse(stats)
+	return &statistics, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"context"
+	"errors"
+	"time"
+)
+
+// ReminderService 提醒服务接口
+type ReminderService interface {
+	CreateReminder(ctx context.Context, todoID, userID uint, req *request.CreateReminderRequest) (*response.ReminderResponse, error)
+	GetReminders(ctx context.Context, todoID, userID uint) ([]response.ReminderResponse, error)
+	DeleteReminder(ctx context.Context, todoID, reminderID, userID uint) error
+}
+
+type reminderService struct {
+	reminderRepo repository.ReminderRepository
+	todoRepo     repository.TodoRepository
+	access       AccessControl
+}
+
+// NewReminderService 创建提醒服务实例
+func NewReminderService(reminderRepo repository.ReminderRepository, todoRepo repository.TodoRepository,
+	access AccessControl) ReminderService {
+	return &reminderService{reminderRepo: reminderRepo, todoRepo: todoRepo, access: access}
+}
+
+// reminderToResponse 将Reminder模型转换为响应格式
+func reminderToResponse(reminder *model.Reminder) response.ReminderResponse {
+	return response.ReminderResponse{
+		ID:            reminder.ID,
+		TodoID:        reminder.TodoID,
+		RemindAt:      reminder.RemindAt,
+		OffsetMinutes: reminder.OffsetMinutes,
+		SentAt:        reminder.SentAt,
+		FailedAt:      reminder.FailedAt,
+		CreatedAt:     reminder.CreatedAt,
+	}
+}
+
+// getVisibleTodo 获取待办事项并检查查看权限，提醒属于各自的用户，共享的只读用户也可设置
+func (s *reminderService) getVisibleTodo(ctx context.Context, todoID, userID uint) (*model.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, errors.New("待办事项不存在")
+	}
+
+	ok, err := s.access.CanAccessTodo(ctx, todo, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, todoPermissionError(ActionView)
+	}
+	return todo, nil
+}
+
+// CreateReminder 创建提醒
+func (s *reminderService) CreateReminder(ctx context.Context, todoID, userID uint, req *request.CreateReminderRequest) (*response.ReminderResponse, error) {
+	todo, err := s.getVisibleTodo(ctx, todoID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	reminder := &model.Reminder{
+		TodoID: todo.ID,
+		UserID: userID,
+	}
+	if req.OffsetMinutes != nil {
+		//相对提醒依赖截止时间
+		if todo.DueDate == nil {
+			return nil, errors.New("待办事项未设置截止时间")
+		}
+		reminder.OffsetMinutes = req.OffsetMinutes
+		reminder.RemindAt = todo.DueDate.Add(-time.Duration(*req.OffsetMinutes) * time.Minute)
+	} else {
+		reminder.RemindAt = *req.RemindAt
+	}
+	if reminder.RemindAt.Before(time.Now()) {
+		return nil, errors.New("提醒时间已过")
+	}
+
+	if err := s.reminderRepo.Create(ctx, reminder); err != nil {
+		return nil, err
+	}
+	resp := reminderToResponse(reminder)
+	return &resp, nil
+}
+
+// GetReminders 获取待办事项的提醒列表
+func (s *reminderService) GetReminders(ctx context.Context, todoID, userID uint) ([]response.ReminderResponse, error) {
+	if _, err := s.getVisibleTodo(ctx, todoID, userID); err != nil {
+		return nil, err
+	}
+
+	reminders, err := s.reminderRepo.GetByTodoID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	//只返回当前用户自己的提醒
+	reminderResponses := make([]response.ReminderResponse, 0, len(reminders))
+	for i := range reminders {
+		if reminders[i].UserID == userID {
+			reminderResponses = append(reminderResponses, reminderToResponse(&reminders[i]))
+		}
+	}
+	return reminderResponses, nil
+}
+
+// DeleteReminder 删除提醒
+func (s *reminderService) DeleteReminder(ctx context.Context, todoID, reminderID, userID uint) error {
+	if _, err := s.getVisibleTodo(ctx, todoID, userID); err != nil {
+		return err
+	}
+
+	reminder, err := s.reminderRepo.GetByID(ctx, reminderID)
+	if err != nil {
+		return err
+	}
+	if reminder == nil || reminder.TodoID != todoID || reminder.UserID != userID {
+		return errors.New("提醒不存在")
+	}
+	return s.reminderRepo.Delete(ctx, reminderID)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"context"
+	"errors"
+)
+
+// ShareService 共享服务接口
+type ShareService interface {
+	CreateShare(ctx context.Context, userID uint, req *request.CreateShareRequest) (*response.ShareResponse, error)
+	GetShares(ctx context.Context, userID uint, query *request.ShareQueryRequest) ([]response.ShareResponse, error)
+	GetSharedWithMe(ctx context.Context, userID uint) ([]response.ShareResponse, error)
+	UpdateShare(ctx context.Context, id, userID uint, req *request.UpdateShareRequest) (*response.ShareResponse, error)
+	DeleteShare(ctx context.Context, id, userID uint) error
+}
+
+type shareService struct {
+	shareRepo   repository.ShareRepository
+	todoRepo    repository.TodoRepository
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+	access      AccessControl
+}
+
+// NewShareService 创建共享服务实例
+func NewShareService(shareRepo repository.ShareRepository, todoRepo repository.TodoRepository,
+	projectRepo repository.ProjectRepository, userRepo repository.UserRepository, access AccessControl) ShareService {
+	return &shareService{
+		shareRepo:   shareRepo,
+		todoRepo:    todoRepo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		access:      access,
+	}
+}
+
+// shareToResponse 将Share模型转换为响应格式
+func shareToResponse(share *model.Share) response.ShareResponse {
+	return response.ShareResponse{
+		ID:            share.ID,
+		ResourceType:  share.ResourceType,
+		ResourceID:    share.ResourceID,
+		OwnerID:       share.OwnerID,
+		OwnerUsername: share.Owner.Username,
+		UserID:        share.UserID,
+		Username:      share.User.Username,
+		Role:          string(share.Role),
+		CreatedAt:     share.CreatedAt,
+	}
+}
+
+// resolveResource 获取共享资源的所有者与名称，资源不存在时返回错误
+func (s *shareService) resolveResource(ctx context.Context, resourceType string, resourceID, userID uint,
+	action Action) (ownerID uint, name string, err error) {
+	switch resourceType {
+	case model.ShareResourceTodo:
+		todo, err := s.todoRepo.GetByID(ctx, resourceID)
+		if err != nil {
+			return 0, "", err
+		}
+		if todo == nil {
+			return 0, "", errors.New("待办事项不存在")
+		}
+		ok, err := s.access.CanAccessTodo(ctx, todo, userID, action)
+		if err != nil {
+			return 0, "", err
+		}
+		if !ok {
+			return 0, "", errors.New("无权限管理此共享")
+		}
+		return todo.UserID, todo.Title, nil
+	case model.ShareResourceProject:
+		project, err := s.projectRepo.GetByID(ctx, resourceID)
+		if err != nil {
+			return 0, "", err
+		}
+		if project == nil {
+			return 0, "", errors.New("项目不存在")
+		}
+		ok, err := s.access.CanAccessProject(ctx, project, userID, action)
+		if err != nil {
+			return 0, "", err
+		}
+		if !ok {
+			return 0, "", errors.New("无权限管理此共享")
+		}
+		return project.UserID, project.Name, nil
+	default:
+		return 0, "", errors.New("不支持的共享资源类型")
+	}
+}
+
+// getManagedShare 获取共享记录并检查当前用户是否可管理该资源的共享
+func (s *shareService) getManagedShare(ctx context.Context, id, userID uint) (*model.Share, error) {
+	share, err := s.shareRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if share == nil {
+		return nil, errors.New("共享不存在")
+	}
+	if _, _, err := s.resolveResource(ctx, share.ResourceType, share.ResourceID, userID, ActionManage); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// CreateShare 将待办事项或项目共享给其他用户
+func (s *shareService) CreateShare(ctx context.Context, userID uint, req *request.CreateShareRequest) (*response.ShareResponse, error) {
+	ownerID, name, err := s.resolveResource(ctx, req.ResourceType, req.ResourceID, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, errors.New("用户不存在")
+	}
+	//资源所有者本身拥有全部权限
+	if target.ID == userID || target.ID == ownerID {
+		return nil, errors.New("不能共享给自己或资源所有者")
+	}
+
+	existing, err := s.shareRepo.Get(ctx, req.ResourceType, req.ResourceID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("已共享给该用户")
+	}
+
+	share := &model.Share{
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceID,
+		OwnerID:      ownerID,
+		UserID:       target.ID,
+		Role:         model.ShareRole(req.Role),
+	}
+	if err := s.shareRepo.Create(ctx, share); err != nil {
+		return nil, err
+	}
+
+	created, err := s.shareRepo.GetByID(ctx, share.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := shareToResponse(created)
+	resp.ResourceName = name
+	return &resp, nil
+}
+
+// GetShares 获取资源的共享列表，需要管理权限
+func (s *shareService) GetShares(ctx context.Context, userID uint, query *request.ShareQueryRequest) ([]response.ShareResponse, error) {
+	_, name, err := s.resolveResource(ctx, query.ResourceType, query.ResourceID, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shareRepo.GetByResource(ctx, query.ResourceType, query.ResourceID)
+	if err != nil {
+		return nil, err
+	}
+	shareResponses := make([]response.ShareResponse, len(shares))
+	for i := range shares {
+		shareResponses[i] = shareToResponse(&shares[i])
+		shareResponses[i].ResourceName = name
+	}
+	return shareResponses, nil
+}
+
+// GetSharedWithMe 获取共享给当前用户的资源，已删除的资源会被跳过
+func (s *shareService) GetSharedWithMe(ctx context.Context, userID uint) ([]response.ShareResponse, error) {
+	shares, err := s.shareRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	shareResponses := make([]response.ShareResponse, 0, len(shares))
+	for i := range shares {
+		_, name, err := s.resolveResource(ctx, shares[i].ResourceType, shares[i].ResourceID, userID, ActionView)
+		if err != nil {
+			if err.Error() == "待办事项不存在" || err.Error() == "项目不存在" {
+				continue
+			}
+			return nil, err
+		}
+		resp := shareToResponse(&shares[i])
+		resp.ResourceName = name
+		shareResponses = append(shareResponses, resp)
+	}
+	return shareResponses, nil
+}
+
+// UpdateShare 修改共享角色
+func (s *shareService) UpdateShare(ctx context.Context, id, userID uint, req *request.UpdateShareRequest) (*response.ShareResponse, error) {
+	share, err := s.getManagedShare(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	//不能修改自己的角色，避免 owner 误降级后失去管理权
+	if share.UserID == userID {
+		return nil, errors.New("不能修改自己的共享角色")
+	}
+
+	share.Role = model.ShareRole(req.Role)
+	if err := s.shareRepo.Update(ctx, share); err != nil {
+		return nil, err
+	}
+	resp := shareToResponse(share)
+	return &resp, nil
+}
+
+// DeleteShare 取消共享，被共享的用户也可以主动退出
+func (s *shareService) DeleteShare(ctx context.Context, id, userID uint) error {
+	share, err := s.shareRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if share == nil {
+		return errors.New("共享不存在")
+	}
+
+	if share.UserID != userID {
+		if _, err := s.getManagedShare(ctx, id, userID); err != nil {
+			return err
+		}
+	}
+	return s.shareRepo.Delete(ctx, id)
+}
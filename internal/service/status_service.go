@@ -0,0 +1,159 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"context"
+	"errors"
+)
+
+// maxCustomStatuses 每个用户最多的自定义状态数量
+const maxCustomStatuses = 50
+
+// statusCategories 状态分类，自定义状态必须归属其中之一
+var statusCategories = []model.TodoStatus{0, 1, 2}
+
+// StatusService 自定义状态服务接口
+type StatusService interface {
+	GetWorkflow(ctx context.Context, userID uint) (*response.WorkflowResponse, error)
+	CreateStatus(ctx context.Context, userID uint, req *request.CreateStatusRequest) (*response.StatusResponse, error)
+	UpdateStatus(ctx context.Context, id, userID uint, req *request.UpdateStatusRequest) (*response.StatusResponse, error)
+	DeleteStatus(ctx context.Context, id, userID uint) error
+}
+
+type statusService struct {
+	statusRepo repository.StatusRepository
+}
+
+// NewStatusService 创建自定义状态服务实例
+func NewStatusService(statusRepo repository.StatusRepository) StatusService {
+	return &statusService{statusRepo: statusRepo}
+}
+
+// statusToResponse 将WorkflowStatus模型转换为响应格式
+func statusToResponse(status *model.WorkflowStatus) response.StatusResponse {
+	return response.StatusResponse{
+		ID:           status.ID,
+		Name:         status.Name,
+		Category:     uint8(status.Category),
+		CategoryText: getStatusText(status.Category),
+		Color:        status.Color,
+		Position:     status.Position,
+		CreatedAt:    status.CreatedAt,
+	}
+}
+
+// getOwnedStatus 获取自定义状态并检查所有权
+func (s *statusService) getOwnedStatus(ctx context.Context, id, userID uint) (*model.WorkflowStatus, error) {
+	status, err := s.statusRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil || status.UserID != userID {
+		return nil, errors.New("状态不存在")
+	}
+	return status, nil
+}
+
+// GetWorkflow 获取状态工作流：各分类允许的流转及其下的自定义状态
+func (s *statusService) GetWorkflow(ctx context.Context, userID uint) (*response.WorkflowResponse, error) {
+	statuses, err := s.statusRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow := &response.WorkflowResponse{Categories: make([]response.StatusCategoryResponse, len(statusCategories))}
+	for i, category := range statusCategories {
+		transitions := model.Transitions(category)
+		workflow.Categories[i] = response.StatusCategoryResponse{
+			Status:      uint8(category),
+			StatusText:  getStatusText(category),
+			Transitions: make([]uint8, len(transitions)),
+			Statuses:    []response.StatusResponse{},
+		}
+		for j, next := range transitions {
+			workflow.Categories[i].Transitions[j] = uint8(next)
+		}
+	}
+	for i := range statuses {
+		category := &workflow.Categories[statuses[i].Category]
+		category.Statuses = append(category.Statuses, statusToResponse(&statuses[i]))
+	}
+	return workflow, nil
+}
+
+// CreateStatus 创建自定义状态
+func (s *statusService) CreateStatus(ctx context.Context, userID uint, req *request.CreateStatusRequest) (*response.StatusResponse, error) {
+	existing, err := s.statusRepo.GetByName(ctx, userID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("状态名已存在")
+	}
+	count, err := s.statusRepo.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxCustomStatuses {
+		return nil, errors.New("自定义状态数量已达上限")
+	}
+
+	status := &model.WorkflowStatus{
+		UserID:   userID,
+		Name:     req.Name,
+		Category: model.TodoStatus(*req.Category),
+		Color:    req.Color,
+		Position: req.Position,
+	}
+	if status.Color == "" {
+		status.Color = "#8c8c8c"
+	}
+
+	if err := s.statusRepo.Create(ctx, status); err != nil {
+		return nil, err
+	}
+	resp := statusToResponse(status)
+	return &resp, nil
+}
+
+// UpdateStatus 更新自定义状态
+func (s *statusService) UpdateStatus(ctx context.Context, id, userID uint, req *request.UpdateStatusRequest) (*response.StatusResponse, error) {
+	status, err := s.getOwnedStatus(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" && req.Name != status.Name {
+		existing, err := s.statusRepo.GetByName(ctx, userID, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New("状态名已存在")
+		}
+		status.Name = req.Name
+	}
+	if req.Color != "" {
+		status.Color = req.Color
+	}
+	if req.Position != nil {
+		status.Position = *req.Position
+	}
+
+	if err := s.statusRepo.Update(ctx, status); err != nil {
+		return nil, err
+	}
+	resp := statusToResponse(status)
+	return &resp, nil
+}
+
+// DeleteStatus 删除自定义状态，使用该状态的待办事项保留所属分类
+func (s *statusService) DeleteStatus(ctx context.Context, id, userID uint) error {
+	if _, err := s.getOwnedStatus(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.statusRepo.Delete(ctx, id)
+}
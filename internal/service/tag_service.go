@@ -0,0 +1,139 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"context"
+	"errors"
+)
+
+// TagService 标签服务接口
+type TagService interface {
+	CreateTag(ctx context.Context, userID uint, req *request.CreateTagRequest) (*response.TagResponse, error)
+	GetTags(ctx context.Context, userID uint) ([]response.TagResponse, error)
+	GetTagByID(ctx context.Context, id, userID uint) (*response.TagResponse, error)
+	UpdateTag(ctx context.Context, id, userID uint, req *request.UpdateTagRequest) (*response.TagResponse, error)
+	DeleteTag(ctx context.Context, id, userID uint) error
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+// NewTagService 创建标签服务实例
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{tagRepo: tagRepo}
+}
+
+// tagToResponse 将Tag模型转换为响应格式
+func tagToResponse(tag *model.Tag) response.TagResponse {
+	return response.TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedAt: tag.CreatedAt,
+	}
+}
+
+// getOwnedTag 获取标签并检查所有权
+func (s *tagService) getOwnedTag(ctx context.Context, id, userID uint) (*model.Tag, error) {
+	tag, err := s.tagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, errors.New("标签不存在")
+	}
+	if tag.UserID != userID {
+		return nil, errors.New("无权限访问此标签")
+	}
+	return tag, nil
+}
+
+// CreateTag 创建标签
+func (s *tagService) CreateTag(ctx context.Context, userID uint, req *request.CreateTagRequest) (*response.TagResponse, error) {
+	existing, err := s.tagRepo.GetByName(ctx, userID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("标签名已存在")
+	}
+
+	tag := &model.Tag{
+		UserID: userID,
+		Name:   req.Name,
+		Color:  req.Color,
+	}
+	if tag.Color == "" {
+		tag.Color = "#1890ff"
+	}
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	resp := tagToResponse(tag)
+	return &resp, nil
+}
+
+// GetTags 获取当前用户的全部标签
+func (s *tagService) GetTags(ctx context.Context, userID uint) ([]response.TagResponse, error) {
+	tags, err := s.tagRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tagResponses := make([]response.TagResponse, len(tags))
+	for i := range tags {
+		tagResponses[i] = tagToResponse(&tags[i])
+	}
+	return tagResponses, nil
+}
+
+// GetTagByID 根据ID获取标签
+func (s *tagService) GetTagByID(ctx context.Context, id, userID uint) (*response.TagResponse, error) {
+	tag, err := s.getOwnedTag(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := tagToResponse(tag)
+	return &resp, nil
+}
+
+// UpdateTag 更新标签
+func (s *tagService) UpdateTag(ctx context.Context, id, userID uint, req *request.UpdateTagRequest) (*response.TagResponse, error) {
+	tag, err := s.getOwnedTag(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" && req.Name != tag.Name {
+		existing, err := s.tagRepo.GetByName(ctx, userID, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, errors.New("标签名已存在")
+		}
+		tag.Name = req.Name
+	}
+	if req.Color != "" {
+		tag.Color = req.Color
+	}
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	resp := tagToResponse(tag)
+	return &resp, nil
+}
+
+// DeleteTag 删除标签
+func (s *tagService) DeleteTag(ctx context.Context, id, userID uint) error {
+	if _, err := s.getOwnedTag(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.Delete(ctx, id)
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"context"
+	"errors"
+	"strings"
+)
+
+// 批量操作类型
+const (
+	batchActionUpdate = "update"
+	batchActionDelete = "delete"
+)
+
+// errBatchRolledBack 全部回滚模式下有待办事项失败，用于撤销整个事务
+var errBatchRolledBack = errors.New("批量操作已回滚")
+
+// BatchTodos 在一个事务中批量修改或删除待办事项，逐项返回结果。每项使用保存点，失败时只撤销该项；
+// 全部回滚模式下任一项失败则撤销全部修改
+func (s *todoService) BatchTodos(ctx context.Context, userID uint, req *request.BatchTodoRequest) (*response.BatchTodoResponse, error) {
+	if req.Action == batchActionUpdate && req.Status == nil && req.StatusID == nil && req.Priority == nil &&
+		req.DueDate == nil && req.ProjectID == nil && len(req.AddTagIDs) == 0 && len(req.RemoveTagIDs) == 0 {
+		return nil, errors.New("未指定要修改的字段")
+	}
+
+	//去重并保持顺序，同一待办事项只处理一次
+	seen := make(map[uint]bool, len(req.TodoIDs))
+	var ids []uint
+	for _, id := range req.TodoIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	result := &response.BatchTodoResponse{Total: len(ids)}
+	var updated []*model.Todo
+	var deleted []uint
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		result.Items = make([]response.BatchTodoItem, len(ids))
+		result.Succeeded, result.Failed = 0, 0
+		updated, deleted = nil, nil
+		for i, id := range ids {
+			item := &result.Items[i]
+			item.ID = id
+			var todos []*model.Todo
+			var removed []uint
+			err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				var err error
+				if req.Action == batchActionDelete {
+					removed, err = s.batchDelete(ctx, id, userID)
+				} else {
+					todos, err = s.batchUpdate(ctx, id, userID, req)
+				}
+				return err
+			})
+			if err != nil {
+				item.Result, item.Message = batchFailure(err), err.Error()
+				result.Failed++
+				continue
+			}
+			//只记录已写入的单项，整个批量操作提交后才更新搜索索引
+			updated = append(updated, todos...)
+			deleted = append(deleted, removed...)
+			item.Result = response.BatchResultUpdated
+			if req.Action == batchActionDelete {
+				item.Result = response.BatchResultDeleted
+			}
+			result.Succeeded++
+		}
+
+		if req.AllOrNothing && result.Failed > 0 {
+			for i := range result.Items {
+				switch result.Items[i].Result {
+				case response.BatchResultUpdated, response.BatchResultDeleted:
+					result.Items[i].Result = response.BatchResultRolledBack
+				}
+			}
+			result.Succeeded = 0
+			return errBatchRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchRolledBack) {
+		result.RolledBack = true
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	//事务提交后再更新搜索索引
+	s.indexTodos(ctx, updated...)
+	if len(deleted) > 0 {
+		s.unindexTodos(ctx, deleted...)
+	}
+	return result, nil
+}
+
+// batchFailure 将单项的错误归类为批量操作结果
+func batchFailure(err error) string {
+	switch {
+	case err.Error() == "待办事项不存在":
+		return response.BatchResultNotFound
+	case strings.HasPrefix(err.Error(), "无权限"):
+		return response.BatchResultForbidden
+	default:
+		return response.BatchResultFailed
+	}
+}
+
+// batchUpdate 修改单个待办事项的指定字段，规则与 UpdateTodo、AttachTags、DetachTag 一致。
+// 只有写入成功时返回修改后的待办事项及完成重复任务时生成的下一次实例
+func (s *todoService) batchUpdate(ctx context.Context, id, userID uint, req *request.BatchTodoRequest) ([]*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+	before := *todo
+
+	if req.Status != nil || req.StatusID != nil {
+		status, statusID, err := s.resolveStatus(ctx, todo, req.Status, req.StatusID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.changeStatus(ctx, todo, status, statusID, userID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Priority != nil {
+		todo.Priority = model.TodosPriority(*req.Priority)
+	}
+	dueDateChanged := false
+	if req.DueDate != nil {
+		dueDateChanged = todo.DueDate == nil || !todo.DueDate.Equal(*req.DueDate)
+		todo.DueDate = req.DueDate
+	}
+	if req.ProjectID != nil {
+		if err := s.changeProject(ctx, todo, *req.ProjectID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := s.batchTags(ctx, todo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := s.saveTodo(ctx, &before, todo, userID)
+	if err != nil {
+		return nil, err
+	}
+	if dueDateChanged {
+		if err := s.reminderRepo.RescheduleRelative(ctx, todo.ID, *todo.DueDate); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.RemoveTagIDs) > 0 {
+		if err := s.tagRepo.DetachFromTodo(ctx, todo.ID, req.RemoveTagIDs); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.AddTagIDs) > 0 {
+		if err := s.tagRepo.AttachToTodo(ctx, todo.ID, req.AddTagIDs); err != nil {
+			return nil, err
+		}
+	}
+	todo.Tags = tags
+
+	events := todoChanges(&before, todo, userID)
+	events = append(events, tagChange(todo.ID, userID, before.Tags, todo.Tags)...)
+	s.recordHistory(ctx, events...)
+	if next != nil {
+		return []*model.Todo{todo, next}, nil
+	}
+	return []*model.Todo{todo}, nil
+}
+
+// batchTags 计算添加、移除标签后的标签列表，只允许使用待办事项所有者的标签
+func (s *todoService) batchTags(ctx context.Context, todo *model.Todo, req *request.BatchTodoRequest) ([]model.Tag, error) {
+	removed := make(map[uint]bool, len(req.RemoveTagIDs))
+	for _, tagID := range req.RemoveTagIDs {
+		removed[tagID] = true
+	}
+	tags := make([]model.Tag, 0, len(todo.Tags)+len(req.AddTagIDs))
+	attached := make(map[uint]bool, len(todo.Tags))
+	for _, tag := range todo.Tags {
+		if !removed[tag.ID] {
+			tags = append(tags, tag)
+			attached[tag.ID] = true
+		}
+	}
+	if len(req.AddTagIDs) == 0 {
+		return tags, nil
+	}
+
+	added, err := s.tagRepo.GetByIDs(ctx, todo.UserID, req.AddTagIDs)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[uint]model.Tag, len(added))
+	for _, tag := range added {
+		owned[tag.ID] = tag
+	}
+	for _, tagID := range req.AddTagIDs {
+		tag, ok := owned[tagID]
+		if !ok {
+			return nil, errors.New("标签不存在")
+		}
+		//同时添加和移除的标签以添加为准
+		if !attached[tagID] {
+			tags = append(tags, tag)
+			attached[tagID] = true
+		}
+	}
+	return tags, nil
+}
+
+// batchDelete 删除单个待办事项，规则与 DeleteTodo 一致，返回需要从搜索索引中移除的ID
+func (s *todoService) batchDelete(ctx context.Context, id, userID uint) ([]uint, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.todoRepo.Delete(ctx, id); err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, actionEvent(id, userID, model.HistoryActionDeleted))
+	ids := []uint{todo.ID}
+	for i := range todo.Subtasks {
+		ids = append(ids, todo.Subtasks[i].ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/rank"
+	"context"
+	"errors"
+)
+
+// maxRankLength 排序键的最大长度，与数据库字段一致，超出时重新分配全部排序键
+const maxRankLength = 64
+
+// errRebalanceRanks 相邻任务的排序键缺失、重复或新键过长，需要重新分配排序键
+var errRebalanceRanks = errors.New("需要重新分配排序键")
+
+// GetBoard 获取看板：每个状态一列，列内按排序键排列，只包含顶层任务
+func (s *todoService) GetBoard(ctx context.Context, userID uint, query *request.BoardQueryRequest) (*response.BoardResponse, error) {
+	//查看共享项目时按项目所有者查询
+	ownerID := userID
+	if query.ProjectID != nil && *query.ProjectID != 0 {
+		project, err := s.resolveProject(ctx, userID, *query.ProjectID, ActionView)
+		if err != nil {
+			return nil, err
+		}
+		ownerID = project.UserID
+	}
+
+	board := &response.BoardResponse{ProjectID: query.ProjectID, Columns: make([]response.BoardColumn, 0, len(statusCategories))}
+	for _, status := range statusCategories {
+		todos, total, err := s.todoRepo.GetBoardColumn(ctx, ownerID, status, query.ProjectID, query.Limit)
+		if err != nil {
+			return nil, err
+		}
+		column := response.BoardColumn{
+			Status:     uint8(status),
+			StatusText: getStatusText(status),
+			Total:      uint(total),
+			Todos:      make([]response.TodoResponse, len(todos)),
+		}
+		for i := range todos {
+			column.Todos[i] = *s.todoToResponse(&todos[i])
+		}
+		if err := s.fillTodoDetails(ctx, column.Todos); err != nil {
+			return nil, err
+		}
+		board.Columns = append(board.Columns, column)
+	}
+	return board, nil
+}
+
+// MoveTodo 在看板中移动待办事项：只修改被移动任务的排序键，移动到其他列时按状态机修改状态，ifMatch 须匹配当前版本
+func (s *todoService) MoveTodo(ctx context.Context, id, userID uint, ifMatch string, req *request.MoveTodoRequest) (*response.TodoResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTodoIfMatch(ctx, todo, ifMatch); err != nil {
+		return nil, err
+	}
+	if model.IsSubtask(todo) {
+		return nil, errors.New("子任务不能在看板中移动")
+	}
+	status, statusID, err := s.resolveStatus(ctx, todo, req.Status, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	boardRank, err := s.boardRank(ctx, todo, status, req)
+	if errors.Is(err, errRebalanceRanks) {
+		if err := s.todoRepo.RebalanceRanks(ctx, todo.UserID); err != nil {
+			return nil, err
+		}
+		//重新分配同时修改了被移动任务的排序键及版本号，需重新读取后再保存
+		if todo, err = s.getAuthorizedTodo(ctx, id, userID, ActionEdit); err != nil {
+			return nil, err
+		}
+		boardRank, err = s.boardRank(ctx, todo, status, req)
+		//重新分配后仍无法生成，说明指定的前后任务顺序不对
+		if errors.Is(err, errRebalanceRanks) {
+			err = errors.New("相邻的待办事项无效")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	before := *todo
+	if err := s.changeStatus(ctx, todo, status, statusID, userID); err != nil {
+		return nil, err
+	}
+	todo.BoardRank = boardRank
+
+	next, err := s.saveTodo(ctx, &before, todo, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, todoChanges(&before, todo, userID)...)
+	if status != before.Status {
+		s.indexTodos(ctx, todo, next)
+	}
+	return s.reloadResponse(ctx, todo.ID)
+}
+
+// boardRank 根据目标列及前后任务生成新的排序键；只指定一侧时另一侧取列中紧邻的任务
+func (s *todoService) boardRank(ctx context.Context, todo *model.Todo, status model.TodoStatus,
+	req *request.MoveTodoRequest) (string, error) {
+	prev, err := s.boardNeighbor(ctx, todo, status, req.BeforeID)
+	if err != nil {
+		return "", err
+	}
+	next, err := s.boardNeighbor(ctx, todo, status, req.AfterID)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case req.BeforeID != nil && req.AfterID == nil:
+		next, err = s.todoRepo.GetBoardNeighbor(ctx, todo.UserID, status, prev, true, todo.ID)
+	case req.BeforeID == nil && req.AfterID != nil:
+		prev, err = s.todoRepo.GetBoardNeighbor(ctx, todo.UserID, status, next, false, todo.ID)
+	case req.BeforeID == nil && req.AfterID == nil:
+		next, err = s.todoRepo.GetBoardNeighbor(ctx, todo.UserID, status, nil, true, todo.ID)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var lower, upper string
+	if prev != nil {
+		lower = prev.BoardRank
+	}
+	if next != nil {
+		upper = next.BoardRank
+	}
+	if (prev != nil && !rank.Valid(lower)) || (next != nil && !rank.Valid(upper)) {
+		return "", errRebalanceRanks
+	}
+	key, err := rank.Between(lower, upper)
+	if err != nil || len(key) > maxRankLength {
+		return "", errRebalanceRanks
+	}
+	return key, nil
+}
+
+// boardNeighbor 获取指定的相邻任务，必须是同一所有者、位于目标列中的其他顶层任务
+func (s *todoService) boardNeighbor(ctx context.Context, todo *model.Todo, status model.TodoStatus, id *uint) (*model.Todo, error) {
+	if id == nil {
+		return nil, nil
+	}
+	neighbor, err := s.todoRepo.GetByID(ctx, *id)
+	if err != nil {
+		return nil, err
+	}
+	if neighbor == nil || neighbor.ID == todo.ID || neighbor.UserID != todo.UserID ||
+		model.IsSubtask(neighbor) || neighbor.Status != status {
+		return nil, errors.New("相邻的待办事项无效")
+	}
+	return neighbor, nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/etag"
+	"TODO_API/pkg/ical"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CalendarObject CalDAV 日历对象，每个待办事项对应一个只包含一个 VTODO 的日历
+type CalendarObject struct {
+	Name   string // 资源名，如 todo-12.ics
+	ETag   string // 带引号的强 ETag，内容变化时随之变化
+	Data   []byte
+	TodoID uint
+}
+
+// calendarObjectName 日历对象的资源名，CalDAV 客户端创建的沿用客户端指定的名称
+func calendarObjectName(todo *model.Todo) string {
+	if todo.CalDAVName != nil {
+		return *todo.CalDAVName
+	}
+	return fmt.Sprintf("todo-%d.ics", todo.ID)
+}
+
+// renderCalendarObject 生成日历对象，DTSTAMP 使用更新时间，保证内容不变时 ETag 不变
+func renderCalendarObject(todo *model.Todo, uids map[uint]string) *CalendarObject {
+	stamp := time.Unix(0, 0)
+	if todo.UpdatedAt != nil {
+		stamp = *todo.UpdatedAt
+	}
+	cal := ical.NewCalendar(calendarProdID)
+	cal.Components = append(cal.Components, todoToVTODO(todo, uids, stamp))
+
+	var buf bytes.Buffer
+	ical.Encode(&buf, cal)
+	return &CalendarObject{
+		Name:   calendarObjectName(todo),
+		ETag:   etag.Compute(buf.Bytes()),
+		Data:   buf.Bytes(),
+		TodoID: todo.ID,
+	}
+}
+
+// calendarTodos 获取用户的全部待办事项及ID到UID的映射
+func (s *todoService) calendarTodos(ctx context.Context, userID uint) ([]model.Todo, map[uint]string, error) {
+	todos, err := s.todoRepo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	uids := make(map[uint]string, len(todos))
+	for i := range todos {
+		uids[todos[i].ID] = todoUID(&todos[i])
+	}
+	return todos, uids, nil
+}
+
+// findCalendarTodo 按资源名查找待办事项，客户端指定的名称优先
+func findCalendarTodo(todos []model.Todo, name string) *model.Todo {
+	var fallback *model.Todo
+	for i := range todos {
+		if todos[i].CalDAVName != nil {
+			if *todos[i].CalDAVName == name {
+				return &todos[i]
+			}
+		} else if fallback == nil && calendarObjectName(&todos[i]) == name {
+			fallback = &todos[i]
+		}
+	}
+	return fallback
+}
+
+// checkCalendarPrecondition 校验 If-Match(强比较) / If-None-Match(弱比较)，current 为空表示对象不存在
+func checkCalendarPrecondition(current, ifMatch, ifNoneMatch string) error {
+	if ifNoneMatch != "" && etag.WeakMatch(ifNoneMatch, current) {
+		return errors.New("日历对象已被修改")
+	}
+	if ifMatch != "" && !etag.StrongMatch(ifMatch, current) {
+		return errors.New("日历对象已被修改")
+	}
+	return nil
+}
+
+// ListCalendarObjects 获取用户的全部日历对象
+func (s *todoService) ListCalendarObjects(ctx context.Context, userID uint) ([]CalendarObject, error) {
+	todos, uids, err := s.calendarTodos(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	objects := make([]CalendarObject, len(todos))
+	for i := range todos {
+		objects[i] = *renderCalendarObject(&todos[i], uids)
+	}
+	return objects, nil
+}
+
+// GetCalendarObject 按资源名获取日历对象
+func (s *todoService) GetCalendarObject(ctx context.Context, userID uint, name string) (*CalendarObject, error) {
+	todos, uids, err := s.calendarTodos(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	todo := findCalendarTodo(todos, name)
+	if todo == nil {
+		return nil, errors.New("日历对象不存在")
+	}
+	return renderCalendarObject(todo, uids), nil
+}
+
+// calendarVTODO 取日历对象中的 VTODO，带 RECURRENCE-ID 的例外实例不单独处理
+func calendarVTODO(r io.Reader) (*ical.Component, error) {
+	root, err := ical.Parse(r)
+	if err != nil {
+		return nil, errors.New("日历数据无效: " + err.Error())
+	}
+	if root.Name != ical.ComponentCalendar {
+		return nil, errors.New("日历数据无效: 缺少 VCALENDAR")
+	}
+
+	var master *ical.Component
+	for _, child := range root.Components {
+		switch child.Name {
+		case ical.ComponentTodo:
+			if child.Prop("RECURRENCE-ID") != nil {
+				continue
+			}
+			if master != nil {
+				return nil, errors.New("日历数据无效: 一个日历对象只能包含一个待办事项")
+			}
+			master = child
+		case "VTIMEZONE":
+		default:
+			return nil, errors.New("仅支持 VTODO")
+		}
+	}
+	if master == nil {
+		return nil, errors.New("仅支持 VTODO")
+	}
+	return master, nil
+}
+
+// PutCalendarObject 创建或更新日历对象，返回是否为新建；新建与更新分别与 Create、UpdateTodo
+// 一样记录变更并更新搜索索引。保存的内容会被规范化，客户端需重新获取以得到新的 ETag
+func (s *todoService) PutCalendarObject(ctx context.Context, userID uint, name string, r io.Reader,
+	ifMatch, ifNoneMatch string) (bool, error) {
+	vtodo, err := calendarVTODO(r)
+	if err != nil {
+		return false, err
+	}
+	uid := strings.TrimSpace(vtodo.Text("UID"))
+	if uid == "" {
+		return false, errors.New("日历数据无效: 缺少 UID")
+	}
+	incoming, _, err := vtodoToTodo(vtodo, userID)
+	if err != nil {
+		return false, errors.New("日历数据无效: " + err.Error())
+	}
+
+	todos, uids, err := s.calendarTodos(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	existing := findCalendarTodo(todos, name)
+	var current string
+	if existing != nil {
+		current = renderCalendarObject(existing, uids).ETag
+	}
+	if err := checkCalendarPrecondition(current, ifMatch, ifNoneMatch); err != nil {
+		return false, err
+	}
+
+	tags := vtodoTags(vtodo)
+	if existing == nil {
+		for i := range todos {
+			if uids[todos[i].ID] == uid {
+				return false, errors.New("UID 已被其他日历对象使用")
+			}
+		}
+		incoming.CalDAVName = &name
+		incoming.ICalUID = &uid
+		linkCalendarParent(incoming, todos, uids, vtodoParentUID(vtodo))
+		if err := s.todoRepo.Create(ctx, incoming); err != nil {
+			return false, err
+		}
+		s.recordHistory(ctx, actionEvent(incoming.ID, userID, model.HistoryActionCreated))
+		s.indexTodos(ctx, incoming)
+		if err := s.setTodoTags(ctx, incoming, userID, tags, make(map[string]*model.Tag)); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if uids[existing.ID] != uid {
+		return false, errors.New("日历数据无效: UID 与已有日历对象不一致")
+	}
+	// 客户端未提供完成时间时保留原有的完成时间
+	if existing.Status == 2 && vtodo.Prop("COMPLETED") == nil {
+		incoming.CompletedAt = existing.CompletedAt
+	}
+	return false, s.applyCalendarUpdate(ctx, existing, incoming, tags, userID)
+}
+
+// linkCalendarParent 新建的对象通过 RELATED-TO 关联到已有的顶层任务
+func linkCalendarParent(todo *model.Todo, todos []model.Todo, uids map[uint]string, parentUID string) {
+	if parentUID == "" {
+		return
+	}
+	var parent *model.Todo
+	position := 0
+	for i := range todos {
+		if uids[todos[i].ID] == parentUID && !model.IsSubtask(&todos[i]) {
+			parent = &todos[i]
+		}
+	}
+	if parent == nil {
+		return
+	}
+	for i := range todos {
+		if todos[i].ParentID != nil && *todos[i].ParentID == parent.ID {
+			position++
+		}
+	}
+	todo.ParentID = &parent.ID
+	todo.ProjectID = parent.ProjectID
+	todo.Position = position
+}
+
+// applyCalendarUpdate 以客户端上传的内容整体替换待办事项的字段，状态按状态机流转，与 UpdateTodo 一致
+func (s *todoService) applyCalendarUpdate(ctx context.Context, todo, incoming *model.Todo, tags []string, userID uint) error {
+	before := *todo
+
+	todo.Title = incoming.Title
+	todo.Description = incoming.Description
+	todo.Priority = incoming.Priority
+	dueDateChanged := incoming.DueDate != nil && (todo.DueDate == nil || !todo.DueDate.Equal(*incoming.DueDate))
+	todo.DueDate = incoming.DueDate
+	todo.Recurrence = incoming.Recurrence
+
+	//日历客户端只知道分类，分类不变时保留自定义状态
+	statusID := todo.StatusID
+	if incoming.Status != todo.Status {
+		statusID = nil
+	}
+	if err := s.changeStatus(ctx, todo, incoming.Status, statusID, userID); err != nil {
+		return err
+	}
+	//完成时间以客户端提供的为准
+	if model.IsCompleted(todo) {
+		todo.CompletedAt = incoming.CompletedAt
+	}
+
+	next, err := s.saveTodo(ctx, &before, todo, userID)
+	if err != nil {
+		return err
+	}
+	s.recordHistory(ctx, todoChanges(&before, todo, userID)...)
+	s.indexTodos(ctx, todo, next)
+	if dueDateChanged {
+		if err := s.reminderRepo.RescheduleRelative(ctx, todo.ID, *todo.DueDate); err != nil {
+			return err
+		}
+	}
+	return s.setTodoTags(ctx, todo, userID, tags, make(map[string]*model.Tag))
+}
+
+// DeleteCalendarObject 删除日历对象，与 DeleteTodo 一样移入回收站
+func (s *todoService) DeleteCalendarObject(ctx context.Context, userID uint, name, ifMatch string) error {
+	todos, uids, err := s.calendarTodos(ctx, userID)
+	if err != nil {
+		return err
+	}
+	todo := findCalendarTodo(todos, name)
+	if todo == nil {
+		return errors.New("日历对象不存在")
+	}
+	if err := checkCalendarPrecondition(renderCalendarObject(todo, uids).ETag, ifMatch, ""); err != nil {
+		return err
+	}
+	//日历对象的 ETag 已校验，删除时不再比较待办事项的 ETag
+	return s.DeleteTodo(ctx, todo.ID, userID, "*")
+}
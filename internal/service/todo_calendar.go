@@ -0,0 +1,505 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/ical"
+	"TODO_API/pkg/recurrence"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// calendarProdID 导出日历的 PRODID
+	calendarProdID = "-//TODO_API//Todo Calendar//ZH"
+	// calendarUIDDomain 本系统生成的 UID 后缀，如 todo-12@todo-api
+	calendarUIDDomain = "todo-api"
+	// maxImportTodos 单个日历文件最多导入的待办事项数
+	maxImportTodos = 1000
+	// maxTitleLength 标题最大长度，与创建请求的校验一致
+	maxTitleLength = 200
+	// maxTagNameLength 标签名最大长度
+	maxTagNameLength = 50
+)
+
+// todoUID 待办事项在日历中的 UID，导入的待办事项沿用原始 UID
+func todoUID(todo *model.Todo) string {
+	if todo.ICalUID != nil {
+		return *todo.ICalUID
+	}
+	return fmt.Sprintf("todo-%d@%s", todo.ID, calendarUIDDomain)
+}
+
+// parseTodoUID 解析本系统生成的 UID，返回待办事项ID
+func parseTodoUID(uid string) (uint, bool) {
+	text, ok := strings.CutSuffix(uid, "@"+calendarUIDDomain)
+	if !ok {
+		return 0, false
+	}
+	text, ok = strings.CutPrefix(text, "todo-")
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(text, 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// priorityToICal 优先级转换为 RFC 5545 的 1(最高)~9(最低)
+func priorityToICal(priority model.TodosPriority) string {
+	switch priority {
+	case 4:
+		return "1"
+	case 3:
+		return "3"
+	case 2:
+		return "5"
+	default:
+		return "9"
+	}
+}
+
+// priorityFromICal 1~2 为紧急，3~4 为高，5 为中，其余(含0未指定)为低
+func priorityFromICal(value string) model.TodosPriority {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	switch {
+	case err != nil || n <= 0 || n > 9:
+		return 1
+	case n <= 2:
+		return 4
+	case n <= 4:
+		return 3
+	case n == 5:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// statusToICal 状态转换为 VTODO 的 STATUS
+func statusToICal(status model.TodoStatus) string {
+	switch status {
+	case 1:
+		return "IN-PROCESS"
+	case 2:
+		return "COMPLETED"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
+
+// todoToVTODO 将待办事项转换为 VTODO 组件，uids 为ID到UID的映射，用于生成父任务的 RELATED-TO
+func todoToVTODO(todo *model.Todo, uids map[uint]string, now time.Time) *ical.Component {
+	vtodo := &ical.Component{Name: ical.ComponentTodo}
+	vtodo.AddText("UID", todoUID(todo))
+	vtodo.AddDateTime("DTSTAMP", now)
+	if todo.CreatedAt != nil {
+		vtodo.AddDateTime("CREATED", *todo.CreatedAt)
+	}
+	if todo.UpdatedAt != nil {
+		vtodo.AddDateTime("LAST-MODIFIED", *todo.UpdatedAt)
+	}
+	vtodo.AddText("SUMMARY", todo.Title)
+	if todo.Description != nil && *todo.Description != "" {
+		vtodo.AddText("DESCRIPTION", *todo.Description)
+	}
+	vtodo.Add("STATUS", statusToICal(todo.Status))
+	vtodo.Add("PRIORITY", priorityToICal(todo.Priority))
+	if todo.DueDate != nil {
+		vtodo.AddDateTime("DUE", *todo.DueDate)
+	}
+	if todo.Status == 2 && todo.CompletedAt != nil {
+		vtodo.AddDateTime("COMPLETED", *todo.CompletedAt)
+		vtodo.Add("PERCENT-COMPLETE", "100")
+	}
+	if todo.Recurrence != nil {
+		vtodo.Add("RRULE", *todo.Recurrence)
+	}
+	if len(todo.Tags) > 0 {
+		names := make([]string, len(todo.Tags))
+		for i := range todo.Tags {
+			names[i] = todo.Tags[i].Name
+		}
+		vtodo.Add("CATEGORIES", ical.JoinText(names))
+	}
+	if todo.ParentID != nil {
+		if parentUID, ok := uids[*todo.ParentID]; ok {
+			vtodo.AddText("RELATED-TO", parentUID)
+		}
+	}
+	return vtodo
+}
+
+// ExportCalendar 将用户的全部待办事项导出为 iCalendar 格式
+func (s *todoService) ExportCalendar(ctx context.Context, userID uint) ([]byte, error) {
+	todos, err := s.todoRepo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make(map[uint]string, len(todos))
+	for i := range todos {
+		uids[todos[i].ID] = todoUID(&todos[i])
+	}
+
+	cal := ical.NewCalendar(calendarProdID)
+	cal.AddText("X-WR-CALNAME", "待办事项")
+	// 建议订阅客户端每小时刷新一次
+	cal.Add("REFRESH-INTERVAL", "PT1H").Params = map[string]string{"VALUE": "DURATION"}
+	cal.Add("X-PUBLISHED-TTL", "PT1H")
+	now := time.Now()
+	for i := range todos {
+		cal.Components = append(cal.Components, todoToVTODO(&todos[i], uids, now))
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, cal); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// importCandidate 待导入的 VTODO 及其转换结果
+type importCandidate struct {
+	item      *response.CalendarImportItem
+	todo      *model.Todo
+	tags      []string
+	parentUID string
+}
+
+// vtodoToTodo 将 VTODO 转换为待办事项，返回的提示信息表示部分内容被忽略
+func vtodoToTodo(vtodo *ical.Component, userID uint) (*model.Todo, []string, error) {
+	title := strings.TrimSpace(vtodo.Text("SUMMARY"))
+	if title == "" {
+		return nil, nil, errors.New("缺少标题(SUMMARY)")
+	}
+	var notes []string
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		title = string([]rune(title)[:maxTitleLength])
+		notes = append(notes, "标题过长，已截断")
+	}
+
+	todo := &model.Todo{UserID: userID, Title: title, Priority: 1}
+	if description := vtodo.Text("DESCRIPTION"); description != "" {
+		todo.Description = &description
+	}
+	if prop := vtodo.Prop("PRIORITY"); prop != nil {
+		todo.Priority = priorityFromICal(prop.Value)
+	}
+
+	if prop := vtodo.Prop("DUE"); prop != nil {
+		due, dateOnly, err := prop.Time(time.Local)
+		if err != nil {
+			return nil, nil, err
+		}
+		// 只有日期时截止到当天结束
+		if dateOnly {
+			due = due.Add(24*time.Hour - time.Second)
+		}
+		todo.DueDate = &due
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(vtodo.Text("STATUS"))) {
+	case "IN-PROCESS":
+		model.MarkProgress(todo)
+	case "COMPLETED":
+		model.MarkCompleted(todo)
+	default:
+		// 未设置 STATUS 但有完成时间时同样视为已完成
+		if vtodo.Prop("COMPLETED") != nil {
+			model.MarkCompleted(todo)
+		}
+	}
+	if prop := vtodo.Prop("COMPLETED"); prop != nil && model.IsCompleted(todo) {
+		if completedAt, _, err := prop.Time(time.Local); err == nil {
+			todo.CompletedAt = &completedAt
+		}
+	}
+
+	if prop := vtodo.Prop("RRULE"); prop != nil {
+		rule, err := recurrence.Parse(prop.Value)
+		if err == nil {
+			err = rule.Validate()
+		}
+		switch {
+		case err != nil:
+			notes = append(notes, "重复规则不受支持，已忽略")
+		case todo.DueDate == nil:
+			notes = append(notes, "重复任务缺少截止时间，已忽略重复规则")
+		default:
+			text := rule.String()
+			todo.Recurrence = &text
+		}
+	}
+	return todo, notes, nil
+}
+
+// vtodoTags 取 CATEGORIES 中的标签名，去除空白及重复
+func vtodoTags(vtodo *ical.Component) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, prop := range vtodo.PropsNamed("CATEGORIES") {
+		for _, name := range ical.SplitText(prop.Value) {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[name] || utf8.RuneCountInString(name) > maxTagNameLength {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// vtodoParentUID 取父任务的 UID，RELTYPE 缺省即为 PARENT
+func vtodoParentUID(vtodo *ical.Component) string {
+	for _, prop := range vtodo.PropsNamed("RELATED-TO") {
+		if reltype := strings.ToUpper(prop.Param("RELTYPE")); reltype == "" || reltype == "PARENT" {
+			return ical.UnescapeText(strings.TrimSpace(prop.Value))
+		}
+	}
+	return ""
+}
+
+// ImportCalendar 从 iCalendar 数据导入待办事项到收件箱，已存在的 UID 及已取消的任务跳过；
+// 每个 VTODO 单独导入，部分失败不影响其余任务
+func (s *todoService) ImportCalendar(ctx context.Context, userID uint, r io.Reader) (*response.CalendarImportResponse, error) {
+	root, err := ical.Parse(r)
+	if err != nil {
+		return nil, errors.New("日历文件无效: " + err.Error())
+	}
+	if root.Name != ical.ComponentCalendar {
+		return nil, errors.New("日历文件无效: 缺少 VCALENDAR")
+	}
+	vtodos := root.Children(ical.ComponentTodo)
+	if len(vtodos) == 0 {
+		return nil, errors.New("日历文件中没有待办事项")
+	}
+	if len(vtodos) > maxImportTodos {
+		return nil, fmt.Errorf("单次最多导入 %d 个待办事项", maxImportTodos)
+	}
+
+	existing, err := s.existingUIDs(ctx, userID, vtodos)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.CalendarImportResponse{Total: len(vtodos), Items: make([]response.CalendarImportItem, len(vtodos))}
+	var candidates []importCandidate
+	seen := make(map[string]bool)
+	for i, vtodo := range vtodos {
+		item := &result.Items[i]
+		item.Index = i + 1
+		item.UID = strings.TrimSpace(vtodo.Text("UID"))
+		item.Title = vtodo.Text("SUMMARY")
+
+		switch {
+		case strings.EqualFold(strings.TrimSpace(vtodo.Text("STATUS")), "CANCELLED"):
+			item.Result, item.Message = response.ImportResultSkipped, "已取消的任务不导入"
+			continue
+		case item.UID != "" && (existing[item.UID] != nil || seen[item.UID]):
+			item.Result, item.Message = response.ImportResultSkipped, "待办事项已存在"
+			continue
+		}
+
+		todo, notes, err := vtodoToTodo(vtodo, userID)
+		if err != nil {
+			item.Result, item.Message = response.ImportResultFailed, err.Error()
+			continue
+		}
+		if item.UID != "" {
+			seen[item.UID] = true
+			// 本系统生成的 UID 对应的任务已被删除时按新任务导入，使用新的ID生成 UID
+			if _, own := parseTodoUID(item.UID); !own {
+				uid := item.UID
+				todo.ICalUID = &uid
+			}
+		}
+		item.Message = strings.Join(notes, "；")
+		candidates = append(candidates, importCandidate{
+			item:      item,
+			todo:      todo,
+			tags:      vtodoTags(vtodo),
+			parentUID: vtodoParentUID(vtodo),
+		})
+	}
+
+	// 先导入顶层任务，再导入子任务，以便子任务关联本次导入的父任务
+	parents := existing
+	tags := make(map[string]*model.Tag)
+	positions := make(map[uint]int)
+	for _, pass := range []bool{false, true} {
+		for _, candidate := range candidates {
+			if (candidate.parentUID != "") != pass {
+				continue
+			}
+			if pass {
+				s.linkImportedParent(ctx, &candidate, parents, positions)
+			}
+			if err := s.createImportedTodo(ctx, userID, &candidate, tags); err != nil {
+				candidate.item.Result, candidate.item.Message = response.ImportResultFailed, err.Error()
+				continue
+			}
+			if candidate.item.UID != "" && candidate.todo.ParentID == nil {
+				parents[candidate.item.UID] = candidate.todo
+			}
+		}
+	}
+
+	for _, item := range result.Items {
+		switch item.Result {
+		case response.ImportResultCreated:
+			result.Created++
+		case response.ImportResultSkipped:
+			result.Skipped++
+		case response.ImportResultFailed:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// existingUIDs 查询文件中已存在于系统中的 UID：本系统生成的 UID 按ID查找，
+// 其余(包括 CalDAV 客户端沿用的本系统 UID)按记录的原始 UID 查找
+func (s *todoService) existingUIDs(ctx context.Context, userID uint, vtodos []*ical.Component) (map[string]*model.Todo, error) {
+	var ids []uint
+	var uids []string
+	for _, vtodo := range vtodos {
+		uid := strings.TrimSpace(vtodo.Text("UID"))
+		if uid == "" {
+			continue
+		}
+		if id, own := parseTodoUID(uid); own {
+			ids = append(ids, id)
+		}
+		uids = append(uids, uid)
+	}
+
+	existing := make(map[string]*model.Todo)
+	owned, err := s.todoRepo.GetByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	imported, err := s.todoRepo.GetByICalUIDs(ctx, userID, uids)
+	if err != nil {
+		return nil, err
+	}
+	for _, todo := range append(owned, imported...) {
+		existing[todoUID(&todo)] = &todo
+	}
+	return existing, nil
+}
+
+// linkImportedParent 将子任务关联到父任务，父任务不存在或本身是子任务时作为顶层任务导入
+func (s *todoService) linkImportedParent(ctx context.Context, candidate *importCandidate,
+	parents map[string]*model.Todo, positions map[uint]int) {
+	parent, ok := parents[candidate.parentUID]
+	if !ok || model.IsSubtask(parent) {
+		candidate.addNote("未找到父任务，已作为顶层任务导入")
+		return
+	}
+
+	position, ok := positions[parent.ID]
+	if !ok {
+		subtasks, err := s.todoRepo.GetSubtasks(ctx, parent.ID)
+		if err != nil {
+			candidate.addNote("未找到父任务，已作为顶层任务导入")
+			return
+		}
+		position = len(subtasks)
+	}
+	positions[parent.ID] = position + 1
+
+	candidate.todo.ParentID = &parent.ID
+	candidate.todo.ProjectID = parent.ProjectID
+	candidate.todo.Position = position
+}
+
+// addNote 追加导入提示
+func (c *importCandidate) addNote(note string) {
+	if c.item.Message != "" {
+		c.item.Message += "；"
+	}
+	c.item.Message += note
+}
+
+// createImportedTodo 创建导入的待办事项并关联标签，不存在的标签自动创建
+func (s *todoService) createImportedTodo(ctx context.Context, userID uint, candidate *importCandidate, tags map[string]*model.Tag) error {
+	todo := candidate.todo
+	if err := s.todoRepo.Create(ctx, todo); err != nil {
+		return err
+	}
+	candidate.item.Result = response.ImportResultCreated
+	candidate.item.TodoID = &todo.ID
+	s.recordHistory(ctx, actionEvent(todo.ID, userID, model.HistoryActionCreated))
+	s.indexTodos(ctx, todo)
+
+	if err := s.setTodoTags(ctx, todo, userID, candidate.tags, tags); err != nil {
+		candidate.addNote("标签导入失败")
+	}
+	return nil
+}
+
+// resolveTags 按名称查找用户的标签，不存在的自动创建；cache 用于在多次调用间复用查询结果
+func (s *todoService) resolveTags(ctx context.Context, userID uint, names []string, cache map[string]*model.Tag) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tag, ok := cache[name]
+		if !ok {
+			var err error
+			tag, err = s.tagRepo.GetByName(ctx, userID, name)
+			if err != nil {
+				return nil, err
+			}
+			if tag == nil {
+				tag = &model.Tag{UserID: userID, Name: name}
+				if err := s.tagRepo.Create(ctx, tag); err != nil {
+					return nil, err
+				}
+			}
+			cache[name] = tag
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, nil
+}
+
+// setTodoTags 将待办事项的标签替换为指定名称的标签，并更新 todo.Tags
+func (s *todoService) setTodoTags(ctx context.Context, todo *model.Todo, userID uint, names []string, cache map[string]*model.Tag) error {
+	tags, err := s.resolveTags(ctx, todo.UserID, names, cache)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[uint]bool, len(tags))
+	var attach []uint
+	for _, tag := range tags {
+		keep[tag.ID] = true
+		attach = append(attach, tag.ID)
+	}
+	var detach []uint
+	for _, tag := range todo.Tags {
+		if !keep[tag.ID] {
+			detach = append(detach, tag.ID)
+		}
+	}
+	if err := s.tagRepo.DetachFromTodo(ctx, todo.ID, detach); err != nil {
+		return err
+	}
+	if err := s.tagRepo.AttachToTodo(ctx, todo.ID, attach); err != nil {
+		return err
+	}
+
+	s.recordHistory(ctx, tagChange(todo.ID, userID, todo.Tags, tags)...)
+	todo.Tags = tags
+	return nil
+}
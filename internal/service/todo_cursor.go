@@ -0,0 +1,41 @@
+package service
+
+import (
+	"TODO_API/internal/repository"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// todoCursor 游标内容，对客户端不透明，记录排序方式以防与其他排序方式混用
+type todoCursor struct {
+	Sort     string   `json:"s"`
+	Keys     []string `json:"k"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// encodeCursor 生成游标，backward 为 true 表示获取该位置之前的数据
+func encodeCursor(fields []repository.SortField, keys []string, backward bool) string {
+	data, _ := json.Marshal(todoCursor{
+		Sort:     repository.FormatTodoSort(fields),
+		Keys:     keys,
+		Backward: backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor 解析游标并校验与当前排序方式一致
+func decodeCursor(text string, fields []repository.SortField) (*todoCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return nil, errors.New("游标无效")
+	}
+	var cursor todoCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Keys) == 0 {
+		return nil, errors.New("游标无效")
+	}
+	if cursor.Sort != repository.FormatTodoSort(fields) {
+		return nil, errors.New("游标与排序方式不匹配")
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"context"
+	"errors"
+)
+
+// checkBlockersFinished 存在未完成的前置任务时不能开始或完成
+func (s *todoService) checkBlockersFinished(ctx context.Context, ids ...uint) error {
+	blocked, err := s.dependencyRepo.GetBlockedIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if len(blocked) > 0 {
+		return errors.New("存在未完成的前置任务")
+	}
+	return nil
+}
+
+// createsCycle 添加“todoID 被 blockerID 阻塞”后是否形成环，即 blockerID 是否已直接或间接被 todoID 阻塞
+func createsCycle(dependencies []model.TodoDependency, todoID, blockerID uint) bool {
+	blockers := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		blockers[dependency.TodoID] = append(blockers[dependency.TodoID], dependency.BlockerID)
+	}
+
+	visited := make(map[uint]bool)
+	stack := []uint{blockerID}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == todoID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, blockers[id]...)
+	}
+	return false
+}
+
+// GetDependencies 获取待办事项的前置任务及被其阻塞的任务
+func (s *todoService) GetDependencies(ctx context.Context, id, userID uint) (*response.TodoDependenciesResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDependencies(ctx, todo)
+}
+
+// buildDependencies 生成依赖关系响应
+func (s *todoService) buildDependencies(ctx context.Context, todo *model.Todo) (*response.TodoDependenciesResponse, error) {
+	blockers, err := s.dependencyRepo.GetBlockers(ctx, todo.ID)
+	if err != nil {
+		return nil, err
+	}
+	blocking, err := s.dependencyRepo.GetBlocking(ctx, todo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.TodoDependenciesResponse{
+		TodoID:    todo.ID,
+		BlockedBy: make([]response.TodoResponse, len(blockers)),
+		Blocks:    make([]response.TodoResponse, len(blocking)),
+	}
+	for i := range blockers {
+		result.BlockedBy[i] = *s.todoToResponse(&blockers[i])
+		if !model.IsCompleted(&blockers[i]) {
+			result.IsBlocked = true
+		}
+	}
+	for i := range blocking {
+		result.Blocks[i] = *s.todoToResponse(&blocking[i])
+	}
+	if err := s.fillTodoDetails(ctx, result.BlockedBy); err != nil {
+		return nil, err
+	}
+	if err := s.fillTodoDetails(ctx, result.Blocks); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AddDependency 添加前置任务：blockerID 完成前 id 不能开始或完成。两者须属于同一所有者，且不能形成循环依赖
+func (s *todoService) AddDependency(ctx context.Context, id, userID, blockerID uint) (*response.TodoDependenciesResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if blockerID == todo.ID {
+		return nil, errors.New("不能依赖自身")
+	}
+	blocker, err := s.todoRepo.GetByID(ctx, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	if blocker == nil || blocker.UserID != todo.UserID {
+		return nil, errors.New("前置任务不存在")
+	}
+
+	//锁定所有者后再检查与创建，避免并发添加相反方向的依赖时都通过环检测
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.dependencyRepo.LockOwner(ctx, todo.UserID); err != nil {
+			return err
+		}
+		exists, err := s.dependencyRepo.Exists(ctx, todo.ID, blocker.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("依赖关系已存在")
+		}
+		dependencies, err := s.dependencyRepo.GetByUserID(ctx, todo.UserID)
+		if err != nil {
+			return err
+		}
+		if createsCycle(dependencies, todo.ID, blocker.ID) {
+			return errors.New("依赖关系会形成循环")
+		}
+		dependency := &model.TodoDependency{TodoID: todo.ID, BlockerID: blocker.ID, UserID: todo.UserID}
+		return s.dependencyRepo.Create(ctx, dependency)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDependencies(ctx, todo)
+}
+
+// RemoveDependency 移除前置任务
+func (s *todoService) RemoveDependency(ctx context.Context, id, userID, blockerID uint) error {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return err
+	}
+	ok, err := s.dependencyRepo.Delete(ctx, todo.ID, blockerID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("依赖关系不存在")
+	}
+	return nil
+}
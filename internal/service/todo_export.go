@@ -0,0 +1,270 @@
+package service
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportBatchSize 导出时每批查询的数量
+const exportBatchSize = 200
+
+// transferFields 导出及导入使用的字段，CSV 表头按此顺序输出
+var transferFields = []string{
+	"id", "parent_id", "project_id", "title", "description", "status", "priority",
+	"due_date", "recurrence", "tags", "completed_at", "created_at", "updated_at",
+}
+
+// todoRecord JSON 导出的单条记录
+type todoRecord struct {
+	ID          uint       `json:"id"`
+	ParentID    *uint      `json:"parent_id"`
+	ProjectID   *uint      `json:"project_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      uint8      `json:"status"`
+	Priority    uint8      `json:"priority"`
+	DueDate     *time.Time `json:"due_date"`
+	Recurrence  string     `json:"recurrence"`
+	Tags        []string   `json:"tags"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+func newTodoRecord(todo *model.Todo) *todoRecord {
+	record := &todoRecord{
+		ID:          todo.ID,
+		ParentID:    todo.ParentID,
+		ProjectID:   todo.ProjectID,
+		Title:       todo.Title,
+		Status:      uint8(todo.Status),
+		Priority:    uint8(todo.Priority),
+		DueDate:     todo.DueDate,
+		Tags:        make([]string, len(todo.Tags)),
+		CompletedAt: todo.CompletedAt,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+	}
+	if todo.Description != nil {
+		record.Description = *todo.Description
+	}
+	if todo.Recurrence != nil {
+		record.Recurrence = *todo.Recurrence
+	}
+	for i := range todo.Tags {
+		record.Tags[i] = todo.Tags[i].Name
+	}
+	return record
+}
+
+// todoEncoder 按格式逐条输出待办事项
+type todoEncoder interface {
+	Begin() error
+	Encode(todo *model.Todo) error
+	End() error
+}
+
+// newTodoEncoder 创建导出格式对应的编码器
+func (s *todoService) newTodoEncoder(format string, w io.Writer) (todoEncoder, error) {
+	switch format {
+	case "json":
+		return &jsonTodoEncoder{w: w}, nil
+	case "csv":
+		return &csvTodoEncoder{w: csv.NewWriter(w)}, nil
+	case "md":
+		return &markdownTodoEncoder{w: w, s: s}, nil
+	default:
+		return nil, errors.New("不支持的导出格式")
+	}
+}
+
+// ExportTodos 按格式流式导出用户的全部待办事项(含子任务)，分批查询并逐条写出，
+// 第一批查询成功之前不会写入任何内容
+func (s *todoService) ExportTodos(ctx context.Context, userID uint, format string, w io.Writer) error {
+	encoder, err := s.newTodoEncoder(format, w)
+	if err != nil {
+		return err
+	}
+
+	var afterID uint
+	for first := true; ; first = false {
+		todos, err := s.todoRepo.GetBatchByUserID(ctx, userID, afterID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		if first {
+			if err := encoder.Begin(); err != nil {
+				return err
+			}
+		}
+		for i := range todos {
+			if err := encoder.Encode(&todos[i]); err != nil {
+				return err
+			}
+		}
+		if len(todos) < exportBatchSize {
+			break
+		}
+		afterID = todos[len(todos)-1].ID
+	}
+	return encoder.End()
+}
+
+// jsonTodoEncoder 输出 JSON 数组
+type jsonTodoEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonTodoEncoder) Begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonTodoEncoder) Encode(todo *model.Todo) error {
+	data, err := json.Marshal(newTodoRecord(todo))
+	if err != nil {
+		return err
+	}
+	separator := "\n"
+	if e.count > 0 {
+		separator = ",\n"
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonTodoEncoder) End() error {
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+// csvTodoEncoder 输出带表头的 CSV，时间为 RFC 3339 格式，多个标签以逗号分隔
+type csvTodoEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvTodoEncoder) Begin() error {
+	return e.w.Write(transferFields)
+}
+
+func (e *csvTodoEncoder) Encode(todo *model.Todo) error {
+	record := newTodoRecord(todo)
+	return e.w.Write([]string{
+		strconv.FormatUint(uint64(record.ID), 10),
+		formatOptionalID(record.ParentID),
+		formatOptionalID(record.ProjectID),
+		record.Title,
+		record.Description,
+		strconv.Itoa(int(record.Status)),
+		strconv.Itoa(int(record.Priority)),
+		formatOptionalTime(record.DueDate),
+		record.Recurrence,
+		strings.Join(record.Tags, ","),
+		formatOptionalTime(record.CompletedAt),
+		formatOptionalTime(record.CreatedAt),
+		formatOptionalTime(record.UpdatedAt),
+	})
+}
+
+func (e *csvTodoEncoder) End() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// markdownTodoEncoder 输出任务列表，便于阅读，不支持导入
+type markdownTodoEncoder struct {
+	w     io.Writer
+	s     *todoService
+	count int
+}
+
+func (e *markdownTodoEncoder) Begin() error {
+	_, err := fmt.Fprintf(e.w, "# 待办事项\n\n导出时间: %s\n\n", time.Now().Format("2006-01-02 15:04"))
+	return err
+}
+
+func (e *markdownTodoEncoder) Encode(todo *model.Todo) error {
+	e.count++
+	check := " "
+	if model.IsCompleted(todo) {
+		check = "x"
+	}
+
+	details := []string{
+		"ID: " + strconv.FormatUint(uint64(todo.ID), 10),
+		"状态: " + getStatusText(todo.Status),
+		"优先级: " + e.s.getPriorityText(todo.Priority),
+	}
+	if todo.ParentID != nil {
+		details = append(details, "父任务: "+strconv.FormatUint(uint64(*todo.ParentID), 10))
+	}
+	if todo.DueDate != nil {
+		details = append(details, "截止: "+todo.DueDate.Format("2006-01-02 15:04"))
+	}
+	if todo.Recurrence != nil {
+		details = append(details, "重复: "+markdownEscape(*todo.Recurrence))
+	}
+	if len(todo.Tags) > 0 {
+		names := make([]string, len(todo.Tags))
+		for i := range todo.Tags {
+			names[i] = markdownEscape(todo.Tags[i].Name)
+		}
+		details = append(details, "标签: "+strings.Join(names, ", "))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "- [%s] %s — %s\n", check, markdownEscape(todo.Title), strings.Join(details, " · "))
+	if todo.Description != nil && *todo.Description != "" {
+		// 描述作为列表项的续行输出
+		for _, line := range strings.Split(strings.ReplaceAll(*todo.Description, "\r\n", "\n"), "\n") {
+			b.WriteString("  " + markdownEscape(line) + "\n")
+		}
+	}
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *markdownTodoEncoder) End() error {
+	if e.count == 0 {
+		_, err := io.WriteString(e.w, "暂无待办事项\n")
+		return err
+	}
+	return nil
+}
+
+// markdownEscaper 转义会被解释为 Markdown 语法的字符
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`, "~", `\~`,
+)
+
+func markdownEscape(text string) string {
+	return markdownEscaper.Replace(text)
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/logger"
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// historyValue 将字段值格式化为变更记录中的字符串，空值返回 nil
+func historyValue(value any) *string {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case *string:
+		if v == nil {
+			return nil
+		}
+		text = *v
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		text = v.Format(time.RFC3339)
+	case *uint:
+		if v == nil {
+			return nil
+		}
+		text = strconv.FormatUint(uint64(*v), 10)
+	case model.TodoStatus:
+		text = strconv.Itoa(int(v))
+	case model.TodosPriority:
+		text = strconv.Itoa(int(v))
+	case []model.Tag:
+		names := make([]string, len(v))
+		for i := range v {
+			names[i] = v[i].Name
+		}
+		text = strings.Join(names, ",")
+	}
+	return &text
+}
+
+// sameValue 比较两个格式化后的字段值
+func sameValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// todoChanges 对比修改前后的待办事项，为每个变化的字段生成一条 updated 记录
+func todoChanges(before, after *model.Todo, userID uint) []model.TodoHistory {
+	fields := []struct {
+		name     string
+		old, new any
+	}{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"status", before.Status, after.Status},
+		{"status_id", before.StatusID, after.StatusID},
+		{"priority", before.Priority, after.Priority},
+		{"due_date", before.DueDate, after.DueDate},
+		{"project_id", before.ProjectID, after.ProjectID},
+		{"recurrence", before.Recurrence, after.Recurrence},
+	}
+
+	var events []model.TodoHistory
+	for _, field := range fields {
+		oldValue, newValue := historyValue(field.old), historyValue(field.new)
+		if sameValue(oldValue, newValue) {
+			continue
+		}
+		events = append(events, model.TodoHistory{
+			TodoID:   after.ID,
+			UserID:   userID,
+			Action:   model.HistoryActionUpdated,
+			Field:    field.name,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+	return events
+}
+
+// tagChange 标签变化生成的记录，未变化时返回 nil
+func tagChange(todoID, userID uint, before, after []model.Tag) []model.TodoHistory {
+	oldValue, newValue := historyValue(before), historyValue(after)
+	if sameValue(oldValue, newValue) {
+		return nil
+	}
+	return []model.TodoHistory{{
+		TodoID:   todoID,
+		UserID:   userID,
+		Action:   model.HistoryActionUpdated,
+		Field:    "tags",
+		OldValue: oldValue,
+		NewValue: newValue,
+	}}
+}
+
+// actionEvent 生成不针对具体字段的记录，如创建、删除
+func actionEvent(todoID, userID uint, action string) model.TodoHistory {
+	return model.TodoHistory{TodoID: todoID, UserID: userID, Action: action}
+}
+
+// recordHistory 写入变更记录，记录失败不影响已完成的修改，只记录日志
+func (s *todoService) recordHistory(ctx context.Context, events ...model.TodoHistory) {
+	if err := s.historyRepo.Create(ctx, events); err != nil {
+		logger.Error("写入待办事项变更记录失败", zap.Int("events", len(events)), zap.Error(err))
+	}
+}
+
+// historyToResponse 将TodoHistory模型转换为响应格式
+func historyToResponse(event *model.TodoHistory) response.TodoHistoryResponse {
+	return response.TodoHistoryResponse{
+		ID:        event.ID,
+		TodoID:    event.TodoID,
+		UserID:    event.UserID,
+		Username:  event.User.Username,
+		Action:    event.Action,
+		Field:     event.Field,
+		OldValue:  event.OldValue,
+		NewValue:  event.NewValue,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// GetHistory 获取待办事项的变更记录
+func (s *todoService) GetHistory(ctx context.Context, id, userID uint) ([]response.TodoHistoryResponse, error) {
+	if _, err := s.getAuthorizedTodo(ctx, id, userID, ActionView); err != nil {
+		return nil, err
+	}
+
+	events, err := s.historyRepo.GetByTodoID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	historyResponses := make([]response.TodoHistoryResponse, len(events))
+	for i := range events {
+		historyResponses[i] = historyToResponse(&events[i])
+	}
+	return historyResponses, nil
+}
@@ -0,0 +1,510 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/repository"
+	"TODO_API/pkg/recurrence"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxImportFileSize 导入文件最大字节数
+	maxImportFileSize = 5 << 20
+	// maxProjectNameLength 项目名最大字符数
+	maxProjectNameLength = 100
+)
+
+// importRow 从文件中读出的一行，字段值统一为字符串
+type importRow struct {
+	row    int
+	fields map[string]string // 为 nil 表示该行无法解析
+	tags   []string
+	errs   []response.TodoImportError
+}
+
+// addError 记录该行某个字段的错误
+func (r *importRow) addError(field, message string) {
+	r.errs = append(r.errs, response.TodoImportError{Row: r.row, Field: field, Message: message})
+}
+
+// importedTodo 校验后的一行
+type importedTodo struct {
+	row       *importRow
+	todo      *model.Todo
+	tags      []string
+	sourceID  string // 文件中的 id，供同一文件中的子任务引用
+	parentRef string // 文件中的 parent_id
+	project   string // 文件中的项目名，project_id 为空时使用
+	parent    int    // 父任务在同一文件中的下标，-1 表示没有
+}
+
+// openImportFile 检查文件大小并打开上传的文件
+func openImportFile(file *multipart.FileHeader) (multipart.File, error) {
+	if file.Size == 0 {
+		return nil, errors.New("文件不能为空")
+	}
+	if file.Size > maxImportFileSize {
+		return nil, errors.New("文件大小超出限制")
+	}
+	return file.Open()
+}
+
+// importFormat 确定导入格式，未指定时按文件扩展名判断
+func importFormat(format, filename string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return "json", nil
+	case ".csv":
+		return "csv", nil
+	default:
+		return "", errors.New("无法识别文件格式，请指定 format")
+	}
+}
+
+// ImportTodos 从 JSON 或 CSV 文件导入待办事项。先逐行校验，任一行有错误时不写入任何数据并返回
+// 全部错误；校验通过且不是 dry_run 时在一个事务中写入
+func (s *todoService) ImportTodos(ctx context.Context, userID uint, req *request.ImportTodosRequest,
+	file *multipart.FileHeader) (*response.TodoImportResponse, error) {
+	format, err := importFormat(req.Format, file.Filename)
+	if err != nil {
+		return nil, err
+	}
+	src, err := openImportFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	var rows []*importRow
+	if format == "csv" {
+		rows, err = readCSVRows(src)
+	} else {
+		rows, err = readJSONRows(src)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.importRows(ctx, userID, rows, req.DryRun)
+}
+
+// importRows 校验并写入读出的行，导入与迁移共用
+func (s *todoService) importRows(ctx context.Context, userID uint, rows []*importRow, dryRun bool) (*response.TodoImportResponse, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("文件中没有待办事项")
+	}
+	if len(rows) > maxImportTodos {
+		return nil, fmt.Errorf("单次最多导入 %d 个待办事项", maxImportTodos)
+	}
+
+	candidates, err := s.validateImportRows(ctx, userID, rows)
+	if err != nil {
+		return nil, err
+	}
+	result := &response.TodoImportResponse{DryRun: dryRun, Total: len(rows), Errors: []response.TodoImportError{}}
+	for _, row := range rows {
+		if len(row.errs) == 0 {
+			result.Valid++
+		}
+		result.Errors = append(result.Errors, row.errs...)
+	}
+	if len(result.Errors) > 0 || dryRun {
+		return result, nil
+	}
+
+	items := importItems(candidates)
+	if err := s.todoRepo.Import(ctx, userID, items); err != nil {
+		return nil, err
+	}
+	todos := make([]*model.Todo, len(items))
+	events := make([]model.TodoHistory, len(items))
+	for i, item := range items {
+		todos[i] = item.Todo
+		events[i] = actionEvent(item.Todo.ID, userID, model.HistoryActionCreated)
+	}
+	s.recordHistory(ctx, events...)
+	s.indexTodos(ctx, todos...)
+	result.Created = len(items)
+	return result, nil
+}
+
+// importItems 按父任务在前的顺序生成导入项，子任务按文件中的顺序排在父任务已有子任务之后
+func importItems(candidates []*importedTodo) []repository.TodoImportItem {
+	order := make([]int, 0, len(candidates))
+	for i, candidate := range candidates {
+		if candidate.parent < 0 {
+			order = append(order, i)
+		}
+	}
+	for i, candidate := range candidates {
+		if candidate.parent >= 0 {
+			order = append(order, i)
+		}
+	}
+
+	positions := make(map[int]int)
+	index := make(map[int]int, len(candidates))
+	items := make([]repository.TodoImportItem, len(order))
+	for n, i := range order {
+		candidate := candidates[i]
+		index[i] = n
+		item := repository.TodoImportItem{
+			Todo:        candidate.todo,
+			TagNames:    candidate.tags,
+			ProjectName: candidate.project,
+			Parent:      -1,
+		}
+		if candidate.parent >= 0 {
+			item.Parent = index[candidate.parent]
+			candidate.todo.Position = positions[candidate.parent]
+			positions[candidate.parent]++
+		}
+		items[n] = item
+	}
+	return items
+}
+
+// readJSONRows 读取 JSON 数组，每个元素为一个对象，字段与导出格式相同
+func readJSONRows(r io.Reader) ([]*importRow, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil || token != json.Delim('[') {
+		return nil, errors.New("文件格式无效: 需要 JSON 数组")
+	}
+
+	var rows []*importRow
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return nil, errors.New("文件格式无效: " + err.Error())
+		}
+		row := &importRow{row: len(rows) + 1, fields: make(map[string]string)}
+		rows = append(rows, row)
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil || object == nil {
+			row.fields = nil
+			row.addError("", "需要 JSON 对象")
+			continue
+		}
+		for key, value := range object {
+			if key == "tags" {
+				row.tags = jsonTags(row, value)
+				continue
+			}
+			var v any
+			valueDecoder := json.NewDecoder(bytes.NewReader(value))
+			valueDecoder.UseNumber()
+			valueDecoder.Decode(&v)
+			switch v := v.(type) {
+			case nil:
+			case string:
+				row.fields[key] = v
+			case json.Number:
+				row.fields[key] = v.String()
+			default:
+				row.addError(key, "字段类型无效")
+			}
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, errors.New("文件格式无效: " + err.Error())
+	}
+	return rows, nil
+}
+
+// jsonTags 标签可以是字符串数组，也可以是逗号分隔的字符串
+func jsonTags(row *importRow, value json.RawMessage) []string {
+	var names []string
+	if err := json.Unmarshal(value, &names); err == nil {
+		return names
+	}
+	var text *string
+	if err := json.Unmarshal(value, &text); err == nil {
+		if text == nil {
+			return nil
+		}
+		return strings.Split(*text, ",")
+	}
+	row.addError("tags", "标签必须为字符串数组")
+	return nil
+}
+
+// readCSVRows 读取带表头的 CSV，按列名取值，未知列被忽略
+func readCSVRows(r io.Reader) ([]*importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("文件格式无效: " + err.Error())
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+	if !slices.Contains(header, "title") {
+		return nil, errors.New("文件格式无效: 缺少 title 列")
+	}
+
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("文件格式无效: " + err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+		row := &importRow{row: line, fields: make(map[string]string)}
+		for i, value := range record {
+			if i >= len(header) || value == "" {
+				continue
+			}
+			if header[i] == "tags" {
+				row.tags = strings.Split(value, ",")
+				continue
+			}
+			row.fields[header[i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseImportTime 解析时间，支持 RFC 3339 及本地时间 2006-01-02 15:04[:05]，只有日期时截止到当天结束
+func parseImportTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("时间格式无效")
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// parseImportRow 校验一行并生成待办事项，错误记录在行上
+func (s *todoService) parseImportRow(ctx context.Context, userID uint, row *importRow,
+	projects map[uint]error) (*importedTodo, error) {
+	field := func(name string) string {
+		return strings.TrimSpace(row.fields[name])
+	}
+	todo := &model.Todo{UserID: userID, Priority: 1}
+	candidate := &importedTodo{
+		row:       row,
+		todo:      todo,
+		sourceID:  field("id"),
+		parentRef: field("parent_id"),
+		parent:    -1,
+	}
+	if row.fields == nil {
+		return candidate, nil
+	}
+
+	todo.Title = field("title")
+	switch {
+	case todo.Title == "":
+		row.addError("title", "标题不能为空")
+	case utf8.RuneCountInString(todo.Title) > maxTitleLength:
+		row.addError("title", fmt.Sprintf("标题不能超过 %d 个字符", maxTitleLength))
+	}
+	if description := row.fields["description"]; description != "" {
+		todo.Description = &description
+	}
+
+	if value := field("priority"); value != "" {
+		priority, err := strconv.ParseUint(value, 10, 8)
+		if err != nil || priority < 1 || priority > 4 {
+			row.addError("priority", "优先级必须为 1-4")
+		}
+		todo.Priority = model.TodosPriority(priority)
+	}
+
+	if value := field("due_date"); value != "" {
+		due, err := parseImportTime(value)
+		if err != nil {
+			row.addError("due_date", err.Error())
+		} else {
+			todo.DueDate = &due
+		}
+	}
+
+	if value := field("status"); value != "" {
+		status, err := strconv.ParseUint(value, 10, 8)
+		switch {
+		case err != nil || status > 2:
+			row.addError("status", "状态必须为 0-2")
+		case status == 1:
+			model.MarkProgress(todo)
+		case status == 2:
+			model.MarkCompleted(todo)
+		}
+	}
+	if value := field("completed_at"); value != "" && model.IsCompleted(todo) {
+		completedAt, err := parseImportTime(value)
+		if err != nil {
+			row.addError("completed_at", err.Error())
+		} else {
+			todo.CompletedAt = &completedAt
+		}
+	}
+
+	if value := field("recurrence"); value != "" {
+		rule, err := recurrence.Parse(value)
+		if err == nil {
+			err = rule.Validate()
+		}
+		switch {
+		case err != nil:
+			row.addError("recurrence", "重复规则无效: "+err.Error())
+		case todo.DueDate == nil:
+			row.addError("recurrence", "重复任务必须设置截止时间")
+		default:
+			text := rule.String()
+			todo.Recurrence = &text
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range row.tags {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxTagNameLength {
+			row.addError("tags", fmt.Sprintf("标签名不能超过 %d 个字符", maxTagNameLength))
+			continue
+		}
+		seen[name] = true
+		candidate.tags = append(candidate.tags, name)
+	}
+
+	if name := field("project"); name != "" && field("project_id") == "" {
+		if utf8.RuneCountInString(name) > maxProjectNameLength {
+			row.addError("project", fmt.Sprintf("项目名不能超过 %d 个字符", maxProjectNameLength))
+		}
+		candidate.project = name
+	}
+
+	if value := field("project_id"); value != "" && value != "0" {
+		projectID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			row.addError("project_id", "项目ID无效")
+			return candidate, nil
+		}
+		id := uint(projectID)
+		projectErr, ok := projects[id]
+		if !ok {
+			project, err := s.projectRepo.GetByID(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			//导入的标签属于当前用户，只能导入到自己的项目
+			if project == nil || project.UserID != userID {
+				projectErr = errors.New("项目不存在")
+			}
+			projects[id] = projectErr
+		}
+		if projectErr != nil {
+			row.addError("project_id", projectErr.Error())
+		} else {
+			todo.ProjectID = &id
+		}
+	}
+	return candidate, nil
+}
+
+// validateImportRows 校验全部行，并将 parent_id 解析为同一文件中的行或已有的顶层任务
+func (s *todoService) validateImportRows(ctx context.Context, userID uint, rows []*importRow) ([]*importedTodo, error) {
+	projects := make(map[uint]error)
+	candidates := make([]*importedTodo, len(rows))
+	sources := make(map[string]int)
+	for i, row := range rows {
+		candidate, err := s.parseImportRow(ctx, userID, row, projects)
+		if err != nil {
+			return nil, err
+		}
+		candidates[i] = candidate
+		if candidate.sourceID == "" {
+			continue
+		}
+		if _, ok := sources[candidate.sourceID]; ok {
+			row.addError("id", "ID 在文件中重复")
+			continue
+		}
+		sources[candidate.sourceID] = i
+	}
+
+	parents := make(map[uint]*model.Todo)
+	for i, candidate := range candidates {
+		ref := candidate.parentRef
+		if ref == "" || ref == "0" {
+			continue
+		}
+		if j, ok := sources[ref]; ok {
+			switch {
+			case j == i:
+				candidate.row.addError("parent_id", "不能将自身设为父任务")
+			case candidates[j].parentRef != "" && candidates[j].parentRef != "0":
+				candidate.row.addError("parent_id", "子任务不能再添加子任务")
+			default:
+				candidate.parent = j
+				candidate.todo.ProjectID = candidates[j].todo.ProjectID
+				candidate.project = candidates[j].project
+			}
+			continue
+		}
+
+		parentID, err := strconv.ParseUint(ref, 10, 64)
+		if err != nil {
+			candidate.row.addError("parent_id", "父任务ID无效")
+			continue
+		}
+		parent, ok := parents[uint(parentID)]
+		if !ok {
+			parent, err = s.todoRepo.GetByID(ctx, uint(parentID))
+			if err != nil {
+				return nil, err
+			}
+			if parent != nil && parent.UserID != userID {
+				parent = nil
+			}
+			parents[uint(parentID)] = parent
+		}
+		switch {
+		case parent == nil:
+			candidate.row.addError("parent_id", "父任务不存在")
+		case model.IsSubtask(parent):
+			candidate.row.addError("parent_id", "子任务不能再添加子任务")
+		default:
+			candidate.todo.ParentID = &parent.ID
+			candidate.todo.ProjectID = parent.ProjectID
+			candidate.project = ""
+			candidate.todo.Position = len(parent.Subtasks)
+			parent.Subtasks = append(parent.Subtasks, *candidate.todo)
+		}
+	}
+	return candidates, nil
+}
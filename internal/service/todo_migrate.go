@@ -0,0 +1,75 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/migration"
+	"context"
+	"mime/multipart"
+	"strconv"
+	"time"
+)
+
+// migrationRef 迁移记录的ID加上前缀，避免被当作已有待办事项的ID
+func migrationRef(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	return "src:" + ref
+}
+
+// migrationRows 将迁移记录转换为导入流程的行，行号为记录的序号
+func migrationRows(records []migration.Record) []*importRow {
+	rows := make([]*importRow, len(records))
+	for i, record := range records {
+		fields := map[string]string{
+			"id":          migrationRef(record.Ref),
+			"parent_id":   migrationRef(record.Parent),
+			"project":     record.Project,
+			"title":       record.Title,
+			"description": record.Description,
+			"status":      strconv.Itoa(record.Status),
+			"recurrence":  record.Recurrence,
+		}
+		if record.Priority > 0 {
+			fields["priority"] = strconv.Itoa(record.Priority)
+		}
+		if record.DueDate != nil {
+			fields["due_date"] = record.DueDate.Format(time.RFC3339)
+		}
+		if record.CompletedAt != nil {
+			fields["completed_at"] = record.CompletedAt.Format(time.RFC3339)
+		}
+		rows[i] = &importRow{row: i + 1, fields: fields, tags: record.Tags}
+	}
+	return rows
+}
+
+// MigrateTodos 从 Todoist、Trello、Microsoft To Do 的导出文件迁移待办事项，项目按名称匹配或创建。
+// 校验与写入与 ImportTodos 相同，无法映射的内容记录在报告中
+func (s *todoService) MigrateTodos(ctx context.Context, userID uint, source string, dryRun bool,
+	file *multipart.FileHeader) (*response.TodoMigrationResponse, error) {
+	src, err := openImportFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	result, err := migration.Parse(source, src, file.Filename)
+	if err != nil {
+		return nil, err
+	}
+	imported, err := s.importRows(ctx, userID, migrationRows(result.Records), dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &response.TodoMigrationResponse{
+		Source:             source,
+		TodoImportResponse: *imported,
+		Unmapped:           make([]response.MigrationIssue, len(result.Issues)),
+	}
+	for i, issue := range result.Issues {
+		report.Unmapped[i] = response.MigrationIssue{Item: issue.Item, Field: issue.Field, Message: issue.Message}
+	}
+	return report, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/jsonpatch"
+	"TODO_API/pkg/recurrence"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// todoPatchDocument 由待办事项生成被修补的文档，未设置的可选字段为 null
+func todoPatchDocument(todo *model.Todo) *request.TodoPatchDocument {
+	status, priority := uint8(todo.Status), uint8(todo.Priority)
+	doc := &request.TodoPatchDocument{
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      &status,
+		StatusID:    todo.StatusID,
+		Priority:    &priority,
+		DueDate:     todo.DueDate,
+		ProjectID:   todo.ProjectID,
+	}
+	if todo.Recurrence != nil {
+		if rule, err := recurrence.Parse(*todo.Recurrence); err == nil {
+			doc.Recurrence = &request.RecurrenceRequest{
+				Freq:      strings.ToLower(string(rule.Freq)),
+				Interval:  rule.Interval,
+				ByWeekday: rule.WeekdayCodes(),
+				Until:     rule.Until,
+				Count:     rule.Count,
+			}
+		}
+	}
+	return doc
+}
+
+// patchTodoDocument 将补丁应用到待办事项的文档，返回修补前后的文档。
+// 修补前的文档同样经过一次 JSON 编解码，与修补结果比较时不受时间精度等差异影响
+func patchTodoDocument(todo *model.Todo, contentType string, patch []byte) (*request.TodoPatchDocument, *request.TodoPatchDocument, error) {
+	original, err := json.Marshal(todoPatchDocument(todo))
+	if err != nil {
+		return nil, nil, err
+	}
+	var patched []byte
+	switch contentType {
+	case jsonpatch.MergePatchContentType:
+		patched, err = jsonpatch.MergePatch(original, patch)
+	case jsonpatch.JSONPatchContentType:
+		patched, err = jsonpatch.Apply(original, patch)
+	default:
+		return nil, nil, errors.New("不支持的补丁格式")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var before, after request.TodoPatchDocument
+	if err := json.Unmarshal(original, &before); err != nil {
+		return nil, nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&after); err != nil {
+		return nil, nil, errors.New("修补后的待办事项无效: " + err.Error())
+	}
+	if err := after.Validate(); err != nil {
+		return nil, nil, errors.New("修补后的待办事项无效: " + err.Error())
+	}
+	return &before, &after, nil
+}
+
+// fieldChanged 比较修补前后的字段值
+func fieldChanged(before, after any) bool {
+	return !sameValue(historyValue(before), historyValue(after))
+}
+
+// PatchTodo 以合并补丁(RFC 7396)或 JSON Patch(RFC 6902)局部更新待办事项，ifMatch 须匹配当前版本。
+// 修补结果校验通过后，只有变化的字段按与 UpdateTodo 相同的规则写入，null 或被删除的可选字段会被清除
+func (s *todoService) PatchTodo(ctx context.Context, id, userID uint, ifMatch, contentType string, patch []byte) (*response.TodoResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTodoIfMatch(ctx, todo, ifMatch); err != nil {
+		return nil, err
+	}
+	before, after, err := patchTodoDocument(todo, contentType, patch)
+	if err != nil {
+		return nil, err
+	}
+	prev := *todo
+
+	todo.Title = after.Title
+	todo.Description = nil
+	if after.Description != nil && *after.Description != "" {
+		todo.Description = after.Description
+	}
+	todo.Priority = model.TodosPriority(*after.Priority)
+	dueDateChanged := fieldChanged(before.DueDate, after.DueDate)
+	todo.DueDate = after.DueDate
+
+	beforeRule, _ := json.Marshal(before.Recurrence)
+	afterRule, _ := json.Marshal(after.Recurrence)
+	if !bytes.Equal(beforeRule, afterRule) {
+		todo.Recurrence = nil
+		if after.Recurrence != nil {
+			if todo.Recurrence, err = s.buildRecurrence(after.Recurrence, todo.DueDate); err != nil {
+				return nil, err
+			}
+		}
+	} else if todo.Recurrence != nil && todo.DueDate == nil {
+		return nil, errors.New("重复任务必须设置截止时间")
+	}
+
+	if fieldChanged(before.ProjectID, after.ProjectID) {
+		var projectID uint
+		if after.ProjectID != nil {
+			projectID = *after.ProjectID
+		}
+		if err := s.changeProject(ctx, todo, projectID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	//只传入变化的分类或自定义状态，由 resolveStatus 决定保留还是清除自定义状态
+	var status *uint8
+	if *after.Status != *before.Status {
+		status = after.Status
+	}
+	var statusID *uint
+	if fieldChanged(before.StatusID, after.StatusID) {
+		statusID = new(uint)
+		if after.StatusID != nil {
+			statusID = after.StatusID
+		}
+	}
+	if status != nil || statusID != nil {
+		target, customID, err := s.resolveStatus(ctx, todo, status, statusID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.changeStatus(ctx, todo, target, customID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(todoChanges(&prev, todo, userID)) == 0 {
+		return s.buildResponse(ctx, todo)
+	}
+	next, err := s.saveTodo(ctx, &prev, todo, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, todoChanges(&prev, todo, userID)...)
+	s.indexTodos(ctx, todo, next)
+	if dueDateChanged && todo.DueDate != nil {
+		if err := s.reminderRepo.RescheduleRelative(ctx, todo.ID, *todo.DueDate); err != nil {
+			return nil, err
+		}
+	}
+	return s.reloadResponse(ctx, todo.ID)
+}
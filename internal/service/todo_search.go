@@ -0,0 +1,138 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/internal/search"
+	"TODO_API/pkg/logger"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// indexBatchSize 重建索引时每批读取的待办事项数
+const indexBatchSize = 500
+
+// todoDocument 将待办事项转换为搜索文档
+func todoDocument(todo *model.Todo) search.Document {
+	doc := search.Document{ID: todo.ID, UserID: todo.UserID, Title: todo.Title}
+	if todo.Description != nil {
+		doc.Description = *todo.Description
+	}
+	return doc
+}
+
+// indexTodos 更新待办事项的搜索索引，失败时只记录日志
+func (s *todoService) indexTodos(ctx context.Context, todos ...*model.Todo) {
+	for _, todo := range todos {
+		if todo == nil {
+			continue
+		}
+		if err := s.searcher.Index(ctx, todoDocument(todo)); err != nil {
+			logger.Error("更新搜索索引失败", zap.Uint("todo_id", todo.ID), zap.Error(err))
+		}
+	}
+}
+
+// unindexTodos 从搜索索引中移除待办事项，失败时只记录日志
+func (s *todoService) unindexTodos(ctx context.Context, ids ...uint) {
+	if err := s.searcher.Remove(ctx, ids...); err != nil {
+		logger.Error("移除搜索索引失败", zap.Uints("todo_ids", ids), zap.Error(err))
+	}
+}
+
+// RebuildSearchIndex 全量重建搜索索引，供进程内索引在启动时调用
+func (s *todoService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	var afterID uint
+	count := 0
+	for {
+		todos, err := s.todoRepo.GetForIndex(ctx, afterID, indexBatchSize)
+		if err != nil {
+			return count, err
+		}
+		for i := range todos {
+			if err := s.searcher.Index(ctx, todoDocument(&todos[i])); err != nil {
+				return count, err
+			}
+		}
+		count += len(todos)
+		if len(todos) < indexBatchSize {
+			return count, nil
+		}
+		afterID = todos[len(todos)-1].ID
+	}
+}
+
+// matchTodoIDs 按关键词搜索用户的待办事项，返回全部命中的ID，用于列表筛选
+func (s *todoService) matchTodoIDs(ctx context.Context, userID uint, keyword string) ([]uint, error) {
+	query, err := search.ParseQuery(keyword)
+	if err != nil {
+		return nil, err
+	}
+	return s.searcher.Match(ctx, userID, query)
+}
+
+// SearchTodos 全文搜索用户的待办事项，按相关度排序并返回高亮片段
+func (s *todoService) SearchTodos(ctx context.Context, userID uint, req *request.SearchTodoRequest) (*response.SearchTodoResponse, error) {
+	query, err := search.ParseQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := int((req.Page - 1) * req.PageSize)
+	hits, total, err := s.searcher.Search(ctx, userID, query, offset, int(req.PageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	todos, err := s.todoRepo.GetByIDs(ctx, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	todoMap := make(map[uint]*model.Todo, len(todos))
+	for i := range todos {
+		todoMap[todos[i].ID] = &todos[i]
+	}
+
+	//按搜索结果的顺序返回，跳过索引中已失效的记录
+	results := make([]response.SearchResultResponse, 0, len(hits))
+	todosResponses := make([]response.TodoResponse, 0, len(hits))
+	for _, hit := range hits {
+		todo, ok := todoMap[hit.ID]
+		if !ok {
+			continue
+		}
+		doc := todoDocument(todo)
+		results = append(results, response.SearchResultResponse{
+			Score: hit.Score,
+			Highlights: response.SearchHighlights{
+				Title:       search.Highlight(doc.Title, query),
+				Description: search.Highlight(doc.Description, query),
+			},
+		})
+		todosResponses = append(todosResponses, *s.todoToResponse(todo))
+	}
+	if err := s.fillTodoDetails(ctx, todosResponses); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Todo = todosResponses[i]
+	}
+	totalPage := (uint(total) + req.PageSize - 1) / req.PageSize
+
+	return &response.SearchTodoResponse{
+		Query:   req.Query,
+		Results: results,
+		Pagination: response.Pagination{
+			Page:       req.Page,
+			PageSize:   req.PageSize,
+			Total:      uint(total),
+			TotalPages: totalPage,
+		},
+	}, nil
+}
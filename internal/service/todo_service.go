@@ -5,8 +5,14 @@ import (
 	"TODO_API/internal/app/dto/response"
 	"TODO_API/internal/domain/model"
 	"TODO_API/internal/repository"
+	"TODO_API/internal/search"
+	todofilter "TODO_API/pkg/filter"
+	"TODO_API/pkg/recurrence"
 	"context"
 	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
 	"time"
 )
 
@@ -15,19 +21,81 @@ type TodoService interface {
 	Create(ctx context.Context, userID uint, req *request.CreateTodoRequest) (*response.TodoResponse, error)
 	GetTodoByID(ctx context.Context, id, userID uint) (*response.TodoResponse, error)
 	GetTodos(ctx context.Context, userID uint, query *request.TodoQueryRequest) (*response.TodoListResponse, error)
-	UpdateTodo(ctx context.Context, id, userID uint, req *request.UpdateTodoRequest) (*response.TodoResponse, error)
-	DeleteTodo(ctx context.Context, id, userID uint) error
-	UpdateTodoStatus(ctx context.Context, id, userID uint, status uint8) (*response.TodoResponse, error)
-	BatchUpdateStatus(ctx context.Context, userID uint, req *request.BatchUpdateTodoRequest) error
+	UpdateTodo(ctx context.Context, id, userID uint, ifMatch string, req *request.UpdateTodoRequest) (*response.TodoResponse, error)
+	PatchTodo(ctx context.Context, id, userID uint, ifMatch, contentType string, patch []byte) (*response.TodoResponse, error)
+	DeleteTodo(ctx context.Context, id, userID uint, ifMatch string) error
+	DeleteProject(ctx context.Context, projectID, userID uint, deleteTodos bool) error
+	UpdateTodoStatus(ctx context.Context, id, userID uint, ifMatch string, req *request.UpdateTodoStatusRequest) (*response.TodoResponse, error)
+	BatchUpdateStatus(ctx context.Context, userID uint, req *request.BatchUpdateTodoRequest) (*response.BatchTodoResponse, error)
+	BatchTodos(ctx context.Context, userID uint, req *request.BatchTodoRequest) (*response.BatchTodoResponse, error)
+	AttachTags(ctx context.Context, id, userID uint, req *request.TodoTagsRequest) (*response.TodoResponse, error)
+	DetachTag(ctx context.Context, id, userID, tagID uint) (*response.TodoResponse, error)
+	CreateSubtask(ctx context.Context, parentID, userID uint, req *request.CreateTodoRequest) (*response.TodoResponse, error)
+	GetSubtasks(ctx context.Context, parentID, userID uint) ([]response.TodoResponse, error)
+	ReorderSubtasks(ctx context.Context, parentID, userID uint, req *request.ReorderSubtasksRequest) ([]response.TodoResponse, error)
+	GetOccurrences(ctx context.Context, id, userID uint, limit int) (*response.OccurrencesResponse, error)
+	StopRecurrence(ctx context.Context, id, userID uint) (*response.TodoResponse, error)
+	GetHistory(ctx context.Context, id, userID uint) ([]response.TodoHistoryResponse, error)
+	GetTrash(ctx context.Context, userID uint, query *request.TrashQueryRequest) (*response.TrashListResponse, error)
+	RestoreTodo(ctx context.Context, id, userID uint) (*response.TodoResponse, error)
+	PurgeTodo(ctx context.Context, id, userID uint) error
+	PurgeExpired(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+	SearchTodos(ctx context.Context, userID uint, req *request.SearchTodoRequest) (*response.SearchTodoResponse, error)
+	RebuildSearchIndex(ctx context.Context) (int, error)
+	GetStats(ctx context.Context, userID uint, req *request.TodoStatsRequest) (*response.TodoStatsResponse, error)
+	ExportCalendar(ctx context.Context, userID uint) ([]byte, error)
+	ImportCalendar(ctx context.Context, userID uint, r io.Reader) (*response.CalendarImportResponse, error)
+	ListCalendarObjects(ctx context.Context, userID uint) ([]CalendarObject, error)
+	GetCalendarObject(ctx context.Context, userID uint, name string) (*CalendarObject, error)
+	PutCalendarObject(ctx context.Context, userID uint, name string, r io.Reader, ifMatch, ifNoneMatch string) (bool, error)
+	DeleteCalendarObject(ctx context.Context, userID uint, name, ifMatch string) error
+	ExportTodos(ctx context.Context, userID uint, format string, w io.Writer) error
+	ImportTodos(ctx context.Context, userID uint, req *request.ImportTodosRequest, file *multipart.FileHeader) (*response.TodoImportResponse, error)
+	MigrateTodos(ctx context.Context, userID uint, source string, dryRun bool, file *multipart.FileHeader) (*response.TodoMigrationResponse, error)
+	ExportTodoTxt(ctx context.Context, userID uint) ([]byte, error)
+	SyncTodoTxt(ctx context.Context, userID uint, file *multipart.FileHeader) (*response.TodoTxtSyncResponse, error)
+	GetBoard(ctx context.Context, userID uint, query *request.BoardQueryRequest) (*response.BoardResponse, error)
+	MoveTodo(ctx context.Context, id, userID uint, ifMatch string, req *request.MoveTodoRequest) (*response.TodoResponse, error)
+	GetDependencies(ctx context.Context, id, userID uint) (*response.TodoDependenciesResponse, error)
+	AddDependency(ctx context.Context, id, userID, blockerID uint) (*response.TodoDependenciesResponse, error)
+	RemoveDependency(ctx context.Context, id, userID, blockerID uint) error
 }
 
 type todoService struct {
-	todoRepo repository.TodoRepository
+	todoRepo       repository.TodoRepository
+	tagRepo        repository.TagRepository
+	reminderRepo   repository.ReminderRepository
+	projectRepo    repository.ProjectRepository
+	commentRepo    repository.CommentRepository
+	historyRepo    repository.HistoryRepository
+	dependencyRepo repository.DependencyRepository
+	statusRepo     repository.StatusRepository
+	transactor     repository.Transactor
+	access         AccessControl
+	attachments    AttachmentService
+	searcher       search.Searcher
 }
 
 // NewTodoService 创建待办事项服务实例
-func NewTodoService(todoRepo repository.TodoRepository) TodoService {
-	return &todoService{todoRepo: todoRepo}
+func NewTodoService(todoRepo repository.TodoRepository, tagRepo repository.TagRepository,
+	reminderRepo repository.ReminderRepository, projectRepo repository.ProjectRepository,
+	commentRepo repository.CommentRepository, historyRepo repository.HistoryRepository,
+	dependencyRepo repository.DependencyRepository, statusRepo repository.StatusRepository,
+	transactor repository.Transactor, access AccessControl, attachments AttachmentService, searcher search.Searcher) TodoService {
+	return &todoService{
+		todoRepo:       todoRepo,
+		tagRepo:        tagRepo,
+		reminderRepo:   reminderRepo,
+		projectRepo:    projectRepo,
+		commentRepo:    commentRepo,
+		historyRepo:    historyRepo,
+		dependencyRepo: dependencyRepo,
+		statusRepo:     statusRepo,
+		transactor:     transactor,
+		access:         access,
+		attachments:    attachments,
+		searcher:       searcher,
+	}
 }
 
 // todoToResponse 将Todo模型转换为响应格式
@@ -40,26 +108,137 @@ func (s *todoService) todoToResponse(todo *model.Todo) *response.TodoResponse {
 	if todo.DueDate != nil && todo.Status != 2 {
 		isOverdue = todo.DueDate.Before(time.Now())
 	}
+	tags := make([]response.TagResponse, len(todo.Tags))
+	for i := range todo.Tags {
+		tags[i] = tagToResponse(&todo.Tags[i])
+	}
+
+	var deletedAt *time.Time
+	if todo.DeletedAt.Valid {
+		deletedAt = &todo.DeletedAt.Time
+	}
+
+	//子任务及完成进度，没有子任务时按自身状态计算
+	var subtasks []response.TodoResponse
+	var completedSubtasks uint
+	for i := range todo.Subtasks {
+		subtasks = append(subtasks, *s.todoToResponse(&todo.Subtasks[i]))
+		if todo.Subtasks[i].Status == 2 {
+			completedSubtasks++
+		}
+	}
+	var completionPercent uint
+	if len(todo.Subtasks) > 0 {
+		completionPercent = completedSubtasks * 100 / uint(len(todo.Subtasks))
+	} else if todo.Status == 2 {
+		completionPercent = 100
+	}
 
 	return &response.TodoResponse{
 		ID:           todo.ID,
 		UserID:       todo.UserID,
+		ProjectID:    todo.ProjectID,
+		ParentID:     todo.ParentID,
 		Title:        todo.Title,
 		Description:  description,
 		Status:       uint8(todo.Status),
-		StatusText:   s.getStatusText(todo.Status),
+		StatusID:     todo.StatusID,
+		StatusText:   getStatusText(todo.Status),
 		Priority:     uint8(todo.Priority),
 		PriorityText: s.getPriorityText(todo.Priority),
 		DueDate:      todo.DueDate,
 		CompletedAt:  todo.CompletedAt,
 		CreatedAt:    todo.CreatedAt,
 		UpdatedAt:    todo.UpdatedAt,
+		DeletedAt:    deletedAt,
+		Version:      todo.Version,
 		IsOverdue:    isOverdue,
+		Recurrence:   s.recurrenceToResponse(todo.Recurrence),
+		Tags:         tags,
+
+		Subtasks:          subtasks,
+		SubtaskCount:      uint(len(todo.Subtasks)),
+		CompletedSubtasks: completedSubtasks,
+		CompletionPercent: completionPercent,
+	}
+}
+
+// fillTodoDetails 填充待办事项及其子任务的评论数、是否被前置任务阻塞及自定义状态名称
+func (s *todoService) fillTodoDetails(ctx context.Context, todos []response.TodoResponse) error {
+	var ids, statusIDs []uint
+	var collect func(list []response.TodoResponse)
+	collect = func(list []response.TodoResponse) {
+		for i := range list {
+			ids = append(ids, list[i].ID)
+			if list[i].StatusID != nil {
+				statusIDs = append(statusIDs, *list[i].StatusID)
+			}
+			collect(list[i].Subtasks)
+		}
+	}
+	collect(todos)
+
+	counts, err := s.commentRepo.CountByTodoIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	blocked, err := s.dependencyRepo.GetBlockedIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	statuses, err := s.statusRepo.GetByIDs(ctx, statusIDs)
+	if err != nil {
+		return err
+	}
+	statusNames := make(map[uint]string, len(statuses))
+	for i := range statuses {
+		statusNames[statuses[i].ID] = statuses[i].Name
+	}
+	var assign func(list []response.TodoResponse)
+	assign = func(list []response.TodoResponse) {
+		for i := range list {
+			list[i].CommentCount = counts[list[i].ID]
+			list[i].IsBlocked = blocked[list[i].ID]
+			if list[i].StatusID != nil && statusNames[*list[i].StatusID] != "" {
+				list[i].StatusText = statusNames[*list[i].StatusID]
+			}
+			assign(list[i].Subtasks)
+		}
+	}
+	assign(todos)
+	return nil
+}
+
+// buildResponse 转换单个待办事项并填充评论数等信息
+func (s *todoService) buildResponse(ctx context.Context, todo *model.Todo) (*response.TodoResponse, error) {
+	todos := []response.TodoResponse{*s.todoToResponse(todo)}
+	if err := s.fillTodoDetails(ctx, todos); err != nil {
+		return nil, err
+	}
+	return &todos[0], nil
+}
+
+// recurrenceToResponse 转换重复规则
+func (s *todoService) recurrenceToResponse(text *string) *response.RecurrenceResponse {
+	if text == nil {
+		return nil
+	}
+	rule, err := recurrence.Parse(*text)
+	if err != nil {
+		return nil
+	}
+	return &response.RecurrenceResponse{
+		Rule:      rule.String(),
+		Freq:      strings.ToLower(string(rule.Freq)),
+		Interval:  rule.Interval,
+		ByWeekday: rule.WeekdayCodes(),
+		Until:     rule.Until,
+		Count:     rule.Count,
 	}
 }
 
 // statsToResponse 转换统计信息
-func (s *todoService) statsToResponse(stats *model.TodoStatistics) response.Statistics {
+func statsToResponse(stats *model.TodoStatistics) response.Statistics {
 	if stats == nil {
 		return response.Statistics{}
 	}
@@ -73,7 +252,7 @@ func (s *todoService) statsToResponse(stats *model.TodoStatistics) response.Stat
 }
 
 // getStatusText 获取状态文本
-func (s *todoService) getStatusText(status model.TodoStatus) string {
+func getStatusText(status model.TodoStatus) string {
 	switch status {
 	case 0:
 		return "待办"
@@ -102,71 +281,327 @@ func (s *todoService) getPriorityText(priority model.TodosPriority) string {
 	}
 }
 
+// getAuthorizedTodo 获取待办事项并检查当前用户的共享权限
+func (s *todoService) getAuthorizedTodo(ctx context.Context, id, userID uint, action Action) (*model.Todo, error) {
+	todo, err := s.todoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo == nil {
+		return nil, errors.New("待办事项不存在")
+	}
+
+	ok, err := s.access.CanAccessTodo(ctx, todo, userID, action)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, todoPermissionError(action)
+	}
+	return todo, nil
+}
+
+// changeProject 将待办事项移动到指定项目，0 表示收件箱；只能移动到所有者自己的项目中。
+// 子任务的项目跟随父任务，不能单独修改，父任务保存时由 saveTodo 同步子任务的项目
+func (s *todoService) changeProject(ctx context.Context, todo *model.Todo, projectID, userID uint) error {
+	if model.IsSubtask(todo) {
+		if (todo.ProjectID == nil && projectID == 0) || (todo.ProjectID != nil && *todo.ProjectID == projectID) {
+			return nil
+		}
+		return errors.New("子任务的项目跟随父任务，不能单独修改")
+	}
+	project, err := s.resolveProject(ctx, userID, projectID, ActionEdit)
+	if err != nil {
+		return err
+	}
+	if project != nil && project.UserID != todo.UserID {
+		return errors.New("项目不存在")
+	}
+	todo.ProjectID = nil
+	if project != nil {
+		todo.ProjectID = &project.ID
+	}
+	return nil
+}
+
+// resolveProject 获取项目并检查当前用户的共享权限，0 表示收件箱
+func (s *todoService) resolveProject(ctx context.Context, userID, projectID uint, action Action) (*model.Project, error) {
+	if projectID == 0 {
+		return nil, nil
+	}
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, errors.New("项目不存在")
+	}
+
+	ok, err := s.access.CanAccessProject(ctx, project, userID, action)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("项目不存在")
+	}
+	return project, nil
+}
+
+// buildRecurrence 根据请求生成 RRULE，以截止时间作为第一次发生时间
+func (s *todoService) buildRecurrence(req *request.RecurrenceRequest, dueDate *time.Time) (*string, error) {
+	if dueDate == nil {
+		return nil, errors.New("重复任务必须设置截止时间")
+	}
+
+	rule := &recurrence.Rule{
+		Freq:     recurrence.Frequency(strings.ToUpper(req.Freq)),
+		Interval: req.Interval,
+		Until:    req.Until,
+		Count:    req.Count,
+	}
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+	for _, code := range req.ByWeekday {
+		if weekday, ok := recurrence.ParseWeekday(code); ok {
+			rule.ByWeekday = append(rule.ByWeekday, weekday)
+		}
+	}
+	if rule.Freq == recurrence.Monthly {
+		rule.ByMonthDay = dueDate.Day()
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, errors.New("重复规则无效: " + err.Error())
+	}
+
+	text := rule.String()
+	return &text, nil
+}
+
+// scheduleNextOccurrence 重复任务完成时生成下一次实例，规则随之转移到新实例上，返回生成的实例。
+// 由 saveTodo 在写入已完成实例的同一事务中调用
+func (s *todoService) scheduleNextOccurrence(ctx context.Context, todo *model.Todo, userID uint) (*model.Todo, error) {
+	if todo.Recurrence == nil || todo.DueDate == nil {
+		return nil, nil
+	}
+	rule, err := recurrence.Parse(*todo.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	var next *model.Todo
+	nextDue, ok := rule.Next(*todo.DueDate)
+	if ok {
+		nextRule := rule.Advance().String()
+		next = &model.Todo{
+			UserID:      todo.UserID,
+			ProjectID:   todo.ProjectID,
+			ParentID:    todo.ParentID,
+			Title:       todo.Title,
+			Description: todo.Description,
+			Priority:    todo.Priority,
+			DueDate:     &nextDue,
+			Recurrence:  &nextRule,
+		}
+		if err := s.todoRepo.Create(ctx, next); err != nil {
+			return nil, err
+		}
+		s.recordHistory(ctx, actionEvent(next.ID, userID, model.HistoryActionCreated))
+
+		tagIDs := make([]uint, len(todo.Tags))
+		for i, tag := range todo.Tags {
+			tagIDs[i] = tag.ID
+		}
+		if err := s.tagRepo.AttachToTodo(ctx, next.ID, tagIDs); err != nil {
+			return nil, err
+		}
+
+		//相对提醒随实例一起延续
+		reminders, err := s.reminderRepo.GetByTodoID(ctx, todo.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, reminder := range reminders {
+			if !reminder.IsRelative() {
+				continue
+			}
+			err := s.reminderRepo.Create(ctx, &model.Reminder{
+				TodoID:        next.ID,
+				UserID:        next.UserID,
+				RemindAt:      nextDue.Add(-time.Duration(*reminder.OffsetMinutes) * time.Minute),
+				OffsetMinutes: reminder.OffsetMinutes,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	//已完成的实例不再重复，避免重新打开后再次完成时重复生成
+	todo.Recurrence = nil
+	return next, nil
+}
+
+// checkSubtasksFinished 父任务完成前要求所有子任务均已完成
+func (s *todoService) checkSubtasksFinished(ctx context.Context, todo *model.Todo) error {
+	if model.IsSubtask(todo) {
+		return nil
+	}
+	count, err := s.todoRepo.CountUnfinishedSubtasks(ctx, todo.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("存在未完成的子任务")
+	}
+	return nil
+}
+
 // CreateTodo 创建待办事项
 func (s *todoService) Create(ctx context.Context, userID uint, req *request.CreateTodoRequest) (*response.TodoResponse, error) {
 	todo := &model.Todo{
 		UserID:   userID,
 		Title:    req.Title,
-		Status:   model.TodoStatus(req.Status),
 		Priority: model.TodosPriority(req.Priority),
 		DueDate:  req.DueDate,
 	}
+	if err := model.Transition(todo, model.TodoStatus(req.Status)); err != nil {
+		return nil, err
+	}
 	if req.Description != "" {
 		todo.Description = &req.Description
 	}
+	if req.Recurrence != nil {
+		rule, err := s.buildRecurrence(req.Recurrence, req.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		todo.Recurrence = rule
+	}
+	if req.ProjectID != nil {
+		project, err := s.resolveProject(ctx, userID, *req.ProjectID, ActionEdit)
+		if err != nil {
+			return nil, err
+		}
+		//在共享项目中创建的待办事项归项目所有者
+		if project != nil {
+			todo.ProjectID = &project.ID
+			todo.UserID = project.UserID
+		}
+	}
 
 	if err := s.todoRepo.Create(ctx, todo); err != nil {
 		return nil, err
 	}
+	s.recordHistory(ctx, actionEvent(todo.ID, userID, model.HistoryActionCreated))
+	s.indexTodos(ctx, todo)
 
 	return s.todoToResponse(todo), nil
 }
 
 // GetTodoByID 根据ID获取待办事项
 func (s *todoService) GetTodoByID(ctx context.Context, id, userID uint) (*response.TodoResponse, error) {
-	todo, err := s.todoRepo.GetByID(ctx, id)
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionView)
 	if err != nil {
 		return nil, err
 	}
 
-	if todo == nil {
-		return nil, errors.New("待办事项不存在")
-	}
-
-	//检查权限
-	if todo.UserID != userID {
-		return nil, errors.New("无权限访问此待办事项")
-	}
-
-	return s.todoToResponse(todo), nil
+	return s.buildResponse(ctx, todo)
 }
 
 // GetTodos 获取待办事项列表
 func (s *todoService) GetTodos(ctx context.Context, userID uint, query *request.TodoQueryRequest) (*response.TodoListResponse, error) {
-	var statusPtr, priorityPtr *uint8
-	if query.Status != nil {
-		statusPtr = query.Status
+	filter := &repository.TodoFilter{
+		Status:    query.Status,
+		Priority:  query.Priority,
+		TagID:     query.TagID,
+		ProjectID: query.ProjectID,
+	}
+
+	//查看共享项目时按项目所有者查询
+	ownerID := userID
+	if query.ProjectID != nil && *query.ProjectID != 0 {
+		project, err := s.resolveProject(ctx, userID, *query.ProjectID, ActionView)
+		if err != nil {
+			return nil, err
+		}
+		ownerID = project.UserID
 	}
-	if query.Priority != nil {
-		priorityPtr = query.Priority
+	//关键词通过搜索后端匹配，分页、排序及总数仍由数据库在命中范围内计算
+	if query.KeyWord != "" {
+		ids, err := s.matchTodoIDs(ctx, ownerID, query.KeyWord)
+		if err != nil {
+			return nil, err
+		}
+		filter.IDs = ids
+	}
+	if query.Filter != "" {
+		expr, err := todofilter.Parse(query.Filter)
+		if err != nil {
+			return nil, errors.New("筛选条件无效: " + err.Error())
+		}
+		filter.Expr = expr
+	}
+
+	sortFields, err := repository.ParseTodoSort(query.Sort)
+	if err != nil {
+		return nil, errors.New("排序参数无效: " + err.Error())
+	}
+	sort := &repository.TodoSort{Fields: sortFields}
+	limit := query.PageSize
+	var cursor *todoCursor
+	if query.Cursor != "" {
+		cursor, err = decodeCursor(query.Cursor, sortFields)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Backward {
+			sort.Before = cursor.Keys
+		} else {
+			sort.After = cursor.Keys
+		}
+		//多取一条用于判断该方向上是否还有数据
+		limit++
 	}
 
-	todos, totalCount, err := s.todoRepo.GetByUserID(ctx, userID, query.Page, query.PageSize, statusPtr, priorityPtr, query.KeyWord)
+	todos, totalCount, err := s.todoRepo.GetByUserID(ctx, ownerID, query.Page, limit, filter, sort)
 	if err != nil {
 		return nil, err
 	}
+	// 计算分页信息
+	totalPage := (uint(totalCount) + query.PageSize - 1) / query.PageSize
+
+	//游标分页：沿翻页方向判断是否还有数据，反方向上游标所在的记录本身即为上一页
+	hasNext, hasPrev := query.Page < totalPage, query.Page > 1
+	if cursor != nil {
+		hasMore := uint(len(todos)) > query.PageSize
+		if hasMore && cursor.Backward {
+			todos = todos[1:]
+		} else if hasMore {
+			todos = todos[:query.PageSize]
+		}
+		hasNext, hasPrev = hasMore || cursor.Backward, hasMore || !cursor.Backward
+	}
+	var cursors response.Cursors
+	if len(todos) > 0 {
+		if hasNext {
+			cursors.Next = encodeCursor(sortFields, repository.TodoSortKey(&todos[len(todos)-1], sortFields), false)
+		}
+		if hasPrev {
+			cursors.Prev = encodeCursor(sortFields, repository.TodoSortKey(&todos[0], sortFields), true)
+		}
+	}
 
 	//转换为响应格式
 	todosResponses := make([]response.TodoResponse, len(todos))
 	for i, t := range todos {
 		todosResponses[i] = *s.todoToResponse(&t)
 	}
-	// 计算分页信息
-	totalPage := (uint(totalCount) + query.PageSize - 1) / query.PageSize
-	//获取统计信息
-	stats, _ := s.todoRepo.GetStatistics(ctx, userID)
-
-	return &response.TodoListResponse{
+	if err := s.fillTodoDetails(ctx, todosResponses); err != nil {
+		return nil, err
+	}
+	result := &response.TodoListResponse{
 		Todos: todosResponses,
 		Pagination: response.Pagination{
 			Page:       query.Page,
@@ -174,25 +609,29 @@ func (s *todoService) GetTodos(ctx context.Context, userID uint, query *request.
 			Total:      uint(totalCount),
 			TotalPages: totalPage,
 		},
-		Statistics: s.statsToResponse(stats),
-	}, nil
+		Cursors: cursors,
+	}
+	if query.WithStats {
+		stats, err := s.todoRepo.GetStatistics(ctx, ownerID, query.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		statistics := statsToResponse(stats)
+		result.Statistics = &statistics
+	}
+	return result, nil
 }
 
-// UpdateTodo 更新待办事项
-func (s *todoService) UpdateTodo(ctx context.Context, id, userID uint, req *request.UpdateTodoRequest) (*response.TodoResponse, error) {
-	todo, err := s.todoRepo.GetByID(ctx, id)
+// UpdateTodo 更新待办事项，ifMatch 须匹配当前版本
+func (s *todoService) UpdateTodo(ctx context.Context, id, userID uint, ifMatch string, req *request.UpdateTodoRequest) (*response.TodoResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
 	if err != nil {
 		return nil, err
 	}
-
-	if todo == nil {
-		return nil, errors.New("待办事项不存在")
-	}
-
-	//检查权限
-	if todo.UserID != userID {
-		return nil, errors.New("无权限修改此待办事项")
+	if err := s.checkTodoIfMatch(ctx, todo, ifMatch); err != nil {
+		return nil, err
 	}
+	before := *todo
 
 	if req.Title != "" {
 		todo.Title = req.Title
@@ -200,78 +639,362 @@ func (s *todoService) UpdateTodo(ctx context.Context, id, userID uint, req *requ
 	if req.Description != "" {
 		todo.Description = &req.Description
 	}
-	if req.Status != nil {
-		todo.Status = model.TodoStatus(*req.Status)
-		if *req.Status == 2 {
-			now := time.Now()
-			todo.CompletedAt = &now
-		} else {
-			todo.CompletedAt = nil
-		}
-	}
-
 	if req.Priority != nil {
 		todo.Priority = model.TodosPriority(*req.Priority)
 	}
+	dueDateChanged := false
 	if req.DueDate != nil {
+		dueDateChanged = todo.DueDate == nil || !todo.DueDate.Equal(*req.DueDate)
 		todo.DueDate = req.DueDate
 	}
+	if req.Recurrence != nil {
+		rule, err := s.buildRecurrence(req.Recurrence, todo.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		todo.Recurrence = rule
+	}
+	if req.ProjectID != nil {
+		if err := s.changeProject(ctx, todo, *req.ProjectID, userID); err != nil {
+			return nil, err
+		}
+	}
 
-	if err := s.todoRepo.Update(ctx, todo); err != nil {
+	if req.Status != nil || req.StatusID != nil {
+		status, statusID, err := s.resolveStatus(ctx, todo, req.Status, req.StatusID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.changeStatus(ctx, todo, status, statusID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	next, err := s.saveTodo(ctx, &before, todo, userID)
+	if err != nil {
 		return nil, err
 	}
-	return s.todoToResponse(todo), nil
+	s.recordHistory(ctx, todoChanges(&before, todo, userID)...)
+	s.indexTodos(ctx, todo, next)
+	if dueDateChanged {
+		if err := s.reminderRepo.RescheduleRelative(ctx, todo.ID, *todo.DueDate); err != nil {
+			return nil, err
+		}
+	}
+	return s.reloadResponse(ctx, todo.ID)
 }
 
-// DeleteTodo 删除待办事项
-func (s *todoService) DeleteTodo(ctx context.Context, id, userID uint) error {
-	todo, err := s.todoRepo.GetByID(ctx, id)
+// DeleteTodo 删除待办事项，ifMatch 须匹配当前版本
+func (s *todoService) DeleteTodo(ctx context.Context, id, userID uint, ifMatch string) error {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionManage)
 	if err != nil {
 		return err
 	}
+	if err := s.checkTodoIfMatch(ctx, todo, ifMatch); err != nil {
+		return err
+	}
 
-	if todo == nil {
-		return errors.New("待办事项不存在")
+	if err := s.todoRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.recordHistory(ctx, actionEvent(id, userID, model.HistoryActionDeleted))
+	ids := []uint{todo.ID}
+	for i := range todo.Subtasks {
+		ids = append(ids, todo.Subtasks[i].ID)
+	}
+	s.unindexTodos(ctx, ids...)
+	return nil
+}
+
+// DeleteProject 删除项目，deleteTodos 为 true 时一并删除项目内的待办事项(含子任务)，否则将其移入收件箱。
+// 与项目的删除在同一事务中完成并记录变更历史，调用方负责检查项目权限
+func (s *todoService) DeleteProject(ctx context.Context, projectID, userID uint, deleteTodos bool) error {
+	var removed []uint
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		removed = nil
+		todos, err := s.todoRepo.GetByProjectID(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		inProject := make(map[uint]bool, len(todos))
+		for i := range todos {
+			inProject[todos[i].ID] = true
+		}
+
+		var events []model.TodoHistory
+		if deleteTodos {
+			for i := range todos {
+				//子任务随父任务一起删除
+				if todos[i].ParentID != nil && inProject[*todos[i].ParentID] {
+					continue
+				}
+				if err := s.todoRepo.Delete(ctx, todos[i].ID); err != nil {
+					return err
+				}
+				removed = append(removed, todos[i].ID)
+				events = append(events, actionEvent(todos[i].ID, userID, model.HistoryActionDeleted))
+			}
+			subtaskIDs, err := s.todoRepo.GetSubtaskIDs(ctx, removed)
+			if err != nil {
+				return err
+			}
+			removed = append(removed, subtaskIDs...)
+		} else {
+			if err := s.todoRepo.MoveProjectToInbox(ctx, projectID); err != nil {
+				return err
+			}
+			for i := range todos {
+				after := todos[i]
+				after.ProjectID = nil
+				events = append(events, todoChanges(&todos[i], &after, userID)...)
+			}
+		}
+		if err := s.projectRepo.Delete(ctx, projectID); err != nil {
+			return err
+		}
+		s.recordHistory(ctx, events...)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	//事务提交后再更新搜索索引
+	if len(removed) > 0 {
+		s.unindexTodos(ctx, removed...)
+	}
+	return nil
+}
 
-	if todo.UserID != userID {
-		return errors.New("无权限删除此待办事项")
+// UpdateTodoStatus 更新待办事项状态，按状态机检查流转是否允许，ifMatch 须匹配当前版本
+func (s *todoService) UpdateTodoStatus(ctx context.Context, id, userID uint, ifMatch string, req *request.UpdateTodoStatusRequest) (*response.TodoResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTodoIfMatch(ctx, todo, ifMatch); err != nil {
+		return nil, err
+	}
+	before := *todo
+
+	status, statusID, err := s.resolveStatus(ctx, todo, req.Status, req.StatusID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.changeStatus(ctx, todo, status, statusID, userID); err != nil {
+		return nil, err
+	}
+
+	next, err := s.saveTodo(ctx, &before, todo, userID)
+	if err != nil {
+		return nil, err
 	}
+	s.recordHistory(ctx, todoChanges(&before, todo, userID)...)
+	s.indexTodos(ctx, next)
 
-	return s.todoRepo.Delete(ctx, id)
+	return s.reloadResponse(ctx, todo.ID)
 }
 
-// UpdateTodoStatus 更新待办事项状态
-func (s *todoService) UpdateTodoStatus(ctx context.Context, id, userID uint, status uint8) (*response.TodoResponse, error) {
-	todo, err := s.todoRepo.GetByID(ctx, id)
+// BatchUpdateStatus 批量更新状态，逐项按状态机流转并返回结果
+func (s *todoService) BatchUpdateStatus(ctx context.Context, userID uint, req *request.BatchUpdateTodoRequest) (*response.BatchTodoResponse, error) {
+	return s.BatchTodos(ctx, userID, &request.BatchTodoRequest{
+		TodoIDs: req.TodoIDs,
+		Action:  batchActionUpdate,
+		Status:  req.Status,
+	})
+}
+
+// AttachTags 为待办事项添加标签
+func (s *todoService) AttachTags(ctx context.Context, id, userID uint, req *request.TodoTagsRequest) (*response.TodoResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	//只允许使用待办事项所有者的标签
+	tags, err := s.tagRepo.GetByIDs(ctx, todo.UserID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[uint]bool, len(tags))
+	for _, tag := range tags {
+		owned[tag.ID] = true
+	}
+	for _, tagID := range req.TagIDs {
+		if !owned[tagID] {
+			return nil, errors.New("标签不存在")
+		}
+	}
+
+	if err := s.tagRepo.AttachToTodo(ctx, id, req.TagIDs); err != nil {
+		return nil, err
+	}
+
+	return s.afterTagsChanged(ctx, todo, userID)
+}
+
+// DetachTag 移除待办事项上的标签
+func (s *todoService) DetachTag(ctx context.Context, id, userID, tagID uint) (*response.TodoResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.tagRepo.DetachFromTodo(ctx, id, []uint{tagID}); err != nil {
+		return nil, err
+	}
+
+	return s.afterTagsChanged(ctx, todo, userID)
+}
+
+// afterTagsChanged 重新加载待办事项并记录标签变更
+func (s *todoService) afterTagsChanged(ctx context.Context, before *model.Todo, userID uint) (*response.TodoResponse, error) {
+	todo, err := s.todoRepo.GetByID(ctx, before.ID)
+	if err != nil {
+		return nil, err
+	}
 	if todo == nil {
 		return nil, errors.New("待办事项不存在")
 	}
+	s.recordHistory(ctx, tagChange(todo.ID, userID, before.Tags, todo.Tags)...)
+	return s.buildResponse(ctx, todo)
+}
 
-	if todo.UserID != userID {
-		return nil, errors.New("无权限修改此待办事项")
+// getAuthorizedParent 获取父任务并检查权限
+func (s *todoService) getAuthorizedParent(ctx context.Context, parentID, userID uint, action Action) (*model.Todo, error) {
+	parent, err := s.getAuthorizedTodo(ctx, parentID, userID, action)
+	if err != nil {
+		return nil, err
 	}
 
-	todo.Status = model.TodoStatus(status)
-	if status == 2 {
-		now := time.Now()
-		todo.CompletedAt = &now
-	} else {
-		todo.CompletedAt = nil
+	//只支持一层子任务
+	if model.IsSubtask(parent) {
+		return nil, errors.New("子任务不能再添加子任务")
 	}
+	return parent, nil
+}
 
-	if err := s.todoRepo.Update(ctx, todo); err != nil {
+// CreateSubtask 创建子任务
+func (s *todoService) CreateSubtask(ctx context.Context, parentID, userID uint, req *request.CreateTodoRequest) (*response.TodoResponse, error) {
+	parent, err := s.getAuthorizedParent(ctx, parentID, userID, ActionEdit)
+	if err != nil {
 		return nil, err
 	}
 
+	todo := &model.Todo{
+		UserID:    parent.UserID,
+		ProjectID: parent.ProjectID,
+		ParentID:  &parent.ID,
+		Position:  len(parent.Subtasks),
+		Title:     req.Title,
+		Priority:  model.TodosPriority(req.Priority),
+		DueDate:   req.DueDate,
+	}
+	if err := model.Transition(todo, model.TodoStatus(req.Status)); err != nil {
+		return nil, err
+	}
+	if req.Description != "" {
+		todo.Description = &req.Description
+	}
+
+	if err := s.todoRepo.Create(ctx, todo); err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, actionEvent(todo.ID, userID, model.HistoryActionCreated))
+	s.indexTodos(ctx, todo)
+
 	return s.todoToResponse(todo), nil
 }
 
-// BatchUpdateStatus 批量更新状态
-func (s *todoService) BatchUpdateStatus(ctx context.Context, userID uint, req *request.BatchUpdateTodoRequest) error {
-	return s.todoRepo.BatchUpdateStatus(ctx, userID, req.TodoIDs, model.TodoStatus(*req.Status))
+// GetSubtasks 获取子任务列表
+func (s *todoService) GetSubtasks(ctx context.Context, parentID, userID uint) ([]response.TodoResponse, error) {
+	parent, err := s.getAuthorizedParent(ctx, parentID, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	subtasks := make([]response.TodoResponse, len(parent.Subtasks))
+	for i := range parent.Subtasks {
+		subtasks[i] = *s.todoToResponse(&parent.Subtasks[i])
+	}
+	if err := s.fillTodoDetails(ctx, subtasks); err != nil {
+		return nil, err
+	}
+	return subtasks, nil
+}
+
+// ReorderSubtasks 调整子任务顺序
+func (s *todoService) ReorderSubtasks(ctx context.Context, parentID, userID uint, req *request.ReorderSubtasksRequest) ([]response.TodoResponse, error) {
+	parent, err := s.getAuthorizedParent(ctx, parentID, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	//排序列表必须与现有子任务完全一致
+	if len(req.SubtaskIDs) != len(parent.Subtasks) {
+		return nil, errors.New("子任务列表不匹配")
+	}
+	existing := make(map[uint]bool, len(parent.Subtasks))
+	for _, subtask := range parent.Subtasks {
+		existing[subtask.ID] = true
+	}
+	for _, id := range req.SubtaskIDs {
+		if !existing[id] {
+			return nil, errors.New("子任务列表不匹配")
+		}
+		delete(existing, id)
+	}
+
+	if err := s.todoRepo.UpdateSubtaskPositions(ctx, parentID, req.SubtaskIDs); err != nil {
+		return nil, err
+	}
+
+	subtasks, err := s.todoRepo.GetSubtasks(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	subtaskResponses := make([]response.TodoResponse, len(subtasks))
+	for i := range subtasks {
+		subtaskResponses[i] = *s.todoToResponse(&subtasks[i])
+	}
+	if err := s.fillTodoDetails(ctx, subtaskResponses); err != nil {
+		return nil, err
+	}
+	return subtaskResponses, nil
+}
+
+// GetOccurrences 预览重复任务接下来的发生时间
+func (s *todoService) GetOccurrences(ctx context.Context, id, userID uint, limit int) (*response.OccurrencesResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo.Recurrence == nil || todo.DueDate == nil {
+		return nil, errors.New("该待办事项不是重复任务")
+	}
+	rule, err := recurrence.Parse(*todo.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.OccurrencesResponse{
+		Rule:        rule.String(),
+		Occurrences: rule.Preview(*todo.DueDate, limit),
+	}, nil
+}
+
+// StopRecurrence 取消重复，保留当前实例
+func (s *todoService) StopRecurrence(ctx context.Context, id, userID uint) (*response.TodoResponse, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, userID, ActionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *todo
+	todo.Recurrence = nil
+	if err := s.todoRepo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, todoChanges(&before, todo, userID)...)
+	return s.buildResponse(ctx, todo)
 }
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	// defaultStatsDays 未指定区间时统计最近的天数
+	defaultStatsDays = 30
+	// maxStatsDays 统计区间最大天数
+	maxStatsDays = 366
+)
+
+// statsWindow 计算统计区间，返回 [from, to) 的起止时间，按服务器时区的整天划分
+func statsWindow(req *request.TodoStatsRequest, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	to := today
+	if req.To != nil {
+		to = time.Date(req.To.Year(), req.To.Month(), req.To.Day(), 0, 0, 0, 0, time.Local)
+	}
+	from := to.AddDate(0, 0, -(defaultStatsDays - 1))
+	if req.From != nil {
+		from = time.Date(req.From.Year(), req.From.Month(), req.From.Day(), 0, 0, 0, 0, time.Local)
+	}
+
+	if from.After(to) {
+		return from, to, errors.New("开始日期不能晚于结束日期")
+	}
+	to = to.AddDate(0, 0, 1)
+	if to.Sub(from) > maxStatsDays*24*time.Hour {
+		return from, to, errors.New("统计区间不能超过366天")
+	}
+	return from, to, nil
+}
+
+// round2 保留两位小数
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// GetStats 获取统计分析数据
+func (s *todoService) GetStats(ctx context.Context, userID uint, req *request.TodoStatsRequest) (*response.TodoStatsResponse, error) {
+	now := time.Now()
+	from, to, err := statsWindow(req, now)
+	if err != nil {
+		return nil, err
+	}
+
+	//统计共享项目时按项目所有者查询
+	ownerID := userID
+	if req.ProjectID != nil && *req.ProjectID != 0 {
+		project, err := s.resolveProject(ctx, userID, *req.ProjectID, ActionView)
+		if err != nil {
+			return nil, err
+		}
+		ownerID = project.UserID
+	}
+
+	analytics, err := s.todoRepo.GetAnalytics(ctx, ownerID, req.ProjectID, from, to, now)
+	if err != nil {
+		return nil, err
+	}
+
+	var completionRate float64
+	if analytics.CreatedInWindow > 0 {
+		completionRate = round2(float64(analytics.CompletedOfCreated) / float64(analytics.CreatedInWindow))
+	}
+	daily := make([]response.DailyStats, len(analytics.Daily))
+	for i, day := range analytics.Daily {
+		daily[i] = response.DailyStats{
+			Date:      day.Day.Format(time.DateOnly),
+			Created:   day.Created,
+			Completed: day.Completed,
+		}
+	}
+
+	return &response.TodoStatsResponse{
+		Statistics: statsToResponse(&analytics.TodoStatistics),
+		ByPriority: response.PriorityCounts{
+			Low:    analytics.LowCount,
+			Medium: analytics.MediumCount,
+			High:   analytics.HighCount,
+			Urgent: analytics.UrgentCount,
+		},
+		OverdueCount:       analytics.OverdueCount,
+		From:               from.Format(time.DateOnly),
+		To:                 to.AddDate(0, 0, -1).Format(time.DateOnly),
+		CreatedInWindow:    analytics.CreatedInWindow,
+		CompletedInWindow:  analytics.CompletedInWindow,
+		CompletionRate:     completionRate,
+		AvgCompletionHours: round2(analytics.AvgCompletionSeconds / 3600),
+		Daily:              daily,
+	}, nil
+}
@@ -0,0 +1,542 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"TODO_API/pkg/todotxt"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// todo.txt 中使用的标签键
+const (
+	todoTxtIDKey     = "id"     // 待办事项ID，同步时按此匹配已有的待办事项
+	todoTxtParentKey = "parent" // 父任务ID
+	todoTxtDueKey    = "due"    // 截止日期
+)
+
+// priorityToTodoTxt 紧急、高、中分别对应 A、B、C，低优先级不写优先级
+func priorityToTodoTxt(priority model.TodosPriority) byte {
+	switch priority {
+	case 4:
+		return 'A'
+	case 3:
+		return 'B'
+	case 2:
+		return 'C'
+	default:
+		return 0
+	}
+}
+
+// priorityFromTodoTxt A、B、C 分别对应紧急、高、中，其余(含未设置)为低
+func priorityFromTodoTxt(priority byte) model.TodosPriority {
+	switch priority {
+	case 'A':
+		return 4
+	case 'B':
+		return 3
+	case 'C':
+		return 2
+	default:
+		return 1
+	}
+}
+
+// todoTxtKey 项目及标签按名称匹配时忽略大小写，名称中的空白与下划线视为相同
+func todoTxtKey(name string) string {
+	return strings.ToLower(todotxt.Token(name))
+}
+
+// sameDate 两个时间在本地时区是否为同一天
+func sameDate(a, b time.Time) bool {
+	return a.Local().Format(todotxt.DateLayout) == b.Local().Format(todotxt.DateLayout)
+}
+
+// todoToTodoTxt 将待办事项转换为 todo.txt 的一行，projects 为项目ID到名称的映射
+func todoToTodoTxt(todo *model.Todo, projects map[uint]string) *todotxt.Task {
+	task := &todotxt.Task{
+		Completed: todo.Status == 2,
+		Priority:  priorityToTodoTxt(todo.Priority),
+		Text:      todo.Title,
+	}
+	if todo.CreatedAt != nil {
+		created := todo.CreatedAt.Local()
+		task.CreationDate = &created
+	}
+	if task.Completed {
+		completed := time.Now()
+		if todo.CompletedAt != nil {
+			completed = todo.CompletedAt.Local()
+		}
+		task.CompletionDate = &completed
+	}
+	if todo.ProjectID != nil {
+		if name, ok := projects[*todo.ProjectID]; ok {
+			task.Projects = []string{todotxt.Token(name)}
+		}
+	}
+	for _, tag := range todo.Tags {
+		task.Contexts = append(task.Contexts, todotxt.Token(tag.Name))
+	}
+	if todo.DueDate != nil {
+		task.Tags = append(task.Tags, todotxt.Tag{Key: todoTxtDueKey, Value: todo.DueDate.Local().Format(todotxt.DateLayout)})
+	}
+	if todo.ParentID != nil {
+		task.Tags = append(task.Tags, todotxt.Tag{Key: todoTxtParentKey, Value: strconv.FormatUint(uint64(*todo.ParentID), 10)})
+	}
+	task.Tags = append(task.Tags, todotxt.Tag{Key: todoTxtIDKey, Value: strconv.FormatUint(uint64(todo.ID), 10)})
+	return task
+}
+
+// ExportTodoTxt 将用户的全部待办事项(含子任务)导出为 todo.txt，每行带有 id:，上传时据此与已有的待办事项对应
+func (s *todoService) ExportTodoTxt(ctx context.Context, userID uint) ([]byte, error) {
+	todos, err := s.todoRepo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projectRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+
+	tasks := make([]*todotxt.Task, 0, len(todos))
+	for i := range todos {
+		tasks = append(tasks, todoToTodoTxt(&todos[i], names))
+	}
+	var buf bytes.Buffer
+	if err := todotxt.Write(&buf, tasks); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// todoTxtSync 一次同步中共享的数据
+type todoTxtSync struct {
+	userID    uint
+	todos     map[uint]*model.Todo
+	projects  map[string]*model.Project // 按 todoTxtKey 索引
+	tagNames  map[string]string         // 按 todoTxtKey 索引的已有标签名
+	tags      map[string]*model.Tag     // resolveTags 的缓存
+	positions map[uint]int              // 父任务下一个子任务的位置
+}
+
+// todoTxtLine 校验后的一行
+type todoTxtLine struct {
+	item           *response.TodoTxtSyncItem
+	task           *todotxt.Task
+	existing       *model.Todo // 按 id: 匹配的待办事项，为空表示新建
+	parent         *model.Todo // 新建子任务的父任务
+	priority       model.TodosPriority
+	dueDate        *time.Time
+	completionDate *time.Time
+	project        string
+	tags           []string
+	saved          []*model.Todo // 写入的待办事项，事务提交后更新搜索索引
+}
+
+// addNote 追加同步提示
+func (l *todoTxtLine) addNote(note string) {
+	if l.item.Message != "" {
+		l.item.Message += "；"
+	}
+	l.item.Message += note
+}
+
+// completionTime 完成时间，未写完成日期或为当天时取当前时间
+func completionTime(date *time.Time) *time.Time {
+	now := time.Now()
+	if date == nil || sameDate(*date, now) {
+		return &now
+	}
+	return date
+}
+
+// SyncTodoTxt 上传 todo.txt 与已有的待办事项同步：带 id: 的行更新对应的待办事项，其余行新建；
+// 文件中没有的待办事项保持不变。描述、重复规则等 todo.txt 无法表示的内容不会被修改，
+// 每行单独处理，部分失败不影响其余行
+func (s *todoService) SyncTodoTxt(ctx context.Context, userID uint, file *multipart.FileHeader) (*response.TodoTxtSyncResponse, error) {
+	f, err := openImportFile(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines, err := todotxt.Parse(f)
+	if err != nil {
+		return nil, errors.New("文件格式无效: " + err.Error())
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("文件中没有待办事项")
+	}
+	if len(lines) > maxImportTodos {
+		return nil, fmt.Errorf("单次最多导入 %d 个待办事项", maxImportTodos)
+	}
+
+	sync, err := s.newTodoTxtSync(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &response.TodoTxtSyncResponse{Total: len(lines), Items: make([]response.TodoTxtSyncItem, len(lines))}
+	var pending []*todoTxtLine
+	seen := make(map[uint]int)
+	for i, line := range lines {
+		item := &result.Items[i]
+		item.Line = line.Number
+		if line.Task != nil {
+			item.Title = line.Task.Text
+		}
+		if line.Err != nil {
+			item.Result, item.Message = response.ImportResultFailed, line.Err.Error()
+			continue
+		}
+		parsed, err := sync.parseLine(item, line.Task, seen)
+		if err != nil {
+			item.Result, item.Message = response.ImportResultFailed, err.Error()
+			continue
+		}
+		pending = append(pending, parsed)
+	}
+
+	// 先处理已有的子任务，同一文件中同时完成子任务与父任务时父任务才能通过检查
+	for _, subtasks := range []bool{true, false} {
+		for _, line := range pending {
+			if (line.existing != nil && model.IsSubtask(line.existing)) != subtasks {
+				continue
+			}
+			if err := s.syncTodoTxtLine(ctx, sync, line); err != nil {
+				line.item.Result, line.item.Message, line.item.TodoID = response.ImportResultFailed, err.Error(), nil
+			}
+		}
+	}
+
+	for _, item := range result.Items {
+		switch item.Result {
+		case response.ImportResultCreated:
+			result.Created++
+		case response.ImportResultUpdated:
+			result.Updated++
+		case response.ImportResultUnchanged:
+			result.Unchanged++
+		case response.ImportResultFailed:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// newTodoTxtSync 加载用户已有的待办事项、项目及标签
+func (s *todoService) newTodoTxtSync(ctx context.Context, userID uint) (*todoTxtSync, error) {
+	todos, err := s.todoRepo.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projectRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sync := &todoTxtSync{
+		userID:    userID,
+		todos:     make(map[uint]*model.Todo, len(todos)),
+		projects:  make(map[string]*model.Project, len(projects)),
+		tagNames:  make(map[string]string, len(tags)),
+		tags:      make(map[string]*model.Tag),
+		positions: make(map[uint]int),
+	}
+	for i := range todos {
+		sync.todos[todos[i].ID] = &todos[i]
+	}
+	for i := range projects {
+		sync.projects[todoTxtKey(projects[i].Name)] = &projects[i]
+	}
+	for _, tag := range tags {
+		sync.tagNames[todoTxtKey(tag.Name)] = tag.Name
+	}
+	return sync, nil
+}
+
+// parseLine 校验一行，seen 记录已出现的待办事项ID所在的行号
+func (sync *todoTxtSync) parseLine(item *response.TodoTxtSyncItem, task *todotxt.Task, seen map[uint]int) (*todoTxtLine, error) {
+	if utf8.RuneCountInString(task.Text) > maxTitleLength {
+		return nil, fmt.Errorf("标题不能超过 %d 个字符", maxTitleLength)
+	}
+	line := &todoTxtLine{
+		item:     item,
+		task:     task,
+		priority: priorityFromTodoTxt(task.Priority),
+	}
+
+	if value, ok := task.Tag(todoTxtIDKey); ok {
+		id, err := strconv.ParseUint(value, 10, 64)
+		todo := sync.todos[uint(id)]
+		if err != nil || todo == nil {
+			return nil, errors.New("待办事项不存在")
+		}
+		if number, ok := seen[todo.ID]; ok {
+			return nil, fmt.Errorf("id 与第 %d 行重复", number)
+		}
+		seen[todo.ID] = item.Line
+		line.existing = todo
+		item.TodoID = &todo.ID
+	}
+	if value, ok := task.Tag(todoTxtParentKey); ok && line.existing == nil {
+		id, err := strconv.ParseUint(value, 10, 64)
+		parent := sync.todos[uint(id)]
+		if err != nil || parent == nil || model.IsSubtask(parent) {
+			return nil, errors.New("父任务不存在")
+		}
+		line.parent = parent
+	}
+	if value, ok := task.Tag(todoTxtDueKey); ok {
+		due, err := parseImportTime(value)
+		if err != nil {
+			return nil, errors.New("截止日期无效")
+		}
+		line.dueDate = &due
+	}
+	if task.Completed {
+		line.completionDate = task.CompletionDate
+	}
+
+	if len(task.Projects) > 0 {
+		line.project = task.Projects[0]
+		if utf8.RuneCountInString(line.project) > maxProjectNameLength {
+			return nil, fmt.Errorf("项目名不能超过 %d 个字符", maxProjectNameLength)
+		}
+		if len(task.Projects) > 1 {
+			line.addNote("只使用第一个项目")
+		}
+	}
+	names := make(map[string]bool)
+	for _, context := range task.Contexts {
+		if utf8.RuneCountInString(context) > maxTagNameLength {
+			return nil, fmt.Errorf("标签名不能超过 %d 个字符", maxTagNameLength)
+		}
+		key := todoTxtKey(context)
+		if names[key] {
+			continue
+		}
+		names[key] = true
+		if name, ok := sync.tagNames[key]; ok {
+			context = name
+		}
+		line.tags = append(line.tags, context)
+	}
+	return line, nil
+}
+
+// todoTxtProject 按名称查找项目，不存在时创建；名称为空表示收件箱
+func (s *todoService) todoTxtProject(ctx context.Context, sync *todoTxtSync, name string) (*model.Project, error) {
+	if name == "" {
+		return nil, nil
+	}
+	key := todoTxtKey(name)
+	if project, ok := sync.projects[key]; ok {
+		return project, nil
+	}
+	project := &model.Project{UserID: sync.userID, Name: name}
+	if err := s.projectRepo.Create(ctx, project); err != nil {
+		return nil, err
+	}
+	sync.projects[key] = project
+	return project, nil
+}
+
+// syncTodoTxtLine 在一个事务中处理一行，失败时回滚本行的全部写入，
+// 并恢复同步过程中缓存的待办事项、项目及标签
+func (s *todoService) syncTodoTxtLine(ctx context.Context, sync *todoTxtSync, line *todoTxtLine) error {
+	projects, tags, positions := maps.Clone(sync.projects), maps.Clone(sync.tags), maps.Clone(sync.positions)
+	var before model.Todo
+	if line.existing != nil {
+		before = *line.existing
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		line.saved = nil
+		if line.existing == nil {
+			return s.createTodoTxtLine(ctx, sync, line)
+		}
+		return s.updateTodoTxtLine(ctx, sync, line)
+	})
+	if err != nil {
+		sync.projects, sync.tags, sync.positions = projects, tags, positions
+		if line.existing != nil {
+			*line.existing = before
+		}
+		return err
+	}
+	//事务提交后再更新搜索索引
+	s.indexTodos(ctx, line.saved...)
+	return nil
+}
+
+// createTodoTxtLine 新建一行对应的待办事项，带 parent: 的作为已有任务的子任务
+func (s *todoService) createTodoTxtLine(ctx context.Context, sync *todoTxtSync, line *todoTxtLine) error {
+	todo := &model.Todo{
+		UserID:   sync.userID,
+		Title:    line.task.Text,
+		Priority: line.priority,
+		DueDate:  line.dueDate,
+	}
+	if line.task.Completed {
+		todo.Status = 2
+		todo.CompletedAt = completionTime(line.completionDate)
+	}
+
+	if parent := line.parent; parent != nil {
+		position, ok := sync.positions[parent.ID]
+		if !ok {
+			for _, existing := range sync.todos {
+				if existing.ParentID != nil && *existing.ParentID == parent.ID {
+					position++
+				}
+			}
+		}
+		sync.positions[parent.ID] = position + 1
+		todo.ParentID = &parent.ID
+		todo.ProjectID = parent.ProjectID
+		todo.Position = position
+	} else {
+		project, err := s.todoTxtProject(ctx, sync, line.project)
+		if err != nil {
+			return err
+		}
+		if project != nil {
+			todo.ProjectID = &project.ID
+		}
+	}
+
+	if err := s.todoRepo.Create(ctx, todo); err != nil {
+		return err
+	}
+	line.item.Result = response.ImportResultCreated
+	line.item.TodoID = &todo.ID
+	s.recordHistory(ctx, actionEvent(todo.ID, sync.userID, model.HistoryActionCreated))
+	if err := s.setTodoTags(ctx, todo, sync.userID, line.tags, sync.tags); err != nil {
+		line.addNote("标签导入失败")
+	}
+	line.saved = append(line.saved, todo)
+	return nil
+}
+
+// updateTodoTxtLine 用一行的内容更新已有的待办事项，内容与现有数据一致时不做修改。
+// 字段与标签的变化都记录到变更历史中，由 syncTodoTxtLine 在事务中调用
+func (s *todoService) updateTodoTxtLine(ctx context.Context, sync *todoTxtSync, line *todoTxtLine) error {
+	todo := line.existing
+	before := *todo
+	if err := s.applyTodoTxtLine(ctx, sync, line, todo); err != nil {
+		return err
+	}
+
+	events := todoChanges(&before, todo, sync.userID)
+	completedAtChanged := (before.CompletedAt == nil) != (todo.CompletedAt == nil) ||
+		before.CompletedAt != nil && !before.CompletedAt.Equal(*todo.CompletedAt)
+	tagsChanged := !sameTagNames(todo.Tags, line.tags)
+	if len(events) == 0 && !completedAtChanged && !tagsChanged {
+		line.item.Result = response.ImportResultUnchanged
+		return nil
+	}
+
+	if len(events) > 0 || completedAtChanged {
+		next, err := s.saveTodo(ctx, &before, todo, sync.userID)
+		if err != nil {
+			return err
+		}
+		s.recordHistory(ctx, todoChanges(&before, todo, sync.userID)...)
+		if todo.DueDate != nil && (before.DueDate == nil || !before.DueDate.Equal(*todo.DueDate)) {
+			if err := s.reminderRepo.RescheduleRelative(ctx, todo.ID, *todo.DueDate); err != nil {
+				return err
+			}
+		}
+		line.saved = append(line.saved, next)
+	}
+	//标签在版本检查通过后再修改，变化由 setTodoTags 记录到变更历史
+	if tagsChanged {
+		if err := s.setTodoTags(ctx, todo, sync.userID, line.tags, sync.tags); err != nil {
+			return err
+		}
+	}
+	line.saved = append(line.saved, todo)
+	line.item.Result = response.ImportResultUpdated
+	return nil
+}
+
+// applyTodoTxtLine 将一行的内容应用到待办事项上(不保存)
+func (s *todoService) applyTodoTxtLine(ctx context.Context, sync *todoTxtSync, line *todoTxtLine, todo *model.Todo) error {
+	todo.Title = line.task.Text
+	todo.Priority = line.priority
+
+	// todo.txt 的截止日期只精确到天，日期未变化时保留原有的时间
+	switch {
+	case line.dueDate == nil:
+		if todo.Recurrence != nil {
+			return errors.New("重复任务必须设置截止时间")
+		}
+		todo.DueDate = nil
+	case todo.DueDate == nil || !sameDate(*todo.DueDate, *line.dueDate):
+		todo.DueDate = line.dueDate
+	}
+
+	// 子任务的项目跟随父任务
+	if !model.IsSubtask(todo) {
+		project, err := s.todoTxtProject(ctx, sync, line.project)
+		if err != nil {
+			return err
+		}
+		todo.ProjectID = nil
+		if project != nil {
+			todo.ProjectID = &project.ID
+		}
+	}
+
+	switch {
+	case line.task.Completed && todo.Status != 2:
+		if err := s.changeStatus(ctx, todo, 2, nil, sync.userID); err != nil {
+			return err
+		}
+		todo.CompletedAt = completionTime(line.completionDate)
+	case line.task.Completed:
+		// 已完成的任务只在完成日期改变时更新完成时间
+		if line.completionDate != nil && (todo.CompletedAt == nil || !sameDate(*todo.CompletedAt, *line.completionDate)) {
+			todo.CompletedAt = line.completionDate
+		}
+	case todo.Status == 2:
+		return s.changeStatus(ctx, todo, 0, nil, sync.userID)
+	}
+	return nil
+}
+
+// sameTagNames 标签名集合是否相同，不区分大小写
+func sameTagNames(tags []model.Tag, names []string) bool {
+	if len(tags) != len(names) {
+		return false
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	for _, tag := range tags {
+		if !set[strings.ToLower(tag.Name)] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"TODO_API/internal/app/dto/request"
+	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
+	"context"
+	"errors"
+	"time"
+)
+
+// getTrashedTodo 获取回收站中的待办事项并检查权限，恢复与彻底删除都需要 owner 角色
+func (s *todoService) getTrashedTodo(ctx context.Context, id, userID uint) (*model.Todo, error) {
+	todo, err := s.todoRepo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if todo == nil {
+		return nil, errors.New("待办事项不存在")
+	}
+
+	ok, err := s.access.CanAccessTodo(ctx, todo, userID, ActionManage)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, todoPermissionError(ActionManage)
+	}
+	return todo, nil
+}
+
+// purge 彻底删除待办事项及其子任务，先清理附件文件再删除数据
+func (s *todoService) purge(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	subtaskIDs, err := s.todoRepo.GetSubtaskIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	ids = append(ids, subtaskIDs...)
+
+	if err := s.attachments.PurgeTodoFiles(ctx, ids); err != nil {
+		return err
+	}
+	if err := s.todoRepo.Purge(ctx, ids); err != nil {
+		return err
+	}
+	s.unindexTodos(ctx, ids...)
+	return nil
+}
+
+// GetTrash 分页获取回收站中的待办事项
+func (s *todoService) GetTrash(ctx context.Context, userID uint, query *request.TrashQueryRequest) (*response.TrashListResponse, error) {
+	todos, totalCount, err := s.todoRepo.GetTrashed(ctx, userID, query.Page, query.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	todosResponses := make([]response.TodoResponse, len(todos))
+	for i := range todos {
+		todosResponses[i] = *s.todoToResponse(&todos[i])
+	}
+	totalPage := (uint(totalCount) + query.PageSize - 1) / query.PageSize
+
+	return &response.TrashListResponse{
+		Todos: todosResponses,
+		Pagination: response.Pagination{
+			Page:       query.Page,
+			PageSize:   query.PageSize,
+			Total:      uint(totalCount),
+			TotalPages: totalPage,
+		},
+	}, nil
+}
+
+// RestoreTodo 从回收站恢复待办事项，所属项目已删除时移入收件箱
+func (s *todoService) RestoreTodo(ctx context.Context, id, userID uint) (*response.TodoResponse, error) {
+	todo, err := s.getTrashedTodo(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo.ParentID != nil {
+		parent, err := s.todoRepo.GetByID(ctx, *todo.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return nil, errors.New("父任务已删除，请先恢复父任务")
+		}
+	}
+
+	moveToInbox := false
+	if todo.ProjectID != nil {
+		project, err := s.projectRepo.GetByID(ctx, *todo.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		moveToInbox = project == nil
+	}
+
+	if err := s.todoRepo.Restore(ctx, todo, moveToInbox); err != nil {
+		return nil, err
+	}
+	s.recordHistory(ctx, actionEvent(todo.ID, userID, model.HistoryActionRestored))
+
+	restored, err := s.getAuthorizedTodo(ctx, id, userID, ActionView)
+	if err != nil {
+		return nil, err
+	}
+	s.indexTodos(ctx, restored)
+	for i := range restored.Subtasks {
+		s.indexTodos(ctx, &restored.Subtasks[i])
+	}
+	return s.buildResponse(ctx, restored)
+}
+
+// PurgeTodo 彻底删除回收站中的待办事项
+func (s *todoService) PurgeTodo(ctx context.Context, id, userID uint) error {
+	todo, err := s.getTrashedTodo(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, []uint{todo.ID})
+}
+
+// PurgeExpired 彻底删除删除时间早于 deletedBefore 的待办事项，返回清理的数量
+func (s *todoService) PurgeExpired(ctx context.Context, deletedBefore time.Time, limit int) (int, error) {
+	ids, err := s.todoRepo.GetExpiredTrashIDs(ctx, deletedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.purge(ctx, ids); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
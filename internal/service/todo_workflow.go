@@ -0,0 +1,94 @@
+package service
+
+import (
+	"TODO_API/internal/domain/model"
+	"context"
+	"errors"
+)
+
+// resolveStatus 解析目标分类及自定义状态：只指定自定义状态时流转到其所属分类；
+// 只指定分类时，分类不变则保留原有的自定义状态，否则清除
+func (s *todoService) resolveStatus(ctx context.Context, todo *model.Todo, status *uint8, statusID *uint) (model.TodoStatus, *uint, error) {
+	target := todo.Status
+	if status != nil {
+		target = model.TodoStatus(*status)
+	}
+	if statusID == nil {
+		if target != todo.Status {
+			return target, nil, nil
+		}
+		return target, todo.StatusID, nil
+	}
+	if *statusID == 0 {
+		return target, nil, nil
+	}
+
+	custom, err := s.statusRepo.GetByID(ctx, *statusID)
+	if err != nil {
+		return 0, nil, err
+	}
+	//自定义状态属于待办事项所有者，共享给他人时同样使用所有者的状态
+	if custom == nil || custom.UserID != todo.UserID {
+		return 0, nil, errors.New("状态不存在")
+	}
+	if status != nil && custom.Category != target {
+		return 0, nil, errors.New("自定义状态不属于该分类")
+	}
+	return custom.Category, &custom.ID, nil
+}
+
+// changeStatus 通过状态机修改待办事项的状态：检查流转是否允许及进入新分类的前置条件，
+// 开始或完成前要求前置任务已完成，完成前要求子任务已完成，完成时间由状态机维护。
+// 只修改内存中的数据，完成重复任务时的下一次实例由 saveTodo 在写入时生成
+func (s *todoService) changeStatus(ctx context.Context, todo *model.Todo, target model.TodoStatus, statusID *uint, userID uint) error {
+	if target != todo.Status {
+		if !model.CanTransition(todo.Status, target) {
+			return model.ErrInvalidTransition
+		}
+		if target != 0 {
+			if err := s.checkBlockersFinished(ctx, todo.ID); err != nil {
+				return err
+			}
+		}
+		if target == 2 {
+			if err := s.checkSubtasksFinished(ctx, todo); err != nil {
+				return err
+			}
+		}
+		if err := model.Transition(todo, target); err != nil {
+			return err
+		}
+	}
+	todo.StatusID = statusID
+	return nil
+}
+
+// saveTodo 写入修改后的待办事项，before 为修改前的数据。本次修改完成了重复任务时，在同一事务中生成下一次实例
+// 并清除已完成实例的重复规则，写入失败(如版本冲突)时不会留下多余的实例；父任务的项目变化时同步修改其子任务的项目。
+// 返回生成的实例，调用方在事务提交后更新其搜索索引
+func (s *todoService) saveTodo(ctx context.Context, before, todo *model.Todo, userID uint) (*model.Todo, error) {
+	rule := todo.Recurrence
+	var next *model.Todo
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		next = nil
+		if !model.IsCompleted(before) && model.IsCompleted(todo) {
+			var err error
+			if next, err = s.scheduleNextOccurrence(ctx, todo, userID); err != nil {
+				return err
+			}
+		}
+		if err := s.todoRepo.Update(ctx, todo); err != nil {
+			return err
+		}
+		//子任务的项目跟随父任务，否则旧项目的共享成员仍能访问子任务，删除旧项目时也会误删子任务
+		if fieldChanged(before.ProjectID, todo.ProjectID) && !model.IsSubtask(todo) {
+			return s.todoRepo.MoveSubtasksToProject(ctx, todo.ID, todo.ProjectID)
+		}
+		return nil
+	})
+	if err != nil {
+		todo.Recurrence = rule
+		return nil, err
+	}
+	return next, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"TODO_API/internal/app/dto/request"
 	"TODO_API/internal/app/dto/response"
+	"TODO_API/internal/domain/model"
 	"TODO_API/internal/repository"
 	"TODO_API/pkg/encryption"
 	"context"
@@ -11,7 +12,7 @@ import (
 
 type UserService interface {
 	GetProfile(ctx context.Context, userID uint) (*response.UserResponse, error)
-	UpdateProfile(ctx context.Context, userID uint, req *request.UpdateProfileRequest) (*response.UserResponse, error)
+	UpdateProfile(ctx context.Context, userID uint, ifMatch string, req *request.UpdateProfileRequest) (*response.UserResponse, error)
 	ChangePassword(ctx context.Context, userID uint, req *request.ChangePasswordRequest) error
 }
 
@@ -23,25 +24,34 @@ func NewUserService(userRepo repository.UserRepository) *userService {
 	return &userService{userRepo: userRepo}
 }
 
+// userToResponse 将User模型转换为响应格式
+func userToResponse(user *model.User) *response.UserResponse {
+	return &response.UserResponse{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Avatar:   user.AvatarURL,
+		Version:  user.Version,
+	}
+}
+
 func (s *userService) GetProfile(ctx context.Context, userID uint) (*response.UserResponse, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil || user == nil {
 		return nil, errors.New("用户不存在")
 	}
 
-	return &response.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Avatar:   user.AvatarURL,
-	}, nil
+	return userToResponse(user), nil
 }
 
-func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *request.UpdateProfileRequest) (*response.UserResponse, error) {
+func (s *userService) UpdateProfile(ctx context.Context, userID uint, ifMatch string, req *request.UpdateProfileRequest) (*response.UserResponse, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil || user == nil {
 		return nil, errors.New("用户不存在")
 	}
+	if err := checkIfMatch(ifMatch, userToResponse(user)); err != nil {
+		return nil, err
+	}
 
 	if req.Email != "" {
 		//检查邮箱是否被使用
@@ -58,12 +68,7 @@ func (s *userService) UpdateProfile(ctx context.Context, userID uint, req *reque
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
-	return &response.UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Avatar:   user.AvatarURL,
-	}, nil
+	return userToResponse(user), nil
 }
 
 func (s *userService) ChangePassword(ctx context.Context, userID uint, req *request.ChangePasswordRequest) error {
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"TODO_API/pkg/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notification 提醒通知内容
+type Notification struct {
+	ReminderID uint
+	UserID     uint
+	TodoID     uint
+	Title      string
+	DueDate    *time.Time
+	RemindAt   time.Time
+}
+
+// Notifier 提醒通知发送接口，可替换为邮件、推送等实现
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// LogNotifier 将提醒写入日志的默认实现
+type LogNotifier struct{}
+
+// NewLogNotifier 创建日志通知实例
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+// Notify 记录提醒日志
+func (n *LogNotifier) Notify(ctx context.Context, notification Notification) error {
+	fields := []zap.Field{
+		zap.Uint("reminder_id", notification.ReminderID),
+		zap.Uint("user_id", notification.UserID),
+		zap.Uint("todo_id", notification.TodoID),
+		zap.String("title", notification.Title),
+		zap.Time("remind_at", notification.RemindAt),
+	}
+	if notification.DueDate != nil {
+		fields = append(fields, zap.Time("due_date", *notification.DueDate))
+	}
+	logger.Info("待办事项提醒", fields...)
+	return nil
+}
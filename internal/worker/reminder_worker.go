@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"TODO_API/internal/repository"
+	"TODO_API/pkg/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxReminderAttempts 提醒最多发送的次数，全部失败后标记为放弃
+	maxReminderAttempts = 5
+	// reminderRetryDelay 首次发送失败后的重试间隔，之后每次加倍
+	reminderRetryDelay = time.Minute
+)
+
+// ReminderWorker 定时扫描到期提醒并通过 Notifier 发送，发送失败时延后重试，避免失败的提醒一直占据批次
+type ReminderWorker struct {
+	reminderRepo repository.ReminderRepository
+	notifier     Notifier
+	interval     time.Duration
+	batchSize    int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReminderWorker 创建提醒后台任务
+func NewReminderWorker(reminderRepo repository.ReminderRepository, notifier Notifier,
+	interval time.Duration, batchSize int) *ReminderWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &ReminderWorker{
+		reminderRepo: reminderRepo,
+		notifier:     notifier,
+		interval:     interval,
+		batchSize:    batchSize,
+	}
+}
+
+// Start 启动后台扫描
+func (w *ReminderWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		logger.Info("提醒任务已启动", zap.Duration("interval", w.interval))
+		for {
+			w.dispatch(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止后台扫描并等待当前批次处理完成
+func (w *ReminderWorker) Stop(ctx context.Context) error {
+	if w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+	select {
+	case <-w.done:
+		logger.Info("提醒任务已停止")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dispatch 发送一批到期提醒
+func (w *ReminderWorker) dispatch(ctx context.Context) {
+	now := time.Now()
+	reminders, err := w.reminderRepo.GetDue(ctx, now, w.batchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("获取到期提醒失败", zap.Error(err))
+		}
+		return
+	}
+
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			return
+		}
+
+		//待办事项已删除或已完成时不再通知，直接标记为已处理
+		if reminder.Todo != nil && reminder.Todo.Status != 2 {
+			err := w.notifier.Notify(ctx, Notification{
+				ReminderID: reminder.ID,
+				UserID:     reminder.UserID,
+				TodoID:     reminder.TodoID,
+				Title:      reminder.Todo.Title,
+				DueDate:    reminder.Todo.DueDate,
+				RemindAt:   reminder.RemindAt,
+			})
+			if err != nil {
+				logger.Error("发送提醒失败", zap.Uint("reminder_id", reminder.ID),
+					zap.Int("attempts", reminder.Attempts+1), zap.Error(err))
+				w.retry(ctx, reminder.ID, reminder.Attempts+1, now)
+				continue
+			}
+		}
+
+		if err := w.reminderRepo.MarkSent(ctx, reminder.ID, now); err != nil {
+			logger.Error("标记提醒已发送失败", zap.Uint("reminder_id", reminder.ID), zap.Error(err))
+		}
+	}
+}
+
+// retry 记录发送失败：未达到最大次数时按指数退避安排下次重试，否则标记为放弃
+func (w *ReminderWorker) retry(ctx context.Context, id uint, attempts int, now time.Time) {
+	var err error
+	if attempts >= maxReminderAttempts {
+		err = w.reminderRepo.MarkFailed(ctx, id, attempts, now)
+	} else {
+		err = w.reminderRepo.MarkRetry(ctx, id, attempts, now.Add(reminderRetryDelay<<(attempts-1)))
+	}
+	if err != nil {
+		logger.Error("记录提醒发送失败出错", zap.Uint("reminder_id", id), zap.Error(err))
+	}
+}
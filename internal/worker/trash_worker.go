@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"TODO_API/pkg/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TrashPurger 彻底删除回收站中过期的数据
+type TrashPurger interface {
+	PurgeExpired(ctx context.Context, deletedBefore time.Time, limit int) (int, error)
+}
+
+// TrashWorker 定时彻底删除在回收站中超过保留期的待办事项
+type TrashWorker struct {
+	purger    TrashPurger
+	retention time.Duration
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTrashWorker 创建回收站清理后台任务
+func NewTrashWorker(purger TrashPurger, retention, interval time.Duration, batchSize int) *TrashWorker {
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &TrashWorker{
+		purger:    purger,
+		retention: retention,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Start 启动后台清理
+func (w *TrashWorker) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		logger.Info("回收站清理任务已启动",
+			zap.Duration("retention", w.retention), zap.Duration("interval", w.interval))
+		for {
+			w.purge(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止后台清理并等待当前批次处理完成
+func (w *TrashWorker) Stop(ctx context.Context) error {
+	if w.cancel == nil {
+		return nil
+	}
+	w.cancel()
+	select {
+	case <-w.done:
+		logger.Info("回收站清理任务已停止")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// purge 分批清理过期数据，直到没有剩余或任务被停止
+func (w *TrashWorker) purge(ctx context.Context) {
+	deletedBefore := time.Now().Add(-w.retention)
+	for ctx.Err() == nil {
+		count, err := w.purger.PurgeExpired(ctx, deletedBefore, w.batchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Error("清理回收站失败", zap.Error(err))
+			}
+			return
+		}
+		if count > 0 {
+			logger.Info("已清理回收站中的待办事项", zap.Int("count", count))
+		}
+		if count < w.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package worker
+
+import "context"
+
+// Worker 后台任务，随服务器启动并在优雅关闭时停止
+type Worker interface {
+	Start()
+	Stop(ctx context.Context) error
+}
@@ -0,0 +1,122 @@
+// Package etag 生成实体标签，并按 RFC 9110 比较 If-Match / If-None-Match 请求头。
+//
+// 请求头可以是 * 或以逗号分隔的 ETag 列表，ETag 为 "值" 或带弱标记的 W/"值"。
+// If-Match 使用强比较，弱 ETag 不会匹配；If-None-Match 使用弱比较，忽略弱标记只比较值
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// tag 解析后的一个 ETag
+type tag struct {
+	weak  bool
+	value string // 不含引号
+}
+
+// Compute 由表示的内容生成强 ETag，内容任何变化都会得到不同的 ETag
+func Compute(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// next 解析 s 开头的一个 ETag，返回剩余的内容
+func next(s string) (tag, string, bool) {
+	var t tag
+	if strings.HasPrefix(s, "W/") {
+		t.weak, s = true, s[2:]
+	}
+	if s == "" || s[0] != '"' {
+		return tag{}, "", false
+	}
+	end := strings.IndexByte(s[1:], '"')
+	if end < 0 {
+		return tag{}, "", false
+	}
+	t.value = s[1 : end+1]
+	for i := 0; i < len(t.value); i++ {
+		// etagc = %x21 / %x23-7E / obs-text
+		if c := t.value[i]; c < 0x21 || c == 0x7F {
+			return tag{}, "", false
+		}
+	}
+	return t, s[end+2:], true
+}
+
+// parse 解析单个 ETag
+func parse(s string) (tag, bool) {
+	t, rest, ok := next(s)
+	return t, ok && rest == ""
+}
+
+// parseList 解析请求头中的 ETag 列表，wildcard 表示请求头为 *；
+// 有格式错误时整个请求头无效，不匹配任何 ETag
+func parseList(header string) (tags []tag, wildcard bool, ok bool) {
+	s := strings.TrimSpace(header)
+	if s == "*" {
+		return nil, true, true
+	}
+	for {
+		//按 RFC 9110 的列表语法忽略空项
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			break
+		}
+		t, rest, ok := next(s)
+		if !ok {
+			return nil, false, false
+		}
+		tags = append(tags, t)
+		s = strings.TrimLeft(rest, " \t")
+		if s != "" && s[0] != ',' {
+			return nil, false, false
+		}
+	}
+	return tags, false, len(tags) > 0
+}
+
+// StrongMatch If-Match 的比较：* 匹配任意存在的资源，否则列表中须有与 current 值相同的强 ETag。
+// current 为空表示资源不存在，不匹配任何请求头
+func StrongMatch(header, current string) bool {
+	cur, ok := parse(current)
+	if !ok || cur.weak {
+		return false
+	}
+	tags, wildcard, ok := parseList(header)
+	if !ok {
+		return false
+	}
+	if wildcard {
+		return true
+	}
+	for _, t := range tags {
+		if !t.weak && t.value == cur.value {
+			return true
+		}
+	}
+	return false
+}
+
+// WeakMatch If-None-Match 的比较：* 匹配任意存在的资源，否则列表中有与 current 值相同的 ETag 即匹配，忽略弱标记。
+// current 为空表示资源不存在，不匹配任何请求头
+func WeakMatch(header, current string) bool {
+	cur, ok := parse(current)
+	if !ok {
+		return false
+	}
+	tags, wildcard, ok := parseList(header)
+	if !ok {
+		return false
+	}
+	if wildcard {
+		return true
+	}
+	for _, t := range tags {
+		if t.value == cur.value {
+			return true
+		}
+	}
+	return false
+}
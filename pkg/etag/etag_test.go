@@ -0,0 +1,53 @@
+package etag
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		current string
+		strong  bool
+		weak    bool
+	}{
+		{"相同的强 ETag", `"abc"`, `"abc"`, true, true},
+		{"不同的 ETag", `"abd"`, `"abc"`, false, false},
+		{"弱 ETag 只参与弱比较", `W/"abc"`, `"abc"`, false, true},
+		{"当前 ETag 为弱时强比较不匹配", `"abc"`, `W/"abc"`, false, true},
+		{"列表中的任一项", `"x", W/"y" ,"abc"`, `"abc"`, true, true},
+		{"列表中只有弱 ETag", `"x", W/"abc"`, `"abc"`, false, true},
+		{"忽略空的列表项", ` , "abc",`, `"abc"`, true, true},
+		{"值中可以包含逗号", `"a,b"`, `"a,b"`, true, true},
+		{"不是子串匹配", `"abcd"`, `"abc"`, false, false},
+		{"星号", `*`, `"abc"`, true, true},
+		{"资源不存在时星号不匹配", `*`, ``, false, false},
+		{"空请求头", ``, `"abc"`, false, false},
+		{"缺少引号", `abc`, `"abc"`, false, false},
+		{"引号未闭合", `"abc`, `"abc"`, false, false},
+		{"列表项之间缺少逗号", `"x" "abc"`, `"abc"`, false, false},
+		{"星号不能出现在列表中", `*, "abc"`, `"abc"`, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrongMatch(tt.header, tt.current); got != tt.strong {
+				t.Errorf("StrongMatch(%q, %q) = %v, want %v", tt.header, tt.current, got, tt.strong)
+			}
+			if got := WeakMatch(tt.header, tt.current); got != tt.weak {
+				t.Errorf("WeakMatch(%q, %q) = %v, want %v", tt.header, tt.current, got, tt.weak)
+			}
+		})
+	}
+}
+
+func TestCompute(t *testing.T) {
+	a, b := Compute([]byte(`{"id":1}`)), Compute([]byte(`{"id":2}`))
+	if a == b {
+		t.Fatalf("内容不同时 ETag 应当不同: %s", a)
+	}
+	if !StrongMatch(a, a) {
+		t.Errorf("Compute 应当生成强 ETag: %s", a)
+	}
+	if a != Compute([]byte(`{"id":1}`)) {
+		t.Errorf("相同内容应当生成相同的 ETag")
+	}
+}
@@ -0,0 +1,104 @@
+package filter
+
+import "time"
+
+// Node 筛选表达式语法树节点
+type Node interface {
+	node()
+}
+
+// And 逻辑与
+type And struct {
+	Left, Right Node
+}
+
+// Or 逻辑或
+type Or struct {
+	Left, Right Node
+}
+
+// Not 逻辑非
+type Not struct {
+	Expr Node
+}
+
+// Op 比较运算符
+type Op string
+
+const (
+	OpEq    Op = "="
+	OpNe    Op = "!="
+	OpLt    Op = "<"
+	OpLe    Op = "<="
+	OpGt    Op = ">"
+	OpGe    Op = ">="
+	OpIn    Op = "in"
+	OpNotIn Op = "not in"
+)
+
+// Compare 字段比较，如 priority >= 3、status in (0,1)、due < 2026-11-01
+type Compare struct {
+	Field  Field
+	Op     Op
+	Values []Value // in / not in 时为多个值，其余为一个
+}
+
+// Flag 无参数的条件，如 overdue、has_due、no_due
+type Flag struct {
+	Name string
+}
+
+func (And) node()     {}
+func (Or) node()      {}
+func (Not) node()     {}
+func (Compare) node() {}
+func (Flag) node()    {}
+
+// Value 比较值，数值字段使用 Int，时间字段使用 Time
+type Value struct {
+	Int      int64
+	Time     time.Time
+	DateOnly bool // 只有日期没有时间，比较时按整天处理
+}
+
+// FieldType 字段值类型
+type FieldType int
+
+const (
+	TypeInt FieldType = iota
+	TypeTime
+)
+
+// Field 可筛选的字段
+type Field struct {
+	Name    string
+	Type    FieldType
+	Ordered bool  // 是否支持 < <= > >=，时间字段不支持 in
+	Min     int64 // 数值字段的取值范围，Max 为 0 表示不限
+	Max     int64
+}
+
+// fields 支持的字段，project 为 0 表示收件箱
+var fields = map[string]Field{
+	"status":    {Name: "status", Type: TypeInt, Ordered: true, Min: 0, Max: 2},
+	"priority":  {Name: "priority", Type: TypeInt, Ordered: true, Min: 1, Max: 4},
+	"project":   {Name: "project", Type: TypeInt},
+	"tag":       {Name: "tag", Type: TypeInt, Min: 1},
+	"due":       {Name: "due", Type: TypeTime, Ordered: true},
+	"created":   {Name: "created", Type: TypeTime, Ordered: true},
+	"updated":   {Name: "updated", Type: TypeTime, Ordered: true},
+	"completed": {Name: "completed", Type: TypeTime, Ordered: true},
+}
+
+// 支持的无参数条件
+const (
+	FlagOverdue = "overdue" // 已过截止时间且未完成
+	FlagHasDue  = "has_due" // 设置了截止时间
+	FlagNoDue   = "no_due"  // 未设置截止时间
+)
+
+var flags = map[string]bool{
+	FlagOverdue: true,
+	FlagHasDue:  true,
+	FlagNoDue:   true,
+}
@@ -0,0 +1,344 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxLength 表达式最大长度
+	maxLength = 1000
+	// maxNodes 表达式最多包含的条件数，避免生成过于复杂的查询
+	maxNodes = 50
+	// maxValues in 列表最多包含的值数
+	maxValues = 100
+)
+
+// tokenKind 词法单元类型
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenTime
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token 词法单元，pos 为在表达式中的位置(从1开始)
+type token struct {
+	kind  tokenKind
+	text  string
+	pos   int
+	value Value
+}
+
+// describe 错误信息中对词法单元的描述
+func (t token) describe() string {
+	if t.kind == tokenEOF {
+		return "表达式结尾"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// Error 解析错误，包含出错位置
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("位置 %d: %s", e.Pos, e.Msg)
+}
+
+// lex 将表达式切分为词法单元
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		pos := i + 1
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: pos})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: pos})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			i++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			op := string(c)
+			if i+1 < len(input) && input[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &Error{Pos: pos, Msg: "无效的运算符 \"!\"，不等于请使用 !="}
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: pos})
+			i += len(op)
+		case isLetter(c):
+			start := i
+			for i < len(input) && (isLetter(input[i]) || isDigit(input[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(input[start:i]), pos: pos})
+		case isDigit(c):
+			start := i
+			for i < len(input) && (isLetter(input[i]) || isDigit(input[i]) || strings.IndexByte("-:+.", input[i]) >= 0) {
+				i++
+			}
+			tok, err := literal(input[start:i], pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+		default:
+			return nil, &Error{Pos: pos, Msg: fmt.Sprintf("无法识别的字符 %q", c)}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input) + 1}), nil
+}
+
+// literal 解析数字、日期(2006-01-02)或时间(RFC3339)字面量，日期按服务器时区解释
+func literal(text string, pos int) (token, error) {
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return token{kind: tokenNumber, text: text, pos: pos, value: Value{Int: n}}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", text, time.Local); err == nil {
+		return token{kind: tokenTime, text: text, pos: pos, value: Value{Time: t, DateOnly: true}}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return token{kind: tokenTime, text: text, pos: pos, value: Value{Time: t}}, nil
+	}
+	return token{}, &Error{Pos: pos, Msg: fmt.Sprintf("无效的值 %q，日期格式为 2006-01-02 或 RFC3339", text)}
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser 递归下降解析器，优先级从低到高为 or、and、not
+type parser struct {
+	tokens []token
+	pos    int
+	nodes  int
+}
+
+// Parse 解析筛选表达式，语法：
+//
+//	expr    = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | "(" expr ")" | flag | field op value | field ["not"] "in" "(" value { "," value } ")"
+//
+// 例如 status in (0,1) and priority >= 3 and due < 2026-11-01 and overdue。
+// 未设置的时间字段(如没有截止时间)不满足任何比较，取反后满足，即 not due < 2026-11-01 包含没有截止时间的待办事项
+func Parse(input string) (Node, error) {
+	if len(input) > maxLength {
+		return nil, &Error{Pos: maxLength, Msg: fmt.Sprintf("表达式长度不能超过 %d", maxLength)}
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Pos: 1, Msg: "表达式不能为空"}
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("多余的内容 %s，条件之间请使用 and 或 or 连接", tok.describe())}
+	}
+	return node, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword 当前词法单元为指定关键字时消费并返回 true
+func (p *parser) keyword(word string) bool {
+	if tok := p.peek(); tok.kind == tokenIdent && tok.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect 消费指定类型的词法单元
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		return tok, &Error{Pos: tok.pos, Msg: fmt.Sprintf("期望%s，实际为 %s", what, tok.describe())}
+	}
+	return tok, nil
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.keyword("not") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: expr}, nil
+	}
+
+	if p.peek().kind == tokenLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenRParen, " \")\""); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	tok, err := p.expect(tokenIdent, "字段名或条件")
+	if err != nil {
+		return nil, err
+	}
+	p.nodes++
+	if p.nodes > maxNodes {
+		return nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("条件数量不能超过 %d", maxNodes)}
+	}
+	if flags[tok.text] {
+		return Flag{Name: tok.text}, nil
+	}
+	field, ok := fields[tok.text]
+	if !ok {
+		return nil, &Error{Pos: tok.pos, Msg: fmt.Sprintf("未知的字段 %q", tok.text)}
+	}
+	return p.parseCompare(field, tok)
+}
+
+// parseCompare 解析字段之后的运算符与值
+func (p *parser) parseCompare(field Field, fieldTok token) (Node, error) {
+	opTok := p.next()
+	var op Op
+	switch {
+	case opTok.kind == tokenOp:
+		op = Op(opTok.text)
+		if op == "==" {
+			op = OpEq
+		}
+		if op != OpEq && op != OpNe && !field.Ordered {
+			return nil, &Error{Pos: opTok.pos, Msg: fmt.Sprintf("字段 %s 不支持运算符 %s", field.Name, op)}
+		}
+	case opTok.kind == tokenIdent && opTok.text == "in":
+		op = OpIn
+	case opTok.kind == tokenIdent && opTok.text == "not" && p.keyword("in"):
+		op = OpNotIn
+	default:
+		return nil, &Error{Pos: opTok.pos, Msg: fmt.Sprintf("字段 %s 之后期望运算符，实际为 %s", field.Name, opTok.describe())}
+	}
+
+	if op != OpIn && op != OpNotIn {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		return Compare{Field: field, Op: op, Values: []Value{value}}, nil
+	}
+
+	if field.Type == TypeTime {
+		return nil, &Error{Pos: opTok.pos, Msg: fmt.Sprintf("字段 %s 不支持 in", field.Name)}
+	}
+	if _, err := p.expect(tokenLParen, " \"(\""); err != nil {
+		return nil, err
+	}
+	var values []Value
+	for {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if len(values) > maxValues {
+			return nil, &Error{Pos: fieldTok.pos, Msg: fmt.Sprintf("in 列表不能超过 %d 个值", maxValues)}
+		}
+		if p.peek().kind != tokenComma {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect(tokenRParen, " \")\""); err != nil {
+		return nil, err
+	}
+	return Compare{Field: field, Op: op, Values: values}, nil
+}
+
+// parseValue 解析并校验字段值
+func (p *parser) parseValue(field Field) (Value, error) {
+	tok := p.next()
+	switch field.Type {
+	case TypeInt:
+		if tok.kind != tokenNumber {
+			return Value{}, &Error{Pos: tok.pos, Msg: fmt.Sprintf("字段 %s 期望整数，实际为 %s", field.Name, tok.describe())}
+		}
+		n := tok.value.Int
+		if n < field.Min || field.Max > 0 && n > field.Max {
+			return Value{}, &Error{Pos: tok.pos, Msg: fmt.Sprintf("字段 %s 的值 %d 超出范围", field.Name, n)}
+		}
+	case TypeTime:
+		if tok.kind != tokenTime {
+			return Value{}, &Error{Pos: tok.pos, Msg: fmt.Sprintf("字段 %s 期望日期，实际为 %s", field.Name, tok.describe())}
+		}
+	}
+	return tok.value, nil
+}
@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	overdue, hasDue, noDue := Flag{Name: FlagOverdue}, Flag{Name: FlagHasDue}, Flag{Name: FlagNoDue}
+	tests := []struct {
+		name  string
+		input string
+		want  Node
+	}{
+		{"比较", "priority >= 3",
+			Compare{Field: fields["priority"], Op: OpGe, Values: []Value{{Int: 3}}}},
+		{"== 等同于 =", "status == 1",
+			Compare{Field: fields["status"], Op: OpEq, Values: []Value{{Int: 1}}}},
+		{"字段名不区分大小写", "Status != 1",
+			Compare{Field: fields["status"], Op: OpNe, Values: []Value{{Int: 1}}}},
+		{"in 列表", "status in (0, 1,2)",
+			Compare{Field: fields["status"], Op: OpIn, Values: []Value{{Int: 0}, {Int: 1}, {Int: 2}}}},
+		{"not in 列表", "tag not in (3)",
+			Compare{Field: fields["tag"], Op: OpNotIn, Values: []Value{{Int: 3}}}},
+		{"日期", "due < 2026-11-01",
+			Compare{Field: fields["due"], Op: OpLt, Values: []Value{
+				{Time: time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local), DateOnly: true}}}},
+		{"RFC3339 时间", "updated >= 2026-11-01T08:30:00Z",
+			Compare{Field: fields["updated"], Op: OpGe, Values: []Value{
+				{Time: time.Date(2026, 11, 1, 8, 30, 0, 0, time.UTC)}}}},
+		{"and 优先于 or", "overdue or has_due and no_due",
+			Or{Left: overdue, Right: And{Left: hasDue, Right: noDue}}},
+		{"and 左结合", "overdue and has_due and no_due",
+			And{Left: And{Left: overdue, Right: hasDue}, Right: noDue}},
+		{"not 优先于 and", "not overdue and has_due",
+			And{Left: Not{Expr: overdue}, Right: hasDue}},
+		{"括号", "not (overdue or has_due) and no_due",
+			And{Left: Not{Expr: Or{Left: overdue, Right: hasDue}}, Right: noDue}},
+		{"关键字不区分大小写", "overdue OR NOT has_due",
+			Or{Left: overdue, Right: Not{Expr: hasDue}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"空表达式", "  ", "位置 1: 表达式不能为空"},
+		{"未知字段", "foo = 1", `位置 1: 未知的字段 "foo"`},
+		{"无法识别的字符", "status @ 1", `位置 8: 无法识别的字符 '@'`},
+		{"单独的感叹号", "status ! 1", `位置 8: 无效的运算符 "!"，不等于请使用 !=`},
+		{"缺少值", "status =", "位置 9: 字段 status 期望整数，实际为 表达式结尾"},
+		{"值类型错误", "status = a", `位置 10: 字段 status 期望整数，实际为 "a"`},
+		{"值超出范围", "priority = 5", "位置 12: 字段 priority 的值 5 超出范围"},
+		{"字段不支持大小比较", "project < 1", "位置 9: 字段 project 不支持运算符 <"},
+		{"缺少运算符", "status 1", "位置 8: 字段 status 之后期望运算符，实际为 \"1\""},
+		{"时间字段不支持 in", "due in (2026-11-01)", "位置 5: 字段 due 不支持 in"},
+		{"时间字段期望日期", "due = 3", `位置 7: 字段 due 期望日期，实际为 "3"`},
+		{"无效日期", "due < 2026-13-01", `位置 7: 无效的值 "2026-13-01"，日期格式为 2006-01-02 或 RFC3339`},
+		{"空 in 列表", "status in ()", `位置 12: 字段 status 期望整数，实际为 ")"`},
+		{"in 缺少括号", "status in 1", `位置 11: 期望 "("，实际为 "1"`},
+		{"括号未闭合", "(overdue", `位置 9: 期望 ")"，实际为 表达式结尾`},
+		{"条件之间缺少连接", "status = 1 priority = 2", `位置 12: 多余的内容 "priority"，条件之间请使用 and 或 or 连接`},
+		{"not 之后缺少条件", "not", "位置 4: 期望字段名或条件，实际为 表达式结尾"},
+		{"表达式过长", strings.Repeat(" ", maxLength+1), "位置 1000: 表达式长度不能超过 1000"},
+		{"条件过多", strings.Repeat("overdue or ", maxNodes) + "overdue", "位置 551: 条件数量不能超过 50"},
+		{"in 列表过长", "tag in (" + strings.Repeat("1,", maxValues) + "1)", "位置 1: in 列表不能超过 100 个值"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) 应当返回错误", tt.input)
+			}
+			var parseErr *Error
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse(%q) error 类型为 %T，期望 *Error", tt.input, err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.input, err.Error(), tt.want)
+			}
+		})
+	}
+}
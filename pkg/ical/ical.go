@@ -0,0 +1,244 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 常用组件名
+const (
+	ComponentCalendar = "VCALENDAR"
+	ComponentTodo     = "VTODO"
+)
+
+const (
+	// maxLineOctets RFC 5545 规定每行不超过75个字节(不含换行)，超出部分折行
+	maxLineOctets = 75
+	// dateTimeLayout UTC 时间格式，如 20261018T083000Z
+	dateTimeLayout = "20060102T150405Z"
+	// localDateTimeLayout 不带时区的本地时间格式
+	localDateTimeLayout = "20060102T150405"
+	// dateLayout 日期格式，如 20261018
+	dateLayout = "20060102"
+)
+
+// Property 内容行，如 DUE;VALUE=DATE:20261018
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string // 原始值，文本类型需经过 EscapeText/UnescapeText
+}
+
+// Param 获取参数值，参数名不区分大小写
+func (p *Property) Param(name string) string {
+	return p.Params[strings.ToUpper(name)]
+}
+
+// Component 组件，如 VCALENDAR、VTODO，可嵌套
+type Component struct {
+	Name       string
+	Props      []Property
+	Components []*Component
+}
+
+// NewCalendar 创建包含 VERSION、PRODID 的日历
+func NewCalendar(prodID string) *Component {
+	cal := &Component{Name: ComponentCalendar}
+	cal.Add("VERSION", "2.0")
+	cal.Add("PRODID", prodID)
+	cal.Add("CALSCALE", "GREGORIAN")
+	return cal
+}
+
+// Add 添加属性，value 按原样输出
+func (c *Component) Add(name, value string) *Property {
+	c.Props = append(c.Props, Property{Name: strings.ToUpper(name), Value: value})
+	return &c.Props[len(c.Props)-1]
+}
+
+// AddText 添加文本属性，自动转义
+func (c *Component) AddText(name, text string) {
+	c.Add(name, EscapeText(text))
+}
+
+// AddDateTime 添加 UTC 时间属性
+func (c *Component) AddDateTime(name string, t time.Time) {
+	c.Add(name, FormatDateTime(t))
+}
+
+// Prop 获取第一个同名属性，不存在时返回 nil
+func (c *Component) Prop(name string) *Property {
+	name = strings.ToUpper(name)
+	for i := range c.Props {
+		if c.Props[i].Name == name {
+			return &c.Props[i]
+		}
+	}
+	return nil
+}
+
+// PropsNamed 获取全部同名属性，如多个 CATEGORIES
+func (c *Component) PropsNamed(name string) []Property {
+	name = strings.ToUpper(name)
+	var props []Property
+	for _, prop := range c.Props {
+		if prop.Name == name {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// Text 获取文本属性反转义后的值，不存在时返回空字符串
+func (c *Component) Text(name string) string {
+	if prop := c.Prop(name); prop != nil {
+		return UnescapeText(prop.Value)
+	}
+	return ""
+}
+
+// Children 获取指定名称的子组件
+func (c *Component) Children(name string) []*Component {
+	name = strings.ToUpper(name)
+	var children []*Component
+	for _, child := range c.Components {
+		if child.Name == name {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// Encode 按 RFC 5545 输出组件：CRLF 换行，超过75字节的行折行
+func Encode(w io.Writer, c *Component) error {
+	bw := bufio.NewWriter(w)
+	writeComponent(bw, c)
+	return bw.Flush()
+}
+
+func writeComponent(w *bufio.Writer, c *Component) {
+	writeLine(w, "BEGIN:"+c.Name)
+	for _, prop := range c.Props {
+		writeLine(w, formatProperty(prop))
+	}
+	for _, child := range c.Components {
+		writeComponent(w, child)
+	}
+	writeLine(w, "END:"+c.Name)
+}
+
+// formatProperty 生成内容行，参数按名称排序保证输出稳定
+func formatProperty(prop Property) string {
+	var sb strings.Builder
+	sb.WriteString(prop.Name)
+	names := make([]string, 0, len(prop.Params))
+	for name := range prop.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(";" + name + "=" + quoteParam(prop.Params[name]))
+	}
+	sb.WriteString(":" + prop.Value)
+	return sb.String()
+}
+
+// quoteParam 参数值包含 : ; , 时需加双引号，双引号本身不允许出现
+func quoteParam(value string) string {
+	value = strings.ReplaceAll(value, `"`, "'")
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// writeLine 输出一行，超长时在字符边界折行，续行以空格开头
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // 续行开头的空格占一个字节
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+// EscapeText 转义文本值中的 \ ; , 及换行
+func EscapeText(text string) string {
+	var sb strings.Builder
+	for _, r := range strings.ReplaceAll(text, "\r\n", "\n") {
+		switch r {
+		case '\\', ';', ',':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// UnescapeText 还原转义后的文本值
+func UnescapeText(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+	var sb strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			sb.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			sb.WriteByte('\n')
+		default:
+			sb.WriteByte(value[i])
+		}
+	}
+	return sb.String()
+}
+
+// SplitText 按未转义的逗号拆分多值文本，如 CATEGORIES:工作,学习，返回反转义后的值
+func SplitText(value string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			parts = append(parts, UnescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(parts, UnescapeText(value[start:]))
+}
+
+// JoinText 将多个文本值转义后以逗号连接
+func JoinText(values []string) string {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = EscapeText(value)
+	}
+	return strings.Join(escaped, ",")
+}
+
+// FormatDateTime 格式化为 UTC 时间
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
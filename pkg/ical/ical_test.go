@@ -0,0 +1,286 @@
+package ical
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"买牛奶", "买牛奶"},
+		{"a,b;c", `a\,b\;c`},
+		{`C:\tmp`, `C:\\tmp`},
+		{"第一行\n第二行", `第一行\n第二行`},
+		{"第一行\r\n第二行", `第一行\n第二行`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := EscapeText(tt.text); got != tt.want {
+				t.Errorf("EscapeText(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if got := UnescapeText(tt.want); got != strings.ReplaceAll(tt.text, "\r\n", "\n") {
+				t.Errorf("UnescapeText(%q) = %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUnescapeText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{`a\Nb`, "a\nb"},
+		{`a\:b`, "a:b"},
+		{`末尾的\`, `末尾的\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := UnescapeText(tt.value); got != tt.want {
+				t.Errorf("UnescapeText(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"工作", []string{"工作"}},
+		{"工作,学习", []string{"工作", "学习"}},
+		{`a\,b,c`, []string{"a,b", "c"}},
+		{`a\\,b`, []string{`a\`, "b"}},
+		{"a,,b", []string{"a", "", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := SplitText(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitText(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+			if joined := JoinText(got); joined != tt.value {
+				t.Errorf("JoinText(%q) = %q, want %q", got, joined, tt.value)
+			}
+		})
+	}
+}
+
+func TestEncodeFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"短文本", "买牛奶"},
+		{"ASCII 长文本", strings.Repeat("abcdefghij", 20)},
+		{"多字节长文本", strings.Repeat("完成季度报告", 30)},
+		{"混合长文本", strings.Repeat("a完成b报告,", 25)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := NewCalendar("-//TODO_API//CN")
+			todo := &Component{Name: ComponentTodo}
+			todo.AddText("SUMMARY", tt.text)
+			cal.Components = append(cal.Components, todo)
+
+			var buf bytes.Buffer
+			if err := Encode(&buf, cal); err != nil {
+				t.Fatalf("Encode error: %v", err)
+			}
+			out := buf.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("输出应当以 CRLF 结尾: %q", out)
+			}
+			for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+				if len(line) > maxLineOctets {
+					t.Errorf("行超过 %d 字节: %q", maxLineOctets, line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("折行截断了字符: %q", line)
+				}
+			}
+
+			parsed, err := Parse(&buf)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			todos := parsed.Children(ComponentTodo)
+			if len(todos) != 1 {
+				t.Fatalf("解析出 %d 个 VTODO，期望 1 个", len(todos))
+			}
+			if got := todos[0].Text("SUMMARY"); got != tt.text {
+				t.Errorf("SUMMARY = %q, want %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestEncodeParams(t *testing.T) {
+	c := &Component{Name: ComponentTodo}
+	prop := c.Add("due", "20261018T090000")
+	prop.Params = map[string]string{"TZID": "Asia/Shanghai", "X-NOTE": `a:b"c`}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, c); err != nil {
+		t.Fatalf("Encode error: %v", err)
+	}
+	want := "BEGIN:VTODO\r\nDUE;TZID=Asia/Shanghai;X-NOTE=\"a:b'c\":20261018T090000\r\nEND:VTODO\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Encode = %q, want %q", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := "\ufeffBEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:1@example.com\r\n" +
+		"SUMMARY:写周\r\n" +
+		" 报\r\n" +
+		"CATEGORIES:工作,学习\r\n" +
+		"CATEGORIES:紧急\r\n" +
+		"DUE;TZID=\"Asia/Shanghai\";x-flag=a:20261018T090000\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"ACTION:DISPLAY\r\n" +
+		"END:VALARM\r\n" +
+		"end:vtodo\r\n" +
+		"\r\n" +
+		"END:VCALENDAR\r\n"
+
+	cal, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if cal.Name != ComponentCalendar || cal.Text("version") != "2.0" {
+		t.Fatalf("日历解析错误: %+v", cal)
+	}
+	todos := cal.Children(ComponentTodo)
+	if len(todos) != 1 {
+		t.Fatalf("解析出 %d 个 VTODO，期望 1 个", len(todos))
+	}
+	todo := todos[0]
+	if got := todo.Text("SUMMARY"); got != "写周报" {
+		t.Errorf("SUMMARY = %q, want %q", got, "写周报")
+	}
+	var categories []string
+	for _, prop := range todo.PropsNamed("CATEGORIES") {
+		categories = append(categories, SplitText(prop.Value)...)
+	}
+	if want := []string{"工作", "学习", "紧急"}; !reflect.DeepEqual(categories, want) {
+		t.Errorf("CATEGORIES = %q, want %q", categories, want)
+	}
+	due := todo.Prop("DUE")
+	if due == nil || due.Param("tzid") != "Asia/Shanghai" || due.Param("X-FLAG") != "a" {
+		t.Errorf("DUE 参数解析错误: %+v", due)
+	}
+	if len(todo.Children("VALARM")) != 1 {
+		t.Errorf("应当解析出嵌套的 VALARM")
+	}
+	if todo.Prop("DESCRIPTION") != nil || todo.Text("DESCRIPTION") != "" {
+		t.Errorf("不存在的属性应当返回空值")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"空内容", "", "第 1 行: 缺少 BEGIN:VCALENDAR"},
+		{"缺少冒号", "BEGIN:VCALENDAR\nSUMMARY\nEND:VCALENDAR", `第 2 行: 无效的内容行 "SUMMARY"`},
+		{"缺少属性名", "BEGIN:VCALENDAR\n:abc\nEND:VCALENDAR", `第 2 行: 无效的内容行 ":abc"`},
+		{"参数缺少等号", "BEGIN:VCALENDAR\nDUE;TZID:1\nEND:VCALENDAR", "第 2 行: 属性 DUE 的参数无效"},
+		{"引号未闭合", "BEGIN:VCALENDAR\nDUE;TZID=\"a:1\nEND:VCALENDAR", "第 2 行: 属性 DUE 的参数缺少结束引号"},
+		{"参数后缺少值", "BEGIN:VCALENDAR\nDUE;TZID=a\nEND:VCALENDAR", "第 2 行: 属性 DUE 缺少值"},
+		{"属性在组件外", "VERSION:2.0", "第 1 行: 属性不在任何组件内"},
+		{"END 不匹配", "BEGIN:VCALENDAR\nBEGIN:VTODO\nEND:VCALENDAR", "第 3 行: END:VCALENDAR 与 BEGIN 不匹配"},
+		{"缺少 END", "BEGIN:VCALENDAR\nBEGIN:VTODO\nEND:VTODO", "第 3 行: 缺少 END:VCALENDAR"},
+		{"多个顶层组件", "BEGIN:VCALENDAR\nEND:VCALENDAR\nBEGIN:VCALENDAR", "第 3 行: 只能包含一个顶层组件"},
+		{"嵌套过深", strings.Repeat("BEGIN:X\n", maxDepth+1), "第 6 行: 组件嵌套层数过多"},
+		{"内容行过长", "BEGIN:VCALENDAR\nSUMMARY:" + strings.Repeat(strings.Repeat("a", 1000)+"\n ", 70), "第 67 行: 内容行过长"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatalf("Parse 应当返回错误")
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Parse error = %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPropertyTime(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("缺少时区数据: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("缺少时区数据: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		prop     Property
+		want     time.Time
+		dateOnly bool
+	}{
+		{"UTC 时间", Property{Name: "DUE", Value: "20261018T083000Z"},
+			time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC), false},
+		{"浮动时间按默认时区", Property{Name: "DUE", Value: "20261018T083000"},
+			time.Date(2026, 10, 18, 8, 30, 0, 0, shanghai), false},
+		{"TZID", Property{Name: "DUE", Params: map[string]string{"TZID": "America/New_York"}, Value: "20261018T083000"},
+			time.Date(2026, 10, 18, 8, 30, 0, 0, newYork), false},
+		{"TZID 带斜杠前缀", Property{Name: "DUE", Params: map[string]string{"TZID": "/America/New_York"}, Value: "20261018T083000"},
+			time.Date(2026, 10, 18, 8, 30, 0, 0, newYork), false},
+		{"无法识别的 TZID", Property{Name: "DUE", Params: map[string]string{"TZID": "China Standard Time"}, Value: "20261018T083000"},
+			time.Date(2026, 10, 18, 8, 30, 0, 0, shanghai), false},
+		{"VALUE=DATE", Property{Name: "DUE", Params: map[string]string{"VALUE": "DATE"}, Value: "20261018"},
+			time.Date(2026, 10, 18, 0, 0, 0, 0, shanghai), true},
+		{"只有日期", Property{Name: "DUE", Value: "20261018"},
+			time.Date(2026, 10, 18, 0, 0, 0, 0, shanghai), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dateOnly, err := tt.prop.Time(shanghai)
+			if err != nil {
+				t.Fatalf("Time error: %v", err)
+			}
+			if !got.Equal(tt.want) || dateOnly != tt.dateOnly {
+				t.Errorf("Time = %v, %v, want %v, %v", got, dateOnly, tt.want, tt.dateOnly)
+			}
+		})
+	}
+}
+
+func TestPropertyTimeErrors(t *testing.T) {
+	tests := []struct {
+		prop Property
+		want string
+	}{
+		{Property{Name: "DUE", Params: map[string]string{"VALUE": "DATE"}, Value: "2026-10-18"}, "属性 DUE 的日期无效: 2026-10-18"},
+		{Property{Name: "DUE", Value: "20261318"}, "属性 DUE 的日期无效: 20261318"},
+		{Property{Name: "DTSTART", Value: "20261018T253000Z"}, "属性 DTSTART 的时间无效: 20261018T253000Z"},
+		{Property{Name: "DTSTART", Value: "2026-10-18 08:30"}, "属性 DTSTART 的时间无效: 2026-10-18 08:30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prop.Value, func(t *testing.T) {
+			_, _, err := tt.prop.Time(time.UTC)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Time error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
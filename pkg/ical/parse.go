@@ -0,0 +1,205 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	// maxDepth 组件最大嵌套层数
+	maxDepth = 5
+	// maxLineLength 展开后单行最大长度，避免异常数据占用过多内存
+	maxLineLength = 64 * 1024
+)
+
+// Error 解析错误，包含出错的行号(折行前的物理行)
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("第 %d 行: %s", e.Line, e.Msg)
+}
+
+// Parse 解析 iCalendar 数据，返回最外层组件(通常为 VCALENDAR)
+func Parse(r io.Reader) (*Component, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *Component
+	var stack []*Component
+	for _, line := range lines {
+		prop, err := parseLine(line.text)
+		if err != nil {
+			return nil, &Error{Line: line.number, Msg: err.Error()}
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			if root != nil && len(stack) == 0 {
+				return nil, &Error{Line: line.number, Msg: "只能包含一个顶层组件"}
+			}
+			if len(stack) >= maxDepth {
+				return nil, &Error{Line: line.number, Msg: "组件嵌套层数过多"}
+			}
+			component := &Component{Name: strings.ToUpper(prop.Value)}
+			if len(stack) == 0 {
+				root = component
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, component)
+			}
+			stack = append(stack, component)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(prop.Value) {
+				return nil, &Error{Line: line.number, Msg: fmt.Sprintf("END:%s 与 BEGIN 不匹配", prop.Value)}
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, &Error{Line: line.number, Msg: "属性不在任何组件内"}
+			}
+			current := stack[len(stack)-1]
+			current.Props = append(current.Props, prop)
+		}
+	}
+
+	if root == nil {
+		return nil, &Error{Line: 1, Msg: "缺少 BEGIN:VCALENDAR"}
+	}
+	if len(stack) > 0 {
+		return nil, &Error{Line: len(lines), Msg: fmt.Sprintf("缺少 END:%s", stack[len(stack)-1].Name)}
+	}
+	return root, nil
+}
+
+// contentLine 展开后的内容行及其起始行号
+type contentLine struct {
+	text   string
+	number int
+}
+
+// unfold 读取并展开折行：以空格或制表符开头的行是上一行的延续
+func unfold(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineLength)
+	var lines []contentLine
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if text == "" {
+			continue
+		}
+		if (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			last := &lines[len(lines)-1]
+			if len(last.text)+len(text) > maxLineLength {
+				return nil, &Error{Line: number, Msg: "内容行过长"}
+			}
+			last.text += text[1:]
+			continue
+		}
+		lines = append(lines, contentLine{text: text, number: number})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &Error{Line: number + 1, Msg: err.Error()}
+	}
+	return lines, nil
+}
+
+// parseLine 解析内容行 name *(";" param) ":" value，参数值可用双引号包裹
+func parseLine(line string) (Property, error) {
+	prop := Property{}
+	i := 0
+	for i < len(line) && line[i] != ';' && line[i] != ':' {
+		i++
+	}
+	if i == 0 || i == len(line) {
+		return prop, fmt.Errorf("无效的内容行 %q", truncate(line))
+	}
+	prop.Name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		i++
+		start := i
+		for i < len(line) && line[i] != '=' {
+			i++
+		}
+		if i == len(line) {
+			return prop, fmt.Errorf("属性 %s 的参数无效", prop.Name)
+		}
+		name := strings.ToUpper(line[start:i])
+		i++
+
+		var value strings.Builder
+		for i < len(line) && line[i] != ';' && line[i] != ':' {
+			if line[i] == '"' {
+				end := strings.IndexByte(line[i+1:], '"')
+				if end < 0 {
+					return prop, fmt.Errorf("属性 %s 的参数缺少结束引号", prop.Name)
+				}
+				value.WriteString(line[i+1 : i+1+end])
+				i += end + 2
+				continue
+			}
+			value.WriteByte(line[i])
+			i++
+		}
+		if i == len(line) {
+			return prop, fmt.Errorf("属性 %s 缺少值", prop.Name)
+		}
+		if prop.Params == nil {
+			prop.Params = make(map[string]string)
+		}
+		prop.Params[name] = value.String()
+	}
+
+	prop.Value = line[i+1:]
+	return prop, nil
+}
+
+func truncate(text string) string {
+	if len(text) > 40 {
+		return text[:40] + "..."
+	}
+	return text
+}
+
+// Time 解析日期或时间属性，支持 UTC(Z 结尾)、TZID 参数及浮动时间(按 defaultLoc 解释)；
+// dateOnly 表示只有日期(VALUE=DATE)，返回 defaultLoc 中当天零点
+func (p *Property) Time(defaultLoc *time.Location) (t time.Time, dateOnly bool, err error) {
+	value := strings.TrimSpace(p.Value)
+	if p.Param("VALUE") == "DATE" || len(value) == len(dateLayout) {
+		t, err = time.ParseInLocation(dateLayout, value, defaultLoc)
+		if err != nil {
+			return t, true, fmt.Errorf("属性 %s 的日期无效: %s", p.Name, value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(dateTimeLayout, value)
+	} else {
+		loc := defaultLoc
+		if tzid := p.Param("TZID"); tzid != "" {
+			// 无法识别的时区(如 Windows 时区名)按默认时区处理
+			if tz, tzErr := time.LoadLocation(strings.TrimPrefix(tzid, "/")); tzErr == nil {
+				loc = tz
+			}
+		}
+		t, err = time.ParseInLocation(localDateTimeLayout, value, loc)
+	}
+	if err != nil {
+		return t, false, fmt.Errorf("属性 %s 的时间无效: %s", p.Name, value)
+	}
+	return t, false, nil
+}
@@ -0,0 +1,414 @@
+// Package jsonpatch 将补丁应用到 JSON 文档，支持 RFC 7396 合并补丁(application/merge-patch+json)
+// 与 RFC 6902 JSON Patch(application/json-patch+json)，路径使用 RFC 6901 JSON Pointer。
+//
+// 补丁本身格式错误时返回的错误以“补丁格式错误”开头，补丁无法应用到文档(路径不存在、test 不匹配等)
+// 时以“补丁无法应用”开头。数字按原文保留，不经过浮点数转换
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MergePatchContentType RFC 7396 合并补丁的媒体类型
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType RFC 6902 JSON Patch 的媒体类型
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+// invalid 补丁格式错误
+func invalid(msg string) error {
+	return errors.New("补丁格式错误: " + msg)
+}
+
+// failed 补丁无法应用到文档
+func failed(msg string) error {
+	return errors.New("补丁无法应用: " + msg)
+}
+
+// decode 解析一个完整的 JSON 值，数字保留为 json.Number
+func decode(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("JSON 之后存在多余的内容")
+	}
+	return v, nil
+}
+
+// MergePatch 按 RFC 7396 将合并补丁应用到文档：补丁为对象时逐个成员合并，成员值为 null 表示删除该成员，
+// 其他值(包括数组)整体替换；补丁不是对象时整体替换文档
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	return json.Marshal(merge(target, p))
+}
+
+// merge 将补丁合并到目标值
+func merge(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+			continue
+		}
+		t[key] = merge(t[key], value)
+	}
+	return t
+}
+
+// operation JSON Patch 中的一个操作，value 缺失时为 nil，为 null 时为 "null"
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply 按 RFC 6902 将 JSON Patch 依次应用到文档，任一操作失败时返回错误，文档不做任何修改
+func Apply(doc, patch []byte) ([]byte, error) {
+	root, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil || ops == nil {
+		return nil, invalid("补丁必须是操作数组")
+	}
+
+	for i := range ops {
+		if root, err = apply(root, &ops[i]); err != nil {
+			return nil, errors.New(err.Error() + " (第 " + strconv.Itoa(i+1) + " 个操作)")
+		}
+	}
+	return json.Marshal(root)
+}
+
+// apply 执行一个操作，返回修改后的根节点
+func apply(root any, op *operation) (any, error) {
+	if op.Path == nil {
+		return nil, invalid("缺少 path")
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, invalid(op.Op + " 操作缺少 value")
+		}
+		if value, err = decode(op.Value); err != nil {
+			return nil, invalid(err.Error())
+		}
+	case "move", "copy":
+		if op.From == nil {
+			return nil, invalid(op.Op + " 操作缺少 from")
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = get(root, from); err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			value = clone(value)
+			break
+		}
+		if *op.From == *op.Path {
+			return root, nil
+		}
+		if strings.HasPrefix(*op.Path, *op.From+"/") {
+			return nil, failed("不能移动到自身的子节点")
+		}
+		if root, err = remove(root, from); err != nil {
+			return nil, err
+		}
+	case "remove":
+	default:
+		return nil, invalid("不支持的操作 " + strconv.Quote(op.Op))
+	}
+
+	switch op.Op {
+	case "add", "move", "copy":
+		return add(root, path, value)
+	case "remove":
+		return remove(root, path)
+	case "replace":
+		return replace(root, path, value)
+	default:
+		current, err := get(root, path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(current, value) {
+			return nil, failed("test 操作不匹配: " + pointerText(path))
+		}
+		return root, nil
+	}
+}
+
+// parsePointer 解析 RFC 6901 JSON Pointer，空字符串表示整个文档
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, invalid("路径必须以 / 开头: " + pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] == '~' && (j+1 == len(token) || token[j+1] != '0' && token[j+1] != '1') {
+				return nil, invalid("路径中的转义无效: " + pointer)
+			}
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerText 将路径还原为 JSON Pointer，用于错误信息
+func pointerText(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// arrayIndex 解析数组下标，不允许前导0；appendable 为 true 时允许 - 及等于长度的下标，表示追加到末尾
+func arrayIndex(token string, length int, appendable bool) (int, error) {
+	if appendable && token == "-" {
+		return length, nil
+	}
+	if token == "" || len(token) > 1 && token[0] == '0' || strings.Trim(token, "0123456789") != "" {
+		return 0, failed("数组下标无效: " + token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i > length || i == length && !appendable {
+		return 0, failed("数组下标越界: " + token)
+	}
+	return i, nil
+}
+
+// get 获取路径指向的值
+func get(node any, tokens []string) (any, error) {
+	for i, token := range tokens {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, failed("路径不存在: " + pointerText(tokens[:i+1]))
+			}
+			node = child
+		case []any:
+			idx, err := arrayIndex(token, len(n), false)
+			if err != nil {
+				return nil, err
+			}
+			node = n[idx]
+		default:
+			return nil, failed("路径不存在: " + pointerText(tokens[:i+1]))
+		}
+	}
+	return node, nil
+}
+
+// update 定位路径的父节点，用 fn 修改其中由最后一段路径指定的成员，返回修改后的节点。
+// 数组的长度可能变化，因此逐层把修改后的子节点写回父节点
+func update(node any, tokens []string, fn func(parent any, key string) (any, error)) (any, error) {
+	if len(tokens) == 1 {
+		return fn(node, tokens[0])
+	}
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[tokens[0]]
+		if !ok {
+			return nil, failed("路径不存在: /" + tokens[0])
+		}
+		updated, err := update(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[tokens[0]] = updated
+		return n, nil
+	case []any:
+		idx, err := arrayIndex(tokens[0], len(n), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := update(n[idx], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = updated
+		return n, nil
+	default:
+		return nil, failed("路径不存在: /" + tokens[0])
+	}
+}
+
+// add 在路径处添加值：对象成员已存在时替换，数组在下标处插入
+func add(root any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(root, tokens, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[key] = value
+			return p, nil
+		case []any:
+			idx, err := arrayIndex(key, len(p), true)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, nil)
+			copy(p[idx+1:], p[idx:])
+			p[idx] = value
+			return p, nil
+		default:
+			return nil, failed("父节点不是对象或数组: " + pointerText(tokens))
+		}
+	})
+}
+
+// remove 删除路径处的值，值必须存在
+func remove(root any, tokens []string) (any, error) {
+	if len(tokens) == 0 {
+		return nil, failed("不能删除整个文档")
+	}
+	return update(root, tokens, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[key]; !ok {
+				return nil, failed("路径不存在: " + pointerText(tokens))
+			}
+			delete(p, key)
+			return p, nil
+		case []any:
+			idx, err := arrayIndex(key, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			return append(p[:idx], p[idx+1:]...), nil
+		default:
+			return nil, failed("路径不存在: " + pointerText(tokens))
+		}
+	})
+}
+
+// replace 替换路径处的值，值必须存在
+func replace(root any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return update(root, tokens, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[key]; !ok {
+				return nil, failed("路径不存在: " + pointerText(tokens))
+			}
+			p[key] = value
+			return p, nil
+		case []any:
+			idx, err := arrayIndex(key, len(p), false)
+			if err != nil {
+				return nil, err
+			}
+			p[idx] = value
+			return p, nil
+		default:
+			return nil, failed("路径不存在: " + pointerText(tokens))
+		}
+	})
+}
+
+// clone 深拷贝一个值，copy 操作之后对副本的修改不影响原值
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, child := range v {
+			m[key] = clone(child)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, child := range v {
+			s[i] = clone(child)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// equal 按 RFC 6902 test 操作的规则比较两个值，数字按数值比较，对象不考虑成员顺序
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			other, ok := y[key]
+			if !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		fx, errX := x.Float64()
+		fy, errY := y.Float64()
+		return errX == nil && errY == nil && fx == fy
+	default:
+		return a == b
+	}
+}
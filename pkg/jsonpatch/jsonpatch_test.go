@@ -0,0 +1,217 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// jsonEqual 按 JSON 语义比较，不受成员顺序与空白影响
+func jsonEqual(t *testing.T, got []byte, want string) bool {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("结果不是有效的 JSON: %s", got)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("期望值不是有效的 JSON: %s", want)
+	}
+	return reflect.DeepEqual(g, w)
+}
+
+// RFC 6902 附录 A 的示例及下标、move、test 的边界情况
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"A.1 添加对象成员", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{"A.2 添加数组元素", `{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{"A.3 删除对象成员", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`, `{"foo":"bar"}`},
+		{"A.4 删除数组元素", `{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{"A.5 替换值", `{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{"A.6 移动值", `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{"A.7 移动数组元素", `{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{"A.8 test 成功", `{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`},
+		{"A.10 添加嵌套成员", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, `{"foo":"bar","child":{"grandchild":{}}}`},
+		{"A.11 忽略未识别的成员", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux","xyz":123}]`, `{"foo":"bar","baz":"qux"}`},
+		{"A.14 ~ 转义", `{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":10}]`, `{"/":9,"~1":10}`},
+		{"A.16 添加数组值", `{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, `{"foo":["bar",["abc","def"]]}`},
+		{"- 追加到数组末尾", `{"a":[1,2]}`,
+			`[{"op":"add","path":"/a/-","value":3}]`, `{"a":[1,2,3]}`},
+		{"下标等于长度时追加", `{"a":[1,2]}`,
+			`[{"op":"add","path":"/a/2","value":3}]`, `{"a":[1,2,3]}`},
+		{"替换整个文档", `{"a":1}`,
+			`[{"op":"replace","path":"","value":[1]}]`, `[1]`},
+		{"move 到相同位置", `{"a":{"b":1}}`,
+			`[{"op":"move","from":"/a","path":"/a"}]`, `{"a":{"b":1}}`},
+		{"move 到前缀相同的兄弟成员", `{"a":1,"ab":{}}`,
+			`[{"op":"move","from":"/a","path":"/ab/x"}]`, `{"ab":{"x":1}}`},
+		{"copy 是深拷贝", `{"a":{"b":1}}`,
+			`[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`,
+			`{"a":{"b":1},"c":{"b":2}}`},
+		{"test 数字按数值比较", `{"n":1}`,
+			`[{"op":"test","path":"/n","value":1.0},{"op":"test","path":"/n","value":1e0}]`, `{"n":1}`},
+		{"test 对象不考虑成员顺序", `{"o":{"a":1,"b":[1,2]}}`,
+			`[{"op":"test","path":"/o","value":{"b":[1,2],"a":1}}]`, `{"o":{"a":1,"b":[1,2]}}`},
+		{"value 为 null", `{"a":1}`,
+			`[{"op":"replace","path":"/a","value":null}]`, `{"a":null}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("Apply error: %v", err)
+			}
+			if !jsonEqual(t, got, tt.want) {
+				t.Errorf("Apply = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string // 错误信息的前缀
+	}{
+		{"A.9 test 不匹配", `{"baz":"qux"}`,
+			`[{"op":"test","path":"/baz","value":"bar"}]`, "补丁无法应用: test 操作不匹配: /baz"},
+		{"A.12 父节点不存在", `{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz/bat","value":"qux"}]`, "补丁无法应用: 路径不存在: /baz"},
+		{"A.15 test 字符串与数字不相等", `{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":"10"}]`, "补丁无法应用: test 操作不匹配: /~01"},
+		{"下标有前导0", `{"a":[1,2]}`,
+			`[{"op":"remove","path":"/a/01"}]`, "补丁无法应用: 数组下标无效: 01"},
+		{"- 只能用于添加", `{"a":[1,2]}`,
+			`[{"op":"remove","path":"/a/-"}]`, "补丁无法应用: 数组下标无效: -"},
+		{"负数下标", `{"a":[1,2]}`,
+			`[{"op":"replace","path":"/a/-1","value":0}]`, "补丁无法应用: 数组下标无效: -1"},
+		{"下标越界", `{"a":[1,2]}`,
+			`[{"op":"add","path":"/a/3","value":0}]`, "补丁无法应用: 数组下标越界: 3"},
+		{"替换时下标等于长度", `{"a":[1,2]}`,
+			`[{"op":"replace","path":"/a/2","value":0}]`, "补丁无法应用: 数组下标越界: 2"},
+		{"移动到自身的子节点", `{"a":{"b":{}}}`,
+			`[{"op":"move","from":"/a","path":"/a/b/c"}]`, "补丁无法应用: 不能移动到自身的子节点"},
+		{"删除不存在的成员", `{"a":1}`,
+			`[{"op":"remove","path":"/b"}]`, "补丁无法应用: 路径不存在: /b"},
+		{"替换不存在的成员", `{"a":1}`,
+			`[{"op":"replace","path":"/b","value":1}]`, "补丁无法应用: 路径不存在: /b"},
+		{"A.13 缺少 value", `{"a":1}`,
+			`[{"op":"add","path":"/b"}]`, "补丁格式错误: add 操作缺少 value"},
+		{"缺少 path", `{"a":1}`,
+			`[{"op":"remove"}]`, "补丁格式错误: 缺少 path"},
+		{"缺少 from", `{"a":1}`,
+			`[{"op":"move","path":"/b"}]`, "补丁格式错误: move 操作缺少 from"},
+		{"不支持的操作", `{"a":1}`,
+			`[{"op":"merge","path":"/a"}]`, `补丁格式错误: 不支持的操作 "merge"`},
+		{"路径不以 / 开头", `{"a":1}`,
+			`[{"op":"remove","path":"a"}]`, "补丁格式错误: 路径必须以 / 开头: a"},
+		{"无效的转义", `{"a":1}`,
+			`[{"op":"remove","path":"/~2"}]`, "补丁格式错误: 路径中的转义无效: /~2"},
+		{"补丁不是数组", `{"a":1}`,
+			`{"op":"remove","path":"/a"}`, "补丁格式错误: 补丁必须是操作数组"},
+		{"错误信息包含操作序号", `{"a":1}`,
+			`[{"op":"remove","path":"/a"},{"op":"remove","path":"/a"}]`, "补丁无法应用: 路径不存在: /a (第 2 个操作)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			if err == nil {
+				t.Fatalf("Apply 应当返回错误")
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("Apply error = %q, want prefix %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+// 数字按原文保留，超出浮点精度的整数不会被改变
+func TestApplyKeepsNumbers(t *testing.T) {
+	got, err := Apply([]byte(`{"n":1,"m":9007199254740993}`), []byte(`[{"op":"replace","path":"/n","value":9007199254740995}]`))
+	if err != nil {
+		t.Fatalf("Apply error: %v", err)
+	}
+	if want := `{"m":9007199254740993,"n":9007199254740995}`; string(got) != want {
+		t.Errorf("Apply = %s, want %s", got, want)
+	}
+}
+
+// 失败的补丁不能修改原文档
+func TestApplyAtomic(t *testing.T) {
+	doc := []byte(`{"a":[1,2]}`)
+	if _, err := Apply(doc, []byte(`[{"op":"add","path":"/a/-","value":3},{"op":"test","path":"/b","value":1}]`)); err == nil {
+		t.Fatal("Apply 应当返回错误")
+	}
+	if string(doc) != `{"a":[1,2]}` {
+		t.Errorf("原文档被修改: %s", doc)
+	}
+}
+
+// RFC 7396 附录 A 的示例
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+		// 删除不存在的成员不报错，数组中的 null 按原样保留
+		{`{"a":1}`, `{"b":null}`, `{"a":1}`},
+		{`{"a":1}`, `{"b":[null]}`, `{"a":1,"b":[null]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch error: %v", err)
+			}
+			if !jsonEqual(t, got, tt.want) {
+				t.Errorf("MergePatch(%s, %s) = %s, want %s", tt.doc, tt.patch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergePatchInvalid(t *testing.T) {
+	for _, patch := range []string{`{"a":`, `{"a":1} {}`, ``} {
+		_, err := MergePatch([]byte(`{}`), []byte(patch))
+		if err == nil || !strings.HasPrefix(err.Error(), "补丁格式错误") {
+			t.Errorf("MergePatch(%q) error = %v, want 补丁格式错误", patch, err)
+		}
+	}
+}
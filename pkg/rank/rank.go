@@ -0,0 +1,276 @@
+// Package rank 生成可按字典序比较的分数排序键：在任意两个键之间总能生成新的键，
+// 移动一项时只需修改这一项的键。
+//
+// 键由整数部分和小数部分组成。整数部分首字符表示长度(a-z 依次为2-27位，Z-A 依次为2-27位，
+// 大写表示负数)，其余为62进制数字；小数部分为62进制数字且不以0结尾。在首尾追加时只增减整数部分，
+// 键的长度按对数增长。数据库中比较时需使用二进制排序规则
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// digits 62进制数字，按 ASCII 顺序排列
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// smallestInteger 最小的整数部分，其前面只能通过小数部分插入
+var smallestInteger = "A" + strings.Repeat(string(digits[0]), 26)
+
+// ErrInvalidKey 键格式无效
+var ErrInvalidKey = errors.New("排序键无效")
+
+// integerLength 整数部分的长度(含首字符)
+func integerLength(head byte) (int, bool) {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2, true
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2, true
+	default:
+		return 0, false
+	}
+}
+
+// split 将键拆分为整数部分和小数部分
+func split(key string) (string, string, error) {
+	if key == "" {
+		return "", "", ErrInvalidKey
+	}
+	n, ok := integerLength(key[0])
+	if !ok || n > len(key) {
+		return "", "", ErrInvalidKey
+	}
+	return key[:n], key[n:], nil
+}
+
+// Valid 检查键是否有效
+func Valid(key string) bool {
+	if key == smallestInteger {
+		return false
+	}
+	integer, fraction, err := split(key)
+	if err != nil {
+		return false
+	}
+	for i := 1; i < len(integer); i++ {
+		if strings.IndexByte(digits, integer[i]) < 0 {
+			return false
+		}
+	}
+	for i := 0; i < len(fraction); i++ {
+		if strings.IndexByte(digits, fraction[i]) < 0 {
+			return false
+		}
+	}
+	return !strings.HasSuffix(fraction, digits[:1])
+}
+
+// Between 生成位于 a、b 之间的键，空字符串表示没有边界；两者都为空时返回初始键
+func Between(a, b string) (string, error) {
+	if (a != "" && !Valid(a)) || (b != "" && !Valid(b)) {
+		return "", ErrInvalidKey
+	}
+	if a != "" && b != "" && a >= b {
+		return "", errors.New("排序键顺序无效")
+	}
+
+	switch {
+	case a == "" && b == "":
+		return "a" + digits[:1], nil
+	case a == "":
+		integer, fraction, _ := split(b)
+		if integer == smallestInteger {
+			return integer + midpoint("", fraction), nil
+		}
+		if integer < b {
+			return integer, nil
+		}
+		prev, ok := decrement(integer)
+		if !ok {
+			return "", errors.New("排序键超出范围")
+		}
+		return prev, nil
+	case b == "":
+		integer, fraction, _ := split(a)
+		next, ok := increment(integer)
+		if !ok {
+			return integer + midpoint(fraction, ""), nil
+		}
+		return next, nil
+	}
+
+	integerA, fractionA, _ := split(a)
+	integerB, fractionB, _ := split(b)
+	if integerA == integerB {
+		return integerA + midpoint(fractionA, fractionB), nil
+	}
+	next, ok := increment(integerA)
+	if !ok {
+		return "", errors.New("排序键超出范围")
+	}
+	if next < b {
+		return next, nil
+	}
+	return integerA + midpoint(fractionA, ""), nil
+}
+
+// NBetween 生成 n 个位于 a、b 之间且递增的键
+func NBetween(a, b string, n int) ([]string, error) {
+	keys := make([]string, 0, n)
+	switch {
+	case n <= 0:
+		return keys, nil
+	case b == "":
+		// 向后追加，每次只增加整数部分
+		for range n {
+			key, err := Between(a, b)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			a = key
+		}
+		return keys, nil
+	case a == "":
+		for range n {
+			key, err := Between(a, b)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			b = key
+		}
+		for i, j := 0, len(keys)-1; i < j; i, j = i+1, j-1 {
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+		return keys, nil
+	}
+
+	// 两端都有边界时二分生成，键的长度按对数增长
+	mid := n / 2
+	key, err := Between(a, b)
+	if err != nil {
+		return nil, err
+	}
+	left, err := NBetween(a, key, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := NBetween(key, b, n-mid-1)
+	if err != nil {
+		return nil, err
+	}
+	keys = append(append(append(keys, left...), key), right...)
+	return keys, nil
+}
+
+// midpoint 生成位于两个小数部分之间的小数部分，b 为空表示没有上界；要求 a < b 且都不以0结尾
+func midpoint(a, b string) string {
+	if b != "" {
+		// 去掉相同的前缀，a 较短时按0补齐
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+	// 首位相邻时，b 的首位本身即位于两者之间
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(digits[digitA]) + midpoint(rest, "")
+}
+
+// digitAt 取第 i 位数字，超出长度时为0
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return digits[0]
+}
+
+// increment 整数部分加一，超出最大值时返回 false
+func increment(integer string) (string, bool) {
+	head, digs := integer[0], []byte(integer[1:])
+	carry := true
+	for i := len(digs) - 1; carry && i >= 0; i-- {
+		d := strings.IndexByte(digits, digs[i]) + 1
+		if d == len(digits) {
+			digs[i] = digits[0]
+		} else {
+			digs[i] = digits[d]
+			carry = false
+		}
+	}
+	if !carry {
+		return string(head) + string(digs), true
+	}
+	switch head {
+	case 'Z':
+		return "a" + digits[:1], true
+	case 'z':
+		return "", false
+	}
+	head++
+	// 正数部分长度随首字符增加，负数部分则减少
+	if head > 'a' {
+		digs = append(digs, digits[0])
+	} else {
+		digs = digs[:len(digs)-1]
+	}
+	return string(head) + string(digs), true
+}
+
+// decrement 整数部分减一，低于最小值时返回 false
+func decrement(integer string) (string, bool) {
+	head, digs := integer[0], []byte(integer[1:])
+	borrow := true
+	for i := len(digs) - 1; borrow && i >= 0; i-- {
+		d := strings.IndexByte(digits, digs[i]) - 1
+		if d == -1 {
+			digs[i] = digits[len(digits)-1]
+		} else {
+			digs[i] = digits[d]
+			borrow = false
+		}
+	}
+	if !borrow {
+		return string(head) + string(digs), true
+	}
+	switch head {
+	case 'a':
+		return "Z" + digits[len(digits)-1:], true
+	case 'A':
+		return "", false
+	}
+	head--
+	if head < 'Z' {
+		digs = append(digs, digits[len(digits)-1])
+	} else {
+		digs = digs[:len(digs)-1]
+	}
+	return string(head) + string(digs), true
+}
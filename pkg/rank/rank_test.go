@@ -0,0 +1,146 @@
+package rank
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "a0"},
+		{"", "a0", "Zz"},
+		{"", "Zz", "Zy"},
+		{"a0", "", "a1"},
+		{"a1", "", "a2"},
+		{"a0", "a1", "a0V"},
+		{"a1", "a2", "a1V"},
+		{"a0V", "a1", "a0l"},
+		{"Zz", "a0", "ZzV"},
+		{"Zz", "a1", "a0"},
+		{"", "Y00", "Xzzz"},
+		{"bzz", "", "c000"},
+		{"a0", "a0V", "a0G"},
+		{"a0", "a0G", "a08"},
+		{"b125", "b129", "b127"},
+		{"a0", "a1V", "a1"},
+		{"Zz", "a01", "a0"},
+		{"", "a0V", "a0"},
+		{"", "b999", "b99"},
+		{"az", "", "b00"},
+		{"Zz", "", "a0"},
+		// 整数部分已是最小值或最大值时只能通过小数部分继续生成
+		{"", "A000000000000000000000000001", "A000000000000000000000000000V"},
+		{"zzzzzzzzzzzzzzzzzzzzzzzzzzy", "", "zzzzzzzzzzzzzzzzzzzzzzzzzzz"},
+		{"zzzzzzzzzzzzzzzzzzzzzzzzzzz", "", "zzzzzzzzzzzzzzzzzzzzzzzzzzzV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"|"+tt.b, func(t *testing.T) {
+			got, err := Between(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("Between(%q, %q) error: %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBetweenErrors(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "A00000000000000000000000000", "排序键无效"},
+		{"a00", "", "排序键无效"},
+		{"a0", "a1 ", "排序键无效"},
+		{"0", "", "排序键无效"},
+		{"b1", "", "排序键无效"},
+		{"a0", "a0", "排序键顺序无效"},
+		{"a1", "a0", "排序键顺序无效"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"|"+tt.b, func(t *testing.T) {
+			_, err := Between(tt.a, tt.b)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Between(%q, %q) error = %v, want %q", tt.a, tt.b, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		n    int
+	}{
+		{"", "", 5},
+		{"a0", "", 100},
+		{"", "a0", 100},
+		{"a0", "a1", 100},
+		{"a0", "a0V", 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"|"+tt.b, func(t *testing.T) {
+			keys, err := NBetween(tt.a, tt.b, tt.n)
+			if err != nil {
+				t.Fatalf("NBetween error: %v", err)
+			}
+			if len(keys) != tt.n {
+				t.Fatalf("生成了 %d 个键，期望 %d 个", len(keys), tt.n)
+			}
+			checkOrdered(t, tt.a, keys, tt.b)
+		})
+	}
+}
+
+// 随机位置反复插入，键始终有效且有序，长度不会无限增长
+func TestRandomInsert(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	keys := []string{}
+	for range 2000 {
+		i := r.Intn(len(keys) + 1)
+		var a, b string
+		if i > 0 {
+			a = keys[i-1]
+		}
+		if i < len(keys) {
+			b = keys[i]
+		}
+		key, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q) error: %v", a, b, err)
+		}
+		keys = append(keys[:i], append([]string{key}, keys[i:]...)...)
+	}
+	checkOrdered(t, "", keys, "")
+	for _, key := range keys {
+		if len(key) > 20 {
+			t.Errorf("键过长: %q", key)
+		}
+	}
+}
+
+// checkOrdered 检查键都有效、严格递增且位于 a、b 之间，比较按字节进行
+func checkOrdered(t *testing.T, a string, keys []string, b string) {
+	t.Helper()
+	if !sort.StringsAreSorted(keys) {
+		t.Fatalf("键未按字典序排列: %v", keys)
+	}
+	for i, key := range keys {
+		if !Valid(key) {
+			t.Fatalf("生成了无效的键 %q", key)
+		}
+		if i > 0 && keys[i-1] == key {
+			t.Fatalf("生成了重复的键 %q", key)
+		}
+		if a != "" && strings.Compare(key, a) <= 0 || b != "" && strings.Compare(key, b) >= 0 {
+			t.Fatalf("键 %q 不在 %q 与 %q 之间", key, a, b)
+		}
+	}
+}
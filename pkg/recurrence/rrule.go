@@ -0,0 +1,258 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency 重复频率
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+const untilLayout = "20060102T150405Z"
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule RRULE 规则的子集：FREQ、INTERVAL、BYDAY、BYMONTHDAY、UNTIL、COUNT
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByWeekday  []time.Weekday
+	ByMonthDay int        // 按月重复时的日期，超过当月天数时取当月最后一天
+	Until      *time.Time // 截止时间(含)
+	Count      int        // 剩余重复次数(含当前一次)，0 表示不限
+}
+
+// Parse 解析 RRULE 字符串，如 FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	rule := &Rule{Interval: 1}
+
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("无效的重复规则片段: %s", part)
+		}
+		key, value := strings.ToUpper(kv[0]), kv[1]
+
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(value))
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("无效的INTERVAL: %s", value)
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				weekday, ok := weekdayCodes[strings.ToUpper(code)]
+				if !ok {
+					return nil, fmt.Errorf("无效的BYDAY: %s", code)
+				}
+				rule.ByWeekday = append(rule.ByWeekday, weekday)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("无效的BYMONTHDAY: %s", value)
+			}
+			rule.ByMonthDay = n
+		case "UNTIL":
+			until, err := time.Parse(untilLayout, value)
+			if err != nil {
+				return nil, fmt.Errorf("无效的UNTIL: %s", value)
+			}
+			rule.Until = &until
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("无效的COUNT: %s", value)
+			}
+			rule.Count = n
+		default:
+			return nil, fmt.Errorf("不支持的重复规则字段: %s", key)
+		}
+	}
+
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Validate 校验规则
+func (r *Rule) Validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly:
+	default:
+		return errors.New("重复频率只支持 DAILY、WEEKLY、MONTHLY")
+	}
+	if r.Interval < 1 {
+		return errors.New("重复间隔必须大于0")
+	}
+	if len(r.ByWeekday) > 0 && r.Freq != Weekly {
+		return errors.New("BYDAY 只能用于 WEEKLY")
+	}
+	if r.ByMonthDay != 0 && (r.Freq != Monthly || r.ByMonthDay < 1 || r.ByMonthDay > 31) {
+		return errors.New("BYMONTHDAY 只能用于 MONTHLY 且取值为1-31")
+	}
+	if r.Count < 0 {
+		return errors.New("COUNT 不能为负数")
+	}
+	return nil
+}
+
+// String 序列化为 RRULE 字符串
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByWeekday) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(r.WeekdayCodes(), ","))
+	}
+	if r.ByMonthDay > 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// WeekdayCodes 返回 BYDAY 的两字母代码，按周一到周日排序
+func (r *Rule) WeekdayCodes() []string {
+	codes := make([]string, 0, len(r.ByWeekday))
+	for _, code := range []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"} {
+		if r.hasWeekday(weekdayCodes[code]) {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// ParseWeekday 解析两字母星期代码
+func ParseWeekday(code string) (time.Weekday, bool) {
+	weekday, ok := weekdayCodes[strings.ToUpper(code)]
+	return weekday, ok
+}
+
+func (r *Rule) hasWeekday(weekday time.Weekday) bool {
+	for _, w := range r.ByWeekday {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// Next 计算 prev 之后的下一次发生时间，规则已结束时返回 false
+func (r *Rule) Next(prev time.Time) (time.Time, bool) {
+	if r.Count == 1 {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch r.Freq {
+	case Daily:
+		next = prev.AddDate(0, 0, r.Interval)
+	case Weekly:
+		next = r.nextWeekly(prev)
+	case Monthly:
+		next = r.nextMonthly(prev)
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// nextWeekly 先在 prev 所在周内查找，否则跳到 INTERVAL 周之后的那一周
+func (r *Rule) nextWeekly(prev time.Time) time.Time {
+	if len(r.ByWeekday) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval)
+	}
+
+	// 以周一作为一周的开始
+	offset := (int(prev.Weekday()) + 6) % 7
+	for d := offset + 1; d < 7; d++ {
+		day := prev.AddDate(0, 0, d-offset)
+		if r.hasWeekday(day.Weekday()) {
+			return day
+		}
+	}
+	weekStart := prev.AddDate(0, 0, 7*r.Interval-offset)
+	for d := 0; d < 7; d++ {
+		day := weekStart.AddDate(0, 0, d)
+		if r.hasWeekday(day.Weekday()) {
+			return day
+		}
+	}
+	return weekStart
+}
+
+// nextMonthly 月份相加后按 BYMONTHDAY 取日，避免 1月31日 变成 3月3日
+func (r *Rule) nextMonthly(prev time.Time) time.Time {
+	day := r.ByMonthDay
+	if day == 0 {
+		day = prev.Day()
+	}
+	firstOfMonth := time.Date(prev.Year(), prev.Month(), 1,
+		prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+	target := firstOfMonth.AddDate(0, r.Interval, 0)
+	lastDay := target.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return target.AddDate(0, 0, day-1)
+}
+
+// Advance 返回生成下一次实例后使用的规则，COUNT 减一
+func (r *Rule) Advance() *Rule {
+	next := *r
+	if next.Count > 0 {
+		next.Count--
+	}
+	return &next
+}
+
+// Preview 从 start 开始列出最多 n 次发生时间(包含 start 本身)
+func (r *Rule) Preview(start time.Time, n int) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+	current, rule := start, r
+	for len(occurrences) < n {
+		occurrences = append(occurrences, current)
+		next, ok := rule.Next(current)
+		if !ok {
+			break
+		}
+		current, rule = next, rule.Advance()
+	}
+	return occurrences
+}
@@ -0,0 +1,132 @@
+package recurrence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestParseString(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;byday=fr,mo;interval=2;count=5", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=5"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{"FREQ=DAILY;UNTIL=20261231T235959Z;", "FREQ=DAILY;UNTIL=20261231T235959Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			rule, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", "重复频率只支持 DAILY、WEEKLY、MONTHLY"},
+		{"FREQ=YEARLY", "重复频率只支持 DAILY、WEEKLY、MONTHLY"},
+		{"FREQ", "无效的重复规则片段: FREQ"},
+		{"FREQ=DAILY;FOO=1", "不支持的重复规则字段: FOO"},
+		{"FREQ=DAILY;INTERVAL=0", "重复间隔必须大于0"},
+		{"FREQ=DAILY;INTERVAL=x", "无效的INTERVAL: x"},
+		{"FREQ=WEEKLY;BYDAY=MO,XX", "无效的BYDAY: XX"},
+		{"FREQ=DAILY;BYDAY=MO", "BYDAY 只能用于 WEEKLY"},
+		{"FREQ=MONTHLY;BYMONTHDAY=32", "BYMONTHDAY 只能用于 MONTHLY 且取值为1-31"},
+		{"FREQ=WEEKLY;BYMONTHDAY=1", "BYMONTHDAY 只能用于 MONTHLY 且取值为1-31"},
+		{"FREQ=DAILY;UNTIL=2026-11-01", "无效的UNTIL: 2026-11-01"},
+		{"FREQ=DAILY;COUNT=-1", "COUNT 不能为负数"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			if err == nil {
+				t.Fatalf("Parse(%q) 应当返回错误", tt.input)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Parse(%q) error = %q, want %q", tt.input, err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		n     int
+		want  []time.Time
+	}{
+		{"每天", "FREQ=DAILY", date(2026, 11, 30), 3,
+			[]time.Time{date(2026, 11, 30), date(2026, 12, 1), date(2026, 12, 2)}},
+		{"每隔两天", "FREQ=DAILY;INTERVAL=2", date(2026, 11, 1), 3,
+			[]time.Time{date(2026, 11, 1), date(2026, 11, 3), date(2026, 11, 5)}},
+		{"每周", "FREQ=WEEKLY", date(2026, 11, 2), 2,
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 9)}},
+		// 2026-11-02 为周一，本周内先取周五，之后跳过一周
+		{"隔周的周一和周五", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", date(2026, 11, 2), 4,
+			[]time.Time{date(2026, 11, 2), date(2026, 11, 6), date(2026, 11, 16), date(2026, 11, 20)}},
+		{"开始日期不在 BYDAY 中", "FREQ=WEEKLY;BYDAY=WE", date(2026, 11, 5), 2,
+			[]time.Time{date(2026, 11, 5), date(2026, 11, 11)}},
+		// 没有 BYMONTHDAY 时按上一次的日期，月末被截断后不再恢复
+		{"每月", "FREQ=MONTHLY", date(2026, 1, 31), 3,
+			[]time.Time{date(2026, 1, 31), date(2026, 2, 28), date(2026, 3, 28)}},
+		{"每月最后一天", "FREQ=MONTHLY;BYMONTHDAY=31", date(2026, 1, 31), 4,
+			[]time.Time{date(2026, 1, 31), date(2026, 2, 28), date(2026, 3, 31), date(2026, 4, 30)}},
+		{"闰年二月", "FREQ=MONTHLY;BYMONTHDAY=30", date(2028, 1, 30), 2,
+			[]time.Time{date(2028, 1, 30), date(2028, 2, 29)}},
+		{"COUNT 包含当前一次", "FREQ=DAILY;COUNT=3", date(2026, 11, 1), 10,
+			[]time.Time{date(2026, 11, 1), date(2026, 11, 2), date(2026, 11, 3)}},
+		{"UNTIL 包含截止时间", "FREQ=DAILY;UNTIL=20261103T093000Z", date(2026, 11, 1), 10,
+			[]time.Time{date(2026, 11, 1), date(2026, 11, 2), date(2026, 11, 3)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if got := rule.Preview(tt.start, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Preview = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=2")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	next := rule.Advance()
+	if rule.Count != 2 || next.Count != 1 {
+		t.Fatalf("Advance 后 COUNT 为 %d，原规则为 %d", next.Count, rule.Count)
+	}
+	if _, ok := next.Next(date(2026, 11, 1)); ok {
+		t.Error("COUNT=1 时不应再有下一次")
+	}
+
+	unlimited, err := Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if unlimited.Advance().Count != 0 {
+		t.Error("不限次数的规则 Advance 后应当仍不限次数")
+	}
+}
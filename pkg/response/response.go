@@ -47,3 +47,23 @@ func Forbidden(c *gin.Context, message string) {
 func NotFound(c *gin.Context, message string) {
 	Error(c, http.StatusNotFound, message)
 }
+
+func PreconditionFailed(c *gin.Context, message string) {
+	Error(c, http.StatusPreconditionFailed, message)
+}
+
+func PreconditionRequired(c *gin.Context, message string) {
+	Error(c, http.StatusPreconditionRequired, message)
+}
+
+func UnsupportedMediaType(c *gin.Context, message string) {
+	Error(c, http.StatusUnsupportedMediaType, message)
+}
+
+func UnprocessableEntity(c *gin.Context, message string) {
+	Error(c, http.StatusUnprocessableEntity, message)
+}
+
+func Conflict(c *gin.Context, message string) {
+	Error(c, http.StatusConflict, message)
+}
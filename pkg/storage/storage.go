@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("文件不存在")
+
+// Storage 文件存储后端接口，key 使用 / 分隔的相对路径
+type Storage interface {
+	// Put 写入对象，size 为内容长度
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象，调用方负责关闭
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+}
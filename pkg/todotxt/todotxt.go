@@ -0,0 +1,215 @@
+// Package todotxt 解析与生成 todo.txt 格式(https://github.com/todotxt/todo.txt)的任务列表，
+// 每行一个任务：完成标记 x、优先级 (A)、完成及创建日期、描述，描述中可包含 +项目、@上下文 及 key:value 标签
+package todotxt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout todo.txt 中的日期格式
+	DateLayout = "2006-01-02"
+	// maxLineLength 单行最大长度
+	maxLineLength = 64 * 1024
+	// priorityKey 已完成任务按惯例将优先级保存为 pri:A
+	priorityKey = "pri"
+)
+
+var (
+	// priorityPattern 行首的优先级，如 (A)
+	priorityPattern = regexp.MustCompile(`^\(([A-Z])\)$`)
+	// tagPattern key:value 标签，键以字母开头，值中不含冒号，排除 http://... 这类地址
+	tagPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_-]*):([^:\s/][^:\s]*)$`)
+)
+
+// Tag key:value 标签
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Task 一行任务
+type Task struct {
+	Completed      bool
+	Priority       byte // 'A'-'Z'，0 表示没有优先级
+	CompletionDate *time.Time
+	CreationDate   *time.Time
+	Text           string   // 去除项目、上下文及标签后的描述
+	Projects       []string // +项目，不含前缀
+	Contexts       []string // @上下文，不含前缀
+	Tags           []Tag    // 按出现顺序
+}
+
+// Tag 获取第一个指定键的标签值，键区分大小写
+func (t *Task) Tag(key string) (string, bool) {
+	for _, tag := range t.Tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+// Line 文件中的一行，Number 从1开始，解析失败时 Err 不为空
+type Line struct {
+	Number int
+	Task   *Task
+	Err    error
+}
+
+// Parse 逐行解析 todo.txt，跳过空行；单行解析失败不影响其余行
+func Parse(r io.Reader) ([]Line, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineLength)
+	var lines []Line
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := scanner.Text()
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		task, err := ParseLine(text)
+		lines = append(lines, Line{Number: number, Task: task, Err: err})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// ParseLine 解析一行任务
+func ParseLine(line string) (*Task, error) {
+	words := strings.Fields(line)
+	task := &Task{}
+
+	if len(words) > 0 && words[0] == "x" {
+		task.Completed = true
+		words = words[1:]
+	}
+	// 部分客户端完成后仍保留 (A)
+	if len(words) > 0 {
+		if match := priorityPattern.FindStringSubmatch(words[0]); match != nil {
+			task.Priority = match[1][0]
+			words = words[1:]
+		}
+	}
+	// 已完成任务的第一个日期为完成日期，第二个为创建日期；未完成任务只有创建日期
+	if date, ok := parseDate(words); ok {
+		words = words[1:]
+		if task.Completed {
+			task.CompletionDate = &date
+			if date, ok := parseDate(words); ok {
+				task.CreationDate = &date
+				words = words[1:]
+			}
+		} else {
+			task.CreationDate = &date
+		}
+	}
+
+	var text []string
+	for _, word := range words {
+		switch {
+		case len(word) > 1 && word[0] == '+':
+			task.Projects = append(task.Projects, word[1:])
+		case len(word) > 1 && word[0] == '@':
+			task.Contexts = append(task.Contexts, word[1:])
+		default:
+			if match := tagPattern.FindStringSubmatch(word); match != nil {
+				task.Tags = append(task.Tags, Tag{Key: match[1], Value: match[2]})
+			} else {
+				text = append(text, word)
+			}
+		}
+	}
+	task.Text = strings.Join(text, " ")
+
+	if task.Completed && task.Priority == 0 {
+		if value, ok := task.Tag(priorityKey); ok && len(value) == 1 && value[0] >= 'A' && value[0] <= 'Z' {
+			task.Priority = value[0]
+			task.removeTag(priorityKey)
+		}
+	}
+
+	if task.Text == "" {
+		return task, errors.New("任务描述为空")
+	}
+	return task, nil
+}
+
+// parseDate 解析第一个单词是否为日期
+func parseDate(words []string) (time.Time, bool) {
+	if len(words) == 0 {
+		return time.Time{}, false
+	}
+	date, err := time.ParseInLocation(DateLayout, words[0], time.Local)
+	return date, err == nil
+}
+
+// removeTag 移除指定键的全部标签
+func (t *Task) removeTag(key string) {
+	tags := t.Tags[:0]
+	for _, tag := range t.Tags {
+		if tag.Key != key {
+			tags = append(tags, tag)
+		}
+	}
+	t.Tags = tags
+}
+
+// String 生成一行任务，项目、上下文及标签追加在描述之后；已完成任务的优先级写为 pri:A
+func (t *Task) String() string {
+	var b strings.Builder
+	if t.Completed {
+		b.WriteString("x ")
+		if t.CompletionDate != nil {
+			b.WriteString(t.CompletionDate.Format(DateLayout) + " ")
+		}
+	} else if t.Priority != 0 {
+		fmt.Fprintf(&b, "(%c) ", t.Priority)
+	}
+	if t.CreationDate != nil && (!t.Completed || t.CompletionDate != nil) {
+		b.WriteString(t.CreationDate.Format(DateLayout) + " ")
+	}
+	b.WriteString(strings.Join(strings.Fields(t.Text), " "))
+
+	for _, project := range t.Projects {
+		b.WriteString(" +" + project)
+	}
+	for _, context := range t.Contexts {
+		b.WriteString(" @" + context)
+	}
+	if t.Completed && t.Priority != 0 {
+		fmt.Fprintf(&b, " %s:%c", priorityKey, t.Priority)
+	}
+	for _, tag := range t.Tags {
+		b.WriteString(" " + tag.Key + ":" + tag.Value)
+	}
+	return b.String()
+}
+
+// Write 按行写入任务列表
+func Write(w io.Writer, tasks []*Task) error {
+	bw := bufio.NewWriter(w)
+	for _, task := range tasks {
+		if _, err := bw.WriteString(task.String() + "\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Token 将名称转换为可用作 +项目 或 @上下文 的单词，空白替换为下划线
+func Token(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
@@ -0,0 +1,202 @@
+package todotxt
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// formatDate 便于比较可为空的日期
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(DateLayout)
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line       string
+		completed  bool
+		priority   byte
+		completion string
+		creation   string
+		text       string
+		projects   []string
+		contexts   []string
+		tags       []Tag
+	}{
+		{line: "买牛奶", text: "买牛奶"},
+		{line: "(A) 给妈妈打电话", priority: 'A', text: "给妈妈打电话"},
+		{line: "(A) 2026-10-18 写周报 +工作 @电脑 due:2026-10-20",
+			priority: 'A', creation: "2026-10-18", text: "写周报",
+			projects: []string{"工作"}, contexts: []string{"电脑"}, tags: []Tag{{"due", "2026-10-20"}}},
+		{line: "2026-10-18 写周报", creation: "2026-10-18", text: "写周报"},
+		{line: "x 2026-10-19 2026-10-18 写周报", completed: true,
+			completion: "2026-10-19", creation: "2026-10-18", text: "写周报"},
+		{line: "x 2026-10-19 写周报", completed: true, completion: "2026-10-19", text: "写周报"},
+		{line: "x 写周报 pri:B", completed: true, priority: 'B', text: "写周报"},
+		{line: "x (C) 写周报", completed: true, priority: 'C', text: "写周报"},
+		// 未完成任务的 pri 标签只是普通标签，非法的优先级值也保留为标签
+		{line: "写周报 pri:B", text: "写周报", tags: []Tag{{"pri", "B"}}},
+		{line: "x 写周报 pri:high", completed: true, text: "写周报", tags: []Tag{{"pri", "high"}}},
+		// 优先级、日期只在行首有效，x 后必须有空格
+		{line: "写周报 (A) 2026-10-18", text: "写周报 (A) 2026-10-18"},
+		{line: "xylophone 练习", text: "xylophone 练习"},
+		{line: "X 写周报", text: "X 写周报"},
+		{line: "(a) 写周报", text: "(a) 写周报"},
+		{line: "  多个   空格  ", text: "多个 空格"},
+		// 地址、时间及单独的 + @ 不是标签、项目或上下文
+		{line: "查看 https://example.com 10:30 + @ 邮件@example.com",
+			text: "查看 https://example.com 10:30 + @ 邮件@example.com"},
+		{line: "打扫 +家务 +周末 @家 rec:1w t:2026-10-20 rec:2w", text: "打扫",
+			projects: []string{"家务", "周末"}, contexts: []string{"家"},
+			tags: []Tag{{"rec", "1w"}, {"t", "2026-10-20"}, {"rec", "2w"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			task, err := ParseLine(tt.line)
+			if err != nil {
+				t.Fatalf("ParseLine error: %v", err)
+			}
+			if task.Completed != tt.completed || task.Priority != tt.priority {
+				t.Errorf("Completed, Priority = %v, %q, want %v, %q", task.Completed, task.Priority, tt.completed, tt.priority)
+			}
+			if got := formatDate(task.CompletionDate); got != tt.completion {
+				t.Errorf("CompletionDate = %q, want %q", got, tt.completion)
+			}
+			if got := formatDate(task.CreationDate); got != tt.creation {
+				t.Errorf("CreationDate = %q, want %q", got, tt.creation)
+			}
+			if task.Text != tt.text {
+				t.Errorf("Text = %q, want %q", task.Text, tt.text)
+			}
+			if !reflect.DeepEqual(task.Projects, tt.projects) {
+				t.Errorf("Projects = %q, want %q", task.Projects, tt.projects)
+			}
+			if !reflect.DeepEqual(task.Contexts, tt.contexts) {
+				t.Errorf("Contexts = %q, want %q", task.Contexts, tt.contexts)
+			}
+			if len(task.Tags) != 0 || len(tt.tags) != 0 {
+				if !reflect.DeepEqual(task.Tags, tt.tags) {
+					t.Errorf("Tags = %v, want %v", task.Tags, tt.tags)
+				}
+			}
+		})
+	}
+}
+
+func TestParseLineErrors(t *testing.T) {
+	for _, line := range []string{"x", "(A)", "x 2026-10-19 2026-10-18", "+工作 @电脑 due:2026-10-20"} {
+		t.Run(line, func(t *testing.T) {
+			_, err := ParseLine(line)
+			if err == nil || err.Error() != "任务描述为空" {
+				t.Errorf("ParseLine(%q) error = %v, want %q", line, err, "任务描述为空")
+			}
+		})
+	}
+}
+
+func TestTag(t *testing.T) {
+	task, err := ParseLine("写周报 due:2026-10-20 Due:2026-10-21 due:2026-10-22")
+	if err != nil {
+		t.Fatalf("ParseLine error: %v", err)
+	}
+	if value, ok := task.Tag("due"); !ok || value != "2026-10-20" {
+		t.Errorf("Tag(due) = %q, %v, want 第一个值", value, ok)
+	}
+	if value, ok := task.Tag("Due"); !ok || value != "2026-10-21" {
+		t.Errorf("Tag(Due) = %q, %v, 键应当区分大小写", value, ok)
+	}
+	if _, ok := task.Tag("rec"); ok {
+		t.Error("不存在的标签应当返回 false")
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"买牛奶", "买牛奶"},
+		{"(A) 2026-10-18 写周报 +工作 @电脑 due:2026-10-20", "(A) 2026-10-18 写周报 +工作 @电脑 due:2026-10-20"},
+		{"写 +工作 周报 @电脑", "写 周报 +工作 @电脑"},
+		{"x 2026-10-19 2026-10-18 写周报", "x 2026-10-19 2026-10-18 写周报"},
+		{"x (B) 2026-10-19 写周报 +工作", "x 2026-10-19 写周报 +工作 pri:B"},
+		{"x 写周报 pri:B due:2026-10-20", "x 写周报 pri:B due:2026-10-20"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			task, err := ParseLine(tt.line)
+			if err != nil {
+				t.Fatalf("ParseLine error: %v", err)
+			}
+			got := task.String()
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			// 输出再次解析应当得到相同的任务
+			again, err := ParseLine(got)
+			if err != nil {
+				t.Fatalf("ParseLine(%q) error: %v", got, err)
+			}
+			if again.String() != got {
+				t.Errorf("再次生成 = %q, want %q", again.String(), got)
+			}
+		})
+	}
+}
+
+// 已完成但没有完成日期时不能只写创建日期，否则会被当成完成日期
+func TestStringCompletedWithoutCompletionDate(t *testing.T) {
+	created := time.Date(2026, 10, 18, 0, 0, 0, 0, time.Local)
+	task := &Task{Completed: true, CreationDate: &created, Text: "写周报"}
+	if got, want := task.String(), "x 写周报"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := "\ufeff(A) 写周报\r\n\n   \nx\n买牛奶 +家务\n"
+	lines, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("解析出 %d 行，期望 3 行", len(lines))
+	}
+
+	if lines[0].Number != 1 || lines[0].Err != nil || lines[0].Task.Priority != 'A' || lines[0].Task.Text != "写周报" {
+		t.Errorf("第一行解析错误: %+v %+v", lines[0], lines[0].Task)
+	}
+	if lines[1].Number != 4 || lines[1].Err == nil {
+		t.Errorf("第4行应当解析失败: %+v", lines[1])
+	}
+	if lines[2].Number != 5 || lines[2].Err != nil || lines[2].Task.Text != "买牛奶" {
+		t.Errorf("第5行解析错误: %+v %+v", lines[2], lines[2].Task)
+	}
+}
+
+func TestParseLineTooLong(t *testing.T) {
+	_, err := Parse(strings.NewReader(strings.Repeat("a", maxLineLength+1)))
+	if err == nil {
+		t.Error("超长的行应当返回错误")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tasks := []*Task{
+		{Priority: 'A', Text: "写周报", Projects: []string{Token("季度 报告")}},
+		{Text: "买牛奶", Contexts: []string{Token(" 超市 ")}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, tasks); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	want := "(A) 写周报 +季度_报告\n买牛奶 @超市\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Write = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package webdav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// 常用命名空间
+const (
+	NSDAV         = "DAV:"
+	NSCalDAV      = "urn:ietf:params:xml:ns:caldav"
+	NSCalendarSrv = "http://calendarserver.org/ns/"
+)
+
+// maxRequestBody 请求体最大字节数
+const maxRequestBody = 1 << 20
+
+// prefixes 输出时使用的固定前缀，其余命名空间按需声明
+var prefixes = map[string]string{
+	NSDAV:         "d",
+	NSCalDAV:      "c",
+	NSCalendarSrv: "cs",
+}
+
+// Request PROPFIND/REPORT 请求体中关心的内容
+type Request struct {
+	Name     xml.Name   // 根元素，如 DAV: propfind、caldav calendar-multiget
+	AllProp  bool       // 请求全部属性(allprop 或请求体为空)
+	PropName bool       // 只请求属性名
+	Props    []xml.Name // prop 下请求的属性
+	Hrefs    []string   // calendar-multiget 中的 href
+	// CompFilters calendar-query 中 comp-filter 的组件名，如 VCALENDAR、VTODO
+	CompFilters []string
+}
+
+// ParseRequest 解析 PROPFIND/REPORT 请求体，请求体为空时视为 allprop
+func ParseRequest(r io.Reader) (*Request, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxRequestBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxRequestBody {
+		return nil, errors.New("请求体过大")
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &Request{Name: xml.Name{Space: NSDAV, Local: "propfind"}, AllProp: true}, nil
+	}
+
+	req := &Request{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []xml.Name
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("XML格式错误: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth := len(stack)
+			stack = append(stack, t.Name)
+			switch {
+			case depth == 0:
+				req.Name = t.Name
+			case depth == 1 && t.Name == xml.Name{Space: NSDAV, Local: "allprop"}:
+				req.AllProp = true
+			case depth == 1 && t.Name == xml.Name{Space: NSDAV, Local: "propname"}:
+				req.PropName = true
+			case depth == 2 && stack[1] == xml.Name{Space: NSDAV, Local: "prop"}:
+				req.Props = append(req.Props, t.Name)
+			case t.Name == xml.Name{Space: NSCalDAV, Local: "comp-filter"}:
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "name" {
+						req.CompFilters = append(req.CompFilters, strings.ToUpper(attr.Value))
+					}
+				}
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 2 && stack[1] == (xml.Name{Space: NSDAV, Local: "href"}) {
+				req.Hrefs = append(req.Hrefs, strings.TrimSpace(string(t)))
+			}
+		}
+	}
+	if req.Name.Local == "" {
+		return nil, errors.New("XML格式错误: 缺少根元素")
+	}
+	return req, nil
+}
+
+// Property 响应中的属性，Inner 为已编码的子元素 XML，Text 为文本值(输出时转义)
+type Property struct {
+	Name  xml.Name
+	Inner string
+	Text  string
+}
+
+// Response multistatus 中的单个资源
+type Response struct {
+	Href     string
+	Found    []Property
+	NotFound []xml.Name
+	Status   int // 非 0 时表示整个资源的状态，如 404，忽略属性
+}
+
+// Element 生成空元素，如 <d:collection/>
+func Element(space, local string) string {
+	prefix, declare := prefixFor(space, 0)
+	return "<" + prefix + local + declare + "/>"
+}
+
+// HrefElement 生成 <d:href>，href 需已做路径编码
+func HrefElement(href string) string {
+	return "<d:href>" + escape(href) + "</d:href>"
+}
+
+// WriteMultiStatus 输出 207 Multi-Status
+func WriteMultiStatus(w http.ResponseWriter, responses []Response) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">`)
+	for _, resp := range responses {
+		buf.WriteString("<d:response>")
+		buf.WriteString(HrefElement(resp.Href))
+		if resp.Status != 0 {
+			buf.WriteString(statusElement(resp.Status))
+		} else {
+			if len(resp.Found) > 0 || len(resp.NotFound) == 0 {
+				buf.WriteString("<d:propstat><d:prop>")
+				for i, prop := range resp.Found {
+					writeProperty(&buf, prop, i)
+				}
+				buf.WriteString("</d:prop>" + statusElement(http.StatusOK) + "</d:propstat>")
+			}
+			if len(resp.NotFound) > 0 {
+				buf.WriteString("<d:propstat><d:prop>")
+				for i, name := range resp.NotFound {
+					writeProperty(&buf, Property{Name: name}, i)
+				}
+				buf.WriteString("</d:prop>" + statusElement(http.StatusNotFound) + "</d:propstat>")
+			}
+		}
+		buf.WriteString("</d:response>")
+	}
+	buf.WriteString("</d:multistatus>")
+
+	w.Header().Set("Content-Type", `application/xml; charset="utf-8"`)
+	w.WriteHeader(http.StatusMultiStatus)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteError 输出带 DAV:error 前置条件的错误，如 CalDAV 的 supported-calendar-component
+func WriteError(w http.ResponseWriter, status int, condition xml.Name, message string) {
+	prefix, declare := prefixFor(condition.Space, 0)
+	body := xml.Header + `<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">` +
+		"<" + prefix + condition.Local + declare + "/>" +
+		"<d:responsedescription>" + escape(message) + "</d:responsedescription></d:error>"
+	w.Header().Set("Content-Type", `application/xml; charset="utf-8"`)
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+func writeProperty(buf *bytes.Buffer, prop Property, index int) {
+	prefix, declare := prefixFor(prop.Name.Space, index)
+	if prop.Inner == "" && prop.Text == "" {
+		buf.WriteString("<" + prefix + prop.Name.Local + declare + "/>")
+		return
+	}
+	buf.WriteString("<" + prefix + prop.Name.Local + declare + ">")
+	buf.WriteString(prop.Inner)
+	buf.WriteString(escape(prop.Text))
+	buf.WriteString("</" + prefix + prop.Name.Local + ">")
+}
+
+// prefixFor 返回命名空间前缀(含冒号)，未知命名空间在元素上就地声明
+func prefixFor(space string, index int) (prefix, declare string) {
+	if p, ok := prefixes[space]; ok {
+		return p + ":", ""
+	}
+	if space == "" {
+		return "", ""
+	}
+	p := fmt.Sprintf("x%d", index)
+	return p + ":", ` xmlns:` + p + `="` + escape(space) + `"`
+}
+
+func statusElement(code int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+func escape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
@@ -19,8 +19,17 @@ SET NAMES utf8mb4;
 SET FOREIGN_KEY_CHECKS = 0;
 
 -- 1. 删除已存在的表（按依赖关系逆序）
+DROP TABLE IF EXISTS `todo_dependencies`;
+DROP TABLE IF EXISTS `workflow_statuses`;
+DROP TABLE IF EXISTS `app_passwords`;
+DROP TABLE IF EXISTS `todo_history`;
+DROP TABLE IF EXISTS `attachments`;
+DROP TABLE IF EXISTS `comments`;
+DROP TABLE IF EXISTS `shares`;
+DROP TABLE IF EXISTS `reminders`;
 DROP TABLE IF EXISTS `todo_tags`;
 DROP TABLE IF EXISTS `todos`;
+DROP TABLE IF EXISTS `projects`;
 DROP TABLE IF EXISTS `tags`;
 DROP TABLE IF EXISTS `users`;
 
@@ -32,41 +41,92 @@ CREATE TABLE `users` (
                          `password_hash` VARCHAR(255) NOT NULL COMMENT '密码哈希',
                          `avatar_url` VARCHAR(255) DEFAULT NULL COMMENT '头像URL',
                          `status` TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '状态: 0-禁用, 1-正常',
+                         `calendar_token_hash` CHAR(64) DEFAULT NULL COMMENT '日历订阅令牌的SHA-256哈希',
+                         `version` INT UNSIGNED NOT NULL DEFAULT 1 COMMENT '版本号，每次更新加1，用于乐观锁',
                          `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
                          `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
                          `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT '软删除时间',
                          PRIMARY KEY (`id`),
                          UNIQUE KEY `uk_username` (`username`) COMMENT '用户名唯一索引',
                          UNIQUE KEY `uk_email` (`email`) COMMENT '邮箱唯一索引',
+                         UNIQUE KEY `uk_calendar_token_hash` (`calendar_token_hash`) COMMENT '日历订阅令牌唯一索引',
                          KEY `idx_deleted_at` (`deleted_at`) COMMENT '软删除查询索引'
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='用户表';
 
--- 3. 创建待办事项表 (todos)
+-- 3. 创建项目表 (projects)
+CREATE TABLE `projects` (
+                            `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '项目ID',
+                            `user_id` INT UNSIGNED NOT NULL COMMENT '用户ID',
+                            `name` VARCHAR(100) NOT NULL COMMENT '项目名称',
+                            `description` VARCHAR(500) DEFAULT NULL COMMENT '项目描述',
+                            `color` VARCHAR(7) NOT NULL DEFAULT '#1890ff' COMMENT '项目颜色',
+                            `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                            `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+                            `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT '软删除时间',
+                            PRIMARY KEY (`id`),
+                            KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+                            KEY `idx_deleted_at` (`deleted_at`) COMMENT '软删除查询索引',
+                            CONSTRAINT `fk_projects_user_id` FOREIGN KEY (`user_id`)
+                                REFERENCES `users` (`id`)
+                                ON DELETE CASCADE
+                                ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='项目表';
+
+-- 4. 创建待办事项表 (todos)
 CREATE TABLE `todos` (
                          `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '待办事项ID',
                          `user_id` INT UNSIGNED NOT NULL COMMENT '用户ID',
+                         `project_id` INT UNSIGNED DEFAULT NULL COMMENT '项目ID，为空表示收件箱',
+                         `parent_id` INT UNSIGNED DEFAULT NULL COMMENT '父任务ID，为空表示顶层任务',
+                         `position` INT NOT NULL DEFAULT 0 COMMENT '子任务排序位置',
+                         `board_rank` VARCHAR(64) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' COMMENT '看板排序键，按字典序排列，只有顶层任务有值',
                          `title` VARCHAR(200) NOT NULL COMMENT '标题',
                          `description` TEXT COMMENT '描述',
                          `status` TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '状态: 0-待办, 1-进行中, 2-已完成',
+                         `status_id` INT UNSIGNED DEFAULT NULL COMMENT '自定义状态ID，必须属于 status 分类',
                          `priority` TINYINT UNSIGNED NOT NULL DEFAULT 1 COMMENT '优先级: 1-低, 2-中, 3-高, 4-紧急',
                          `due_date` DATETIME DEFAULT NULL COMMENT '截止时间',
+                         `recurrence` VARCHAR(255) DEFAULT NULL COMMENT '重复规则(RRULE)',
+                         `ical_uid` VARCHAR(255) DEFAULT NULL COMMENT '从日历导入时的原始UID',
+                         `caldav_name` VARCHAR(255) DEFAULT NULL COMMENT 'CalDAV 客户端创建时指定的资源名',
                          `completed_at` DATETIME DEFAULT NULL COMMENT '完成时间',
+                         `version` INT UNSIGNED NOT NULL DEFAULT 1 COMMENT '版本号，每次更新加1，用于乐观锁',
                          `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
                          `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
                          `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT '软删除时间',
                          PRIMARY KEY (`id`),
                          KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+                         KEY `idx_project_id` (`project_id`) COMMENT '项目ID索引',
+                         KEY `idx_parent_id` (`parent_id`) COMMENT '父任务ID索引',
                          KEY `idx_status` (`status`) COMMENT '状态索引',
+                         KEY `idx_status_id` (`status_id`) COMMENT '自定义状态索引',
                          KEY `idx_priority` (`priority`) COMMENT '优先级索引',
                          KEY `idx_due_date` (`due_date`) COMMENT '截止时间索引',
                          KEY `idx_deleted_at` (`deleted_at`) COMMENT '软删除查询索引',
+                         KEY `idx_user_ical_uid` (`user_id`, `ical_uid`) COMMENT '日历UID索引，用于导入去重',
+                         KEY `idx_user_caldav_name` (`user_id`, `caldav_name`) COMMENT 'CalDAV资源名索引',
+                         KEY `idx_user_status_rank` (`user_id`, `status`, `board_rank`) COMMENT '看板列排序索引',
+                         FULLTEXT KEY `ft_title` (`title`) WITH PARSER ngram COMMENT '标题全文索引，用于提高标题匹配的权重',
+                         FULLTEXT KEY `ft_content` (`title`, `description`) WITH PARSER ngram COMMENT '标题与描述全文索引',
                          CONSTRAINT `fk_todos_user_id` FOREIGN KEY (`user_id`)
                              REFERENCES `users` (`id`)
                              ON DELETE CASCADE
+                             ON UPDATE CASCADE,
+                         CONSTRAINT `fk_todos_project_id` FOREIGN KEY (`project_id`)
+                             REFERENCES `projects` (`id`)
+                             ON DELETE SET NULL
+                             ON UPDATE CASCADE,
+                         CONSTRAINT `fk_todos_parent_id` FOREIGN KEY (`parent_id`)
+                             REFERENCES `todos` (`id`)
+                             ON DELETE CASCADE
+                             ON UPDATE CASCADE,
+                         CONSTRAINT `fk_todos_status_id` FOREIGN KEY (`status_id`)
+                             REFERENCES `workflow_statuses` (`id`)
+                             ON DELETE SET NULL
                              ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='待办事项表';
 
--- 4. 创建标签表 (tags)
+-- 5. 创建标签表 (tags)
 CREATE TABLE `tags` (
                         `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '标签ID',
                         `name` VARCHAR(50) NOT NULL COMMENT '标签名称',
@@ -83,7 +143,7 @@ CREATE TABLE `tags` (
                             ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='标签表';
 
--- 5. 创建待办事项-标签关联表 (todo_tags)
+-- 6. 创建待办事项-标签关联表 (todo_tags)
 CREATE TABLE `todo_tags` (
                              `todo_id` INT UNSIGNED NOT NULL COMMENT '待办事项ID',
                              `tag_id` INT UNSIGNED NOT NULL COMMENT '标签ID',
@@ -100,5 +160,185 @@ CREATE TABLE `todo_tags` (
                                  ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='待办事项标签关联表';
 
--- 6. 重新启用外键约束
+-- 7. 创建提醒表 (reminders)
+CREATE TABLE `reminders` (
+                             `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '提醒ID',
+                             `todo_id` INT UNSIGNED NOT NULL COMMENT '待办事项ID',
+                             `user_id` INT UNSIGNED NOT NULL COMMENT '用户ID',
+                             `remind_at` DATETIME NOT NULL COMMENT '提醒时间',
+                             `offset_minutes` INT DEFAULT NULL COMMENT '截止时间前的分钟数，为空表示绝对时间',
+                             `sent_at` DATETIME DEFAULT NULL COMMENT '发送时间',
+                             `attempts` INT NOT NULL DEFAULT 0 COMMENT '发送失败的次数',
+                             `next_attempt_at` DATETIME DEFAULT NULL COMMENT '发送失败后下次重试的时间',
+                             `failed_at` DATETIME DEFAULT NULL COMMENT '多次发送失败后放弃的时间',
+                             `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                             `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+                             PRIMARY KEY (`id`),
+                             KEY `idx_todo_id` (`todo_id`) COMMENT '待办事项ID索引',
+                             KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+                             KEY `idx_pending` (`sent_at`, `remind_at`) COMMENT '待发送提醒扫描索引',
+                             CONSTRAINT `fk_reminders_todo_id` FOREIGN KEY (`todo_id`)
+                                 REFERENCES `todos` (`id`)
+                                 ON DELETE CASCADE
+                                 ON UPDATE CASCADE,
+                             CONSTRAINT `fk_reminders_user_id` FOREIGN KEY (`user_id`)
+                                 REFERENCES `users` (`id`)
+                                 ON DELETE CASCADE
+                                 ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='待办事项提醒表';
+
+-- 8. 创建共享表 (shares)
+CREATE TABLE `shares` (
+                          `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '共享ID',
+                          `resource_type` VARCHAR(20) NOT NULL COMMENT '资源类型: todo, project',
+                          `resource_id` INT UNSIGNED NOT NULL COMMENT '资源ID',
+                          `owner_id` INT UNSIGNED NOT NULL COMMENT '资源所有者ID',
+                          `user_id` INT UNSIGNED NOT NULL COMMENT '被共享的用户ID',
+                          `role` VARCHAR(20) NOT NULL COMMENT '角色: viewer-只读, editor-可编辑, owner-可删除及管理共享',
+                          `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                          `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+                          PRIMARY KEY (`id`),
+                          UNIQUE KEY `uk_resource_user` (`resource_type`, `resource_id`, `user_id`) COMMENT '同一资源对同一用户只共享一次',
+                          KEY `idx_owner_id` (`owner_id`) COMMENT '所有者ID索引',
+                          KEY `idx_user_id` (`user_id`) COMMENT '被共享用户ID索引',
+                          CONSTRAINT `fk_shares_owner_id` FOREIGN KEY (`owner_id`)
+                              REFERENCES `users` (`id`)
+                              ON DELETE CASCADE
+                              ON UPDATE CASCADE,
+                          CONSTRAINT `fk_shares_user_id` FOREIGN KEY (`user_id`)
+                              REFERENCES `users` (`id`)
+                              ON DELETE CASCADE
+                              ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='共享表';
+
+-- 9. 创建评论表 (comments)
+CREATE TABLE `comments` (
+                            `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '评论ID',
+                            `todo_id` INT UNSIGNED NOT NULL COMMENT '待办事项ID',
+                            `user_id` INT UNSIGNED NOT NULL COMMENT '评论作者ID',
+                            `body` TEXT NOT NULL COMMENT '评论内容',
+                            `edited_at` DATETIME DEFAULT NULL COMMENT '最后编辑时间',
+                            `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                            `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+                            `deleted_at` TIMESTAMP NULL DEFAULT NULL COMMENT '软删除时间',
+                            PRIMARY KEY (`id`),
+                            KEY `idx_todo_created` (`todo_id`, `created_at`) COMMENT '待办事项评论分页索引',
+                            KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+                            KEY `idx_deleted_at` (`deleted_at`) COMMENT '软删除查询索引',
+                            CONSTRAINT `fk_comments_todo_id` FOREIGN KEY (`todo_id`)
+                                REFERENCES `todos` (`id`)
+                                ON DELETE CASCADE
+                                ON UPDATE CASCADE,
+                            CONSTRAINT `fk_comments_user_id` FOREIGN KEY (`user_id`)
+                                REFERENCES `users` (`id`)
+                                ON DELETE CASCADE
+                                ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='待办事项评论表';
+
+-- 10. 创建附件表 (attachments)
+CREATE TABLE `attachments` (
+                               `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '附件ID',
+                               `todo_id` INT UNSIGNED NOT NULL COMMENT '待办事项ID',
+                               `user_id` INT UNSIGNED NOT NULL COMMENT '上传者ID',
+                               `file_name` VARCHAR(255) NOT NULL COMMENT '原始文件名',
+                               `content_type` VARCHAR(100) NOT NULL COMMENT 'MIME类型',
+                               `size` BIGINT NOT NULL COMMENT '文件大小(字节)',
+                               `storage_key` VARCHAR(255) NOT NULL COMMENT '存储后端对象键',
+                               `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                               PRIMARY KEY (`id`),
+                               UNIQUE KEY `uk_storage_key` (`storage_key`) COMMENT '对象键唯一索引',
+                               KEY `idx_todo_id` (`todo_id`) COMMENT '待办事项ID索引',
+                               KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+                               CONSTRAINT `fk_attachments_todo_id` FOREIGN KEY (`todo_id`)
+                                   REFERENCES `todos` (`id`)
+                                   ON DELETE CASCADE
+                                   ON UPDATE CASCADE,
+                               CONSTRAINT `fk_attachments_user_id` FOREIGN KEY (`user_id`)
+                                   REFERENCES `users` (`id`)
+                                   ON DELETE CASCADE
+                                   ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='待办事项附件表';
+
+-- 11. 创建待办事项变更记录表 (todo_history)
+CREATE TABLE `todo_history` (
+                                `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '记录ID',
+                                `todo_id` INT UNSIGNED NOT NULL COMMENT '待办事项ID',
+                                `user_id` INT UNSIGNED NOT NULL COMMENT '操作人ID',
+                                `action` VARCHAR(20) NOT NULL COMMENT '动作: created, updated, deleted',
+                                `field` VARCHAR(50) DEFAULT NULL COMMENT '变更字段，仅 updated 动作有值',
+                                `old_value` TEXT COMMENT '修改前的值',
+                                `new_value` TEXT COMMENT '修改后的值',
+                                `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                                PRIMARY KEY (`id`),
+                                KEY `idx_todo_created` (`todo_id`, `created_at`) COMMENT '待办事项变更记录查询索引',
+                                KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+                                CONSTRAINT `fk_todo_history_todo_id` FOREIGN KEY (`todo_id`)
+                                    REFERENCES `todos` (`id`)
+                                    ON DELETE CASCADE
+                                    ON UPDATE CASCADE,
+                                CONSTRAINT `fk_todo_history_user_id` FOREIGN KEY (`user_id`)
+                                    REFERENCES `users` (`id`)
+                                    ON DELETE CASCADE
+                                    ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='待办事项变更记录表';
+
+-- 12. 创建应用专用密码表 (app_passwords)
+CREATE TABLE `app_passwords` (
+                                 `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '应用密码ID',
+                                 `user_id` INT UNSIGNED NOT NULL COMMENT '用户ID',
+                                 `name` VARCHAR(50) NOT NULL COMMENT '名称，用于区分设备或客户端',
+                                 `password_hash` CHAR(64) NOT NULL COMMENT '密码的SHA-256哈希',
+                                 `last_used_at` DATETIME DEFAULT NULL COMMENT '最近使用时间',
+                                 `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                                 PRIMARY KEY (`id`),
+                                 UNIQUE KEY `uk_password_hash` (`password_hash`) COMMENT '密码哈希唯一索引',
+                                 KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引',
+                                 CONSTRAINT `fk_app_passwords_user_id` FOREIGN KEY (`user_id`)
+                                     REFERENCES `users` (`id`)
+                                     ON DELETE CASCADE
+                                     ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='应用专用密码表';
+
+-- 13. 创建待办事项依赖表 (todo_dependencies)
+CREATE TABLE `todo_dependencies` (
+                                     `todo_id` INT UNSIGNED NOT NULL COMMENT '被阻塞的待办事项ID',
+                                     `blocker_id` INT UNSIGNED NOT NULL COMMENT '前置待办事项ID，完成前阻塞 todo_id',
+                                     `user_id` INT UNSIGNED NOT NULL COMMENT '所有者ID',
+                                     `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                                     PRIMARY KEY (`todo_id`, `blocker_id`),
+                                     KEY `idx_blocker_id` (`blocker_id`) COMMENT '前置任务索引，用于查询被阻塞的任务',
+                                     KEY `idx_user_id` (`user_id`) COMMENT '用户ID索引，用于环检测',
+                                     CONSTRAINT `fk_todo_dependencies_todo_id` FOREIGN KEY (`todo_id`)
+                                         REFERENCES `todos` (`id`)
+                                         ON DELETE CASCADE
+                                         ON UPDATE CASCADE,
+                                     CONSTRAINT `fk_todo_dependencies_blocker_id` FOREIGN KEY (`blocker_id`)
+                                         REFERENCES `todos` (`id`)
+                                         ON DELETE CASCADE
+                                         ON UPDATE CASCADE,
+                                     CONSTRAINT `fk_todo_dependencies_user_id` FOREIGN KEY (`user_id`)
+                                         REFERENCES `users` (`id`)
+                                         ON DELETE CASCADE
+                                         ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='待办事项依赖表';
+
+-- 14. 创建自定义状态表 (workflow_statuses)
+CREATE TABLE `workflow_statuses` (
+                                     `id` INT UNSIGNED NOT NULL AUTO_INCREMENT COMMENT '自定义状态ID',
+                                     `user_id` INT UNSIGNED NOT NULL COMMENT '用户ID',
+                                     `name` VARCHAR(50) NOT NULL COMMENT '状态名称',
+                                     `category` TINYINT UNSIGNED NOT NULL COMMENT '所属分类: 0-待办, 1-进行中, 2-已完成',
+                                     `color` VARCHAR(7) NOT NULL DEFAULT '#8c8c8c' COMMENT '状态颜色',
+                                     `position` INT NOT NULL DEFAULT 0 COMMENT '同一分类内的排序位置',
+                                     `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+                                     `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+                                     PRIMARY KEY (`id`),
+                                     UNIQUE KEY `uk_user_name` (`user_id`, `name`) COMMENT '同一用户下状态名唯一',
+                                     CONSTRAINT `fk_workflow_statuses_user_id` FOREIGN KEY (`user_id`)
+                                         REFERENCES `users` (`id`)
+                                         ON DELETE CASCADE
+                                         ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='自定义状态表';
+
+-- 15. 重新启用外键约束
 SET FOREIGN_KEY_CHECKS = 1;
\ No newline at end of file